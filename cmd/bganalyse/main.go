@@ -0,0 +1,106 @@
+// Command bganalyse checks the plays and cube actions of match files and
+// writes them back as gnubg SGF with their analysis. It reads gnubg .sgf
+// files and Jellyfish .mat or .txt files.
+//
+//	bganalyse --eval plies=2 --out analysed.sgf match.mat
+//	bganalyse --out-dir analysed archive/*.mat
+//	bganalyse --check archive/*.mat
+//
+// See tournament.ParsePlayer for the configuration syntax of --eval.
+package main
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/match"
+	"bgweb-api/internal/tournament"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	var datadir = flag.String("datadir", "./cmd/bgweb-api/data", "Folder containing gnubg data")
+	var eval = flag.String("eval", "plies=0", "Engine configuration of the analysis")
+	var out = flag.String("out", "-", "SGF file to write, - for stdout, with a single match file")
+	var outDir = flag.String("out-dir", "", "Folder to write an SGF file per match file to")
+	var check = flag.Bool("check", false, "Only check the match files, reporting those in error")
+	flag.Parse()
+
+	if flag.NArg() == 0 || (flag.NArg() > 1 && *outDir == "" && !*check) {
+		fmt.Fprintln(os.Stderr, "usage: bganalyse [flags] match.sgf|match.mat, or with --out-dir or --check, several")
+		os.Exit(2)
+	}
+
+	if *check {
+		if n := checkFiles(flag.Args()); n > 0 {
+			fmt.Fprintf(os.Stderr, "%v of %v files in error\n", n, flag.NArg())
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := run(*datadir, *eval, flag.Args(), *out, *outDir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func checkFiles(files []string) int {
+	var n int
+	for _, file := range files {
+		if _, err := match.ReadFile(file); err != nil {
+			fmt.Println(err)
+			n++
+		}
+	}
+	return n
+}
+
+func run(datadir string, eval string, files []string, out string, outDir string) error {
+	if err := gnubg.Init(os.DirFS(datadir)); err != nil {
+		return fmt.Errorf("failed to initialize gnubg: %w", err)
+	}
+
+	p, err := tournament.ParsePlayer(eval)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		m, err := match.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if err := match.Analyse(&m, p.Settings); err != nil {
+			return fmt.Errorf("%v: %w", file, err)
+		}
+
+		var path = out
+		if outDir != "" {
+			path = filepath.Join(outDir, strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))+".sgf")
+		}
+		if err := writeMatch(path, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeMatch(path string, m match.Match) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return match.WriteSGF(w, m)
+}
@@ -0,0 +1,152 @@
+// Command bgdatagen writes training data: positions from self-play games,
+// read from a file or sampled from match files, with their key, class, net
+// inputs, evaluations and optionally rollout results.
+//
+//	bgdatagen --games 1000 --play plies=0,noise=0.05 --plies 0,2 --format binary --out data.bin
+//	bgdatagen --positions positions.jsonl --rollout-trials 1296 --out data.jsonl
+//	bgdatagen --matches 'archive/*.mat' --sample 0.1 --plies 2 --out data.jsonl
+//
+// See tournament.ParsePlayer for the configuration syntax of --play, --eval
+// and --rollout, and datagen for the output formats.
+package main
+
+import (
+	"bgweb-api/internal/datagen"
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/tournament"
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+	var datadir = flag.String("datadir", "./cmd/bgweb-api/data", "Folder containing gnubg data")
+	var games = flag.Int("games", 100, "Self-play games")
+	var positions = flag.String("positions", "", "JSON lines file of positions to use instead of self-play")
+	var matches = flag.String("matches", "", "Glob pattern of .sgf, .mat or .txt match files whose positions to use instead of self-play")
+	var play = flag.String("play", "plies=0,noise=0.02", "Engine configuration of the self-play games")
+	var eval = flag.String("eval", "", "Engine configuration of the evaluations, its plies aside")
+	var plies = flag.String("plies", "0", "Comma separated plies to evaluate every position at")
+	var rolloutTrials = flag.Int("rollout-trials", 0, "Roll every position out this many times, 0 for no rollouts")
+	var rollout = flag.String("rollout", "plies=0", "Engine configuration of the rollouts")
+	var sample = flag.Float64("sample", 0, "Chance of keeping each position, 0 to keep all")
+	var seed = flag.Int64("seed", 0, "Random seed, 0 for the current time")
+	var parallel = flag.Int("parallel", runtime.NumCPU(), "Games worked on at the same time")
+	var format = flag.String("format", "json", "Output format, json or binary")
+	var out = flag.String("out", "-", "File to write, - for stdout")
+	flag.Parse()
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	cfg := datagen.Config{
+		RolloutTrials: *rolloutTrials,
+		Sample:        *sample,
+		Seed:          *seed,
+		Parallel:      *parallel,
+	}
+
+	if err := run(*datadir, *games, *positions, *matches, *play, *eval, *plies, *rollout, *format, *out, cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(datadir string, games int, positions string, matches string, play string, eval string, plies string, rollout string, format string, out string, cfg datagen.Config) error {
+	if err := gnubg.Init(os.DirFS(datadir)); err != nil {
+		return fmt.Errorf("failed to initialize gnubg: %w", err)
+	}
+
+	for _, s := range strings.Split(plies, ",") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid plies: %v", err)
+		}
+		cfg.Plies = append(cfg.Plies, n)
+	}
+
+	p, err := tournament.ParsePlayer(eval)
+	if err != nil {
+		return err
+	}
+	cfg.Eval = p.Settings
+
+	if p, err = tournament.ParsePlayer(rollout); err != nil {
+		return err
+	}
+	cfg.Rollout = p.Settings
+
+	var src datagen.Source
+	switch {
+	case positions != "":
+		f, err := os.Open(positions)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		src = datagen.NewPositions(f)
+	case matches != "":
+		files, err := filepath.Glob(matches)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no match files: %v", matches)
+		}
+		src = &datagen.MatchFiles{Files: files}
+	default:
+		if p, err = tournament.ParsePlayer(play); err != nil {
+			return err
+		}
+		src = &datagen.SelfPlay{Games: games, Settings: p.Settings, Rand: rand.New(rand.NewSource(cfg.Seed))}
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	var write func(datagen.Record) error
+	switch format {
+	case "json":
+		write = datagen.NewJSONWriter(bw)
+	case "binary":
+		w, err := datagen.NewBinaryWriter(bw)
+		if err != nil {
+			return err
+		}
+		write = w.Write
+	default:
+		return fmt.Errorf("unknown format: %v", format)
+	}
+
+	var n int
+	err = datagen.Generate(src, cfg, func(r datagen.Record) error {
+		n++
+		if n%1000 == 0 {
+			fmt.Fprintf(os.Stderr, "\r%v positions", n)
+		}
+		return write(r)
+	})
+	fmt.Fprintf(os.Stderr, "\r%v positions\n", n)
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,69 @@
+// Command bgexternal plays as an external player of GNU Backgammon: it
+// listens on a TCP socket, reads FIBS board: strings and answers each with
+// a play or cube action.
+//
+//	bgexternal --listen localhost:5000 --eval plies=2
+//
+// In gnubg, seat it with "set player 0 external localhost:5000". Each
+// connection is a separate session. See tournament.ParsePlayer for the
+// configuration syntax of --eval.
+package main
+
+import (
+	"bgweb-api/internal/fibs"
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/tournament"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+)
+
+func main() {
+	var datadir = flag.String("datadir", "./cmd/bgweb-api/data", "Folder containing gnubg data")
+	var listen = flag.String("listen", "localhost:5000", "Address to listen on")
+	var eval = flag.String("eval", "plies=0", "Engine configuration of the player")
+	flag.Parse()
+
+	if err := run(*datadir, *listen, *eval); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(datadir string, listen string, eval string) error {
+	if err := gnubg.Init(os.DirFS(datadir)); err != nil {
+		return fmt.Errorf("failed to initialize gnubg: %w", err)
+	}
+
+	p, err := tournament.ParsePlayer(eval)
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", listen)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	log.Printf("playing %v on %v", p.Name, l.Addr())
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+			log.Printf("%v: connected", conn.RemoteAddr())
+			if err := fibs.ServeExternal(conn, p.Settings); err != nil {
+				log.Printf("%v: %v", conn.RemoteAddr(), err)
+				return
+			}
+			log.Printf("%v: closed", conn.RemoteAddr())
+		}()
+	}
+}
@@ -0,0 +1,93 @@
+// Command bgfibsbot logs in to a FIBS server as a bot and plays matches
+// with whoever invites it, until interrupted.
+//
+//	FIBS_PASSWORD=... bgfibsbot --addr fibs.com:4321 --user mybot --eval plies=1,noise=0.02
+//
+// With --fake it plays a match against a local fake server instead, to
+// try the bot out. See tournament.ParsePlayer for the configuration syntax
+// of --eval.
+package main
+
+import (
+	"bgweb-api/internal/fibs"
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/tournament"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	var datadir = flag.String("datadir", "./cmd/bgweb-api/data", "Folder containing gnubg data")
+	var addr = flag.String("addr", "fibs.com:4321", "Address of the FIBS server")
+	var user = flag.String("user", "", "User to log in as")
+	var password = flag.String("password", os.Getenv("FIBS_PASSWORD"), "Password to log in with, $FIBS_PASSWORD by default")
+	var client = flag.String("client", "bgweb-api", "Client name to log in with")
+	var eval = flag.String("eval", "plies=0", "Engine configuration of the bot")
+	var reconnect = flag.Duration("reconnect", 30*time.Second, "Wait before reconnecting, 0 to stop when disconnected")
+	var fake = flag.Int("fake", 0, "Play a match of this length against a local fake server")
+	flag.Parse()
+
+	if *user == "" {
+		fmt.Fprintln(os.Stderr, "usage: bgfibsbot --user name [flags]")
+		os.Exit(2)
+	}
+
+	if err := run(*datadir, *eval, *fake, fibs.BotConfig{
+		Addr:      *addr,
+		User:      *user,
+		Password:  *password,
+		Client:    *client,
+		Reconnect: *reconnect,
+		Log:       log.Default(),
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(datadir string, eval string, fake int, cfg fibs.BotConfig) error {
+	if err := gnubg.Init(os.DirFS(datadir)); err != nil {
+		return fmt.Errorf("failed to initialize gnubg: %w", err)
+	}
+
+	p, err := tournament.ParsePlayer(eval)
+	if err != nil {
+		return err
+	}
+	cfg.Settings = p.Settings
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if fake > 0 {
+		l, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+
+		var results = make(chan fibs.MatchResult, 1)
+		var s = fibs.FakeServer{Opponent: "gnubg", Settings: p.Settings, MatchTo: fake, Seed: time.Now().UnixNano(), Results: results}
+		go s.Serve(l)
+		cfg.Addr = l.Addr().String()
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		go func() {
+			r := <-results
+			log.Printf("%v %v-%v gnubg", r.User, r.Score[0], r.Score[1])
+			cancel()
+		}()
+	}
+
+	if err := fibs.RunBot(ctx, cfg); err != nil && err != context.Canceled {
+		return err
+	}
+	return nil
+}
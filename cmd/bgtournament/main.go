@@ -0,0 +1,132 @@
+// Command bgtournament plays two engine configurations against each other
+// and reports which one plays better.
+//
+//	bgtournament --a plies=0 --b plies=1 --games 1000 --duplicate --reference plies=2 --json result.json
+//
+// See tournament.ParsePlayer for the configuration syntax.
+package main
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/tournament"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"text/tabwriter"
+	"time"
+)
+
+func main() {
+	var datadir = flag.String("datadir", "./cmd/bgweb-api/data", "Folder containing gnubg data")
+	var a = flag.String("a", "plies=0", "First engine configuration")
+	var b = flag.String("b", "plies=1", "Second engine configuration")
+	var reference = flag.String("reference", "", "Configuration judging both players' decisions, empty for no error rates")
+	var games = flag.Int("games", 100, "Money games, or matches with --match-to")
+	var matchTo = flag.Int("match-to", 0, "Match length, 0 for money games")
+	var jacoby = flag.Bool("jacoby", false, "Jacoby rule in money games")
+	var duplicate = flag.Bool("duplicate", true, "Play every game twice with the same dice, swapping seats")
+	var seed = flag.Int64("seed", 0, "Random seed, 0 for the current time")
+	var parallel = flag.Int("parallel", runtime.NumCPU(), "Games played at the same time")
+	var jsonFile = flag.String("json", "", "Write the full result as JSON to this file, - for stdout")
+	flag.Parse()
+
+	if err := run(*datadir, *a, *b, *reference, *jsonFile, tournament.Config{
+		Games:     *games,
+		MatchTo:   *matchTo,
+		Jacoby:    *jacoby,
+		Seed:      *seed,
+		Duplicate: *duplicate,
+		Parallel:  *parallel,
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(datadir string, a string, b string, reference string, jsonFile string, cfg tournament.Config) error {
+	if err := gnubg.Init(os.DirFS(datadir)); err != nil {
+		return fmt.Errorf("failed to initialize gnubg: %w", err)
+	}
+
+	var players [2]tournament.Player
+	var err error
+	if players[0], err = tournament.ParsePlayer(a); err != nil {
+		return err
+	}
+	if players[1], err = tournament.ParsePlayer(b); err != nil {
+		return err
+	}
+
+	if reference != "" {
+		ref, err := tournament.ParsePlayer(reference)
+		if err != nil {
+			return err
+		}
+		cfg.Reference = &ref.Settings
+	}
+
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+
+	cfg.Progress = func(done int) {
+		fmt.Fprintf(os.Stderr, "\r%v/%v", done, cfg.Games)
+	}
+
+	result, err := tournament.Run(players, cfg)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	report(os.Stdout, result)
+
+	switch jsonFile {
+	case "":
+		return nil
+	case "-":
+		return writeJSON(os.Stdout, result)
+	default:
+		f, err := os.Create(jsonFile)
+		if err != nil {
+			return err
+		}
+		if err := writeJSON(f, result); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+}
+
+func writeJSON(w io.Writer, result tournament.Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+func report(w io.Writer, r tournament.Result) {
+	var unit = "points per game"
+	if r.MatchTo > 0 {
+		unit = fmt.Sprintf("points per %v-point match", r.MatchTo)
+	}
+
+	fmt.Fprintf(w, "%v vs %v, %v games, seed %v\n", r.Players[0], r.Players[1], r.Games, r.Seed)
+	fmt.Fprintf(w, "%+.3f %v for %v (± %.3f, 95%% CI %+.3f to %+.3f)\n\n", r.PointsPerGame, unit, r.Players[0], r.StdErr, r.CI95[0], r.CI95[1])
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "player\twins\tgammons\tbackgammons\terror rate\tchequer\tcube\t")
+	for i, name := range r.Players {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t", name, r.Wins[i], r.Gammons[i], r.Backgammons[i])
+		if r.Errors != nil {
+			e := r.Errors[i]
+			fmt.Fprintf(tw, "%.1f\t%v / %.2f\t%v / %.2f\t\n", e.Rate, e.ChequerDecisions, e.ChequerLoss, e.CubeDecisions, e.CubeLoss)
+		} else {
+			fmt.Fprintln(tw, "-\t-\t-\t")
+		}
+	}
+	tw.Flush()
+}
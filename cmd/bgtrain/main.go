@@ -0,0 +1,219 @@
+// Command bgtrain trains gnubg neural nets and writes them as a
+// gnubg.weights file the server can load.
+//
+//	bgtrain supervised --data rollouts.jsonl [--benchmark bench.jsonl | --holdout 0.1] --epochs 10
+//	bgtrain td --games 100000 --lambda 0.7 [--benchmark bench.jsonl]
+//
+// Training starts from the nets in --init, from random nets when --init is
+// empty, or from the latest checkpoint in --checkpoint-dir with --resume.
+package main
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/train"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+)
+
+type common struct {
+	datadir         *string
+	init            *string
+	hidden          *int
+	out             *string
+	alpha           *float64
+	seed            *int64
+	benchmark       *string
+	checkpointDir   *string
+	checkpointEvery *int
+	resume          *bool
+}
+
+func commonFlags(fs *flag.FlagSet) common {
+	return common{
+		datadir:         fs.String("datadir", "./cmd/bgweb-api/data", "Folder containing gnubg data (bearoff databases)"),
+		init:            fs.String("init", "", "Weights file to start from, empty for random nets"),
+		hidden:          fs.Int("hidden", 128, "Hidden nodes of random nets"),
+		out:             fs.String("out", "gnubg.weights", "Weights file to write when done"),
+		alpha:           fs.Float64("alpha", 0.1, "Learning rate"),
+		seed:            fs.Int64("seed", 0, "Random seed, 0 for the current time"),
+		benchmark:       fs.String("benchmark", "", "JSON lines file of examples to benchmark against"),
+		checkpointDir:   fs.String("checkpoint-dir", "", "Folder to save checkpoints in"),
+		checkpointEvery: fs.Int("checkpoint-every", 0, "Save a checkpoint every N epochs or games"),
+		resume:          fs.Bool("resume", false, "Continue from the latest checkpoint in --checkpoint-dir"),
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "supervised":
+		err = supervised(os.Args[2:])
+	case "td":
+		err = td(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: bgtrain supervised|td [flags]")
+	os.Exit(2)
+}
+
+func supervised(args []string) error {
+	fs := flag.NewFlagSet("supervised", flag.ExitOnError)
+	c := commonFlags(fs)
+	var data = fs.String("data", "", "JSON lines file of training examples")
+	var epochs = fs.Int("epochs", 10, "Passes over the training examples")
+	var holdout = fs.Float64("holdout", 0.1, "Fraction of the examples to benchmark against when --benchmark is not set")
+	fs.Parse(args)
+
+	rnd, w, start, err := c.setup()
+	if err != nil {
+		return err
+	}
+
+	examples, err := readExamples(*data)
+	if err != nil {
+		return err
+	}
+
+	var heldOut []train.Example
+	if *c.benchmark != "" {
+		if heldOut, err = readExamples(*c.benchmark); err != nil {
+			return err
+		}
+	} else {
+		examples, heldOut = train.Split(examples, *holdout, rnd)
+	}
+
+	err = train.Supervised(w, examples, heldOut, train.SupervisedConfig{
+		Alpha:      float32(*c.alpha),
+		Epochs:     *epochs,
+		Rand:       rnd,
+		Progress:   progress("epoch"),
+		Checkpoint: c.checkpointer(),
+		Start:      start,
+	})
+	if err != nil {
+		return err
+	}
+
+	return train.SaveWeights(w, *c.out)
+}
+
+func td(args []string) error {
+	fs := flag.NewFlagSet("td", flag.ExitOnError)
+	c := commonFlags(fs)
+	var games = fs.Int("games", 10000, "Self-play games")
+	var lambda = fs.Float64("lambda", 0.7, "TD(λ) trace decay")
+	var epsilon = fs.Float64("epsilon", 0, "Chance of a random play, for exploration")
+	var benchmarkEvery = fs.Int("benchmark-every", 1000, "Benchmark every N games")
+	var variation = fs.String("variation", "standard", "Starting position, standard or nackgammon")
+	fs.Parse(args)
+
+	rnd, w, start, err := c.setup()
+	if err != nil {
+		return err
+	}
+
+	var bench []train.Example
+	if *c.benchmark != "" {
+		if bench, err = readExamples(*c.benchmark); err != nil {
+			return err
+		}
+	}
+
+	var board gnubg.TanBoard
+	switch *variation {
+	case "standard":
+		board = train.StartStandard
+	case "nackgammon":
+		board = train.StartNackgammon
+	default:
+		return fmt.Errorf("unknown variation: %v", *variation)
+	}
+
+	err = train.TD(w, train.TDConfig{
+		Alpha:          float32(*c.alpha),
+		Lambda:         float32(*lambda),
+		Epsilon:        *epsilon,
+		Games:          *games,
+		Start:          board,
+		Rand:           rnd,
+		Benchmark:      bench,
+		BenchmarkEvery: *benchmarkEvery,
+		Progress:       progress("game"),
+		Checkpoint:     c.checkpointer(),
+		Done:           start,
+	})
+	if err != nil {
+		return err
+	}
+
+	return train.SaveWeights(w, *c.out)
+}
+
+// setup initialises the engine and returns the random source, the nets to
+// train and the step they are at
+func (c common) setup() (*rand.Rand, *gnubg.Weights, int, error) {
+	if err := gnubg.Init(os.DirFS(*c.datadir)); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to initialize gnubg: %w", err)
+	}
+
+	seed := *c.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rnd := rand.New(rand.NewSource(seed))
+
+	switch {
+	case *c.resume:
+		if *c.checkpointDir == "" {
+			return nil, nil, 0, fmt.Errorf("--resume needs --checkpoint-dir")
+		}
+		w, step, err := train.LoadCheckpoint(*c.checkpointDir)
+		return rnd, w, step, err
+	case *c.init != "":
+		w, err := train.LoadWeights(*c.init)
+		return rnd, w, 0, err
+	default:
+		return rnd, gnubg.NewWeights(*c.hidden, rnd), 0, nil
+	}
+}
+
+func (c common) checkpointer() *train.Checkpointer {
+	if *c.checkpointDir == "" {
+		return nil
+	}
+	return &train.Checkpointer{Dir: *c.checkpointDir, Every: *c.checkpointEvery}
+}
+
+func progress(step string) func(int, train.Stats) {
+	return func(n int, s train.Stats) {
+		fmt.Printf("%v %v: %v\n", step, n, s)
+	}
+}
+
+func readExamples(path string) ([]train.Example, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return train.ReadExamples(f)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/deepmap/oapi-codegen/pkg/middleware"
 	"github.com/labstack/echo/v4"
@@ -19,12 +20,15 @@ import (
 func main() {
 	var datadir = flag.String("datadir", "./cmd/bgweb-api/data", "Folder containing gnubg data")
 	var port = flag.Int("port", 8080, "Port for HTTP server")
+	var quantized = flag.Bool("quantized", false, "Evaluate with the int8 quantized neural nets unless a request says otherwise")
 	flag.Parse()
 
 	if err := gnubg.Init(os.DirFS(*datadir)); err != nil {
 		panic(fmt.Errorf("failed to initialize gnubg: %w", err))
 	}
 
+	gnubg.SetQuantized(*quantized)
+
 	swagger, err := openapi.GetSwagger()
 	if err != nil {
 		panic(fmt.Errorf("failed to get swagger: %w", err))
@@ -63,7 +67,28 @@ func main() {
 type BackgammonWebAPI struct {
 }
 
-func (*BackgammonWebAPI) PostGetmoves(c echo.Context) (err error) {
+func (*BackgammonWebAPI) PostGetfeatures(c echo.Context) error {
+	var args openapi.FeaturesArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.FeaturesArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	features, err := api.GetFeatures(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, features)
+}
+
+func (*BackgammonWebAPI) PostGetmoves(c echo.Context, params openapi.PostGetmovesParams) (err error) {
 	var args openapi.MoveArgs
 
 	// unmarshal body
@@ -71,13 +96,303 @@ func (*BackgammonWebAPI) PostGetmoves(c echo.Context) (err error) {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	ids, err := api.MoveArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	if acceptsText(c) {
+		text, err := api.GetMovesText(args, string(fromPtr(params.Perspective, "")))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.String(http.StatusOK, text)
+	}
+
 	// process logic
 	moves, err := api.GetMoves(args)
-
 	if err != nil {
-		c.JSON(http.StatusBadRequest, err)
-		return
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	return c.JSON(http.StatusOK, moves)
 }
+
+// Send the IDs of the position of a request in the response headers.
+func setPositionIDs(c echo.Context, ids api.IDs) {
+	c.Response().Header().Set("Position-Id", ids.PositionID)
+	c.Response().Header().Set("Match-Id", ids.MatchID)
+	c.Response().Header().Set("XGID", ids.XGID)
+}
+
+func (*BackgammonWebAPI) PostGetbearoff(c echo.Context, params openapi.PostGetbearoffParams) error {
+	var args openapi.BearoffArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.BearoffArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	if acceptsText(c) {
+		text, err := api.GetBearoffText(args, string(fromPtr(params.Perspective, "")))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.String(http.StatusOK, text)
+	}
+
+	bearoff, err := api.GetBearoff(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, bearoff)
+}
+
+func (*BackgammonWebAPI) PostGetbearoffcube(c echo.Context) error {
+	var args openapi.BearoffArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.BearoffArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	cube, err := api.GetBearoffCube(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, cube)
+}
+
+func (*BackgammonWebAPI) PostGetrace(c echo.Context, params openapi.PostGetraceParams) error {
+	var args openapi.RaceArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.RaceArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	if acceptsText(c) {
+		text, err := api.GetRaceText(args, string(fromPtr(params.Perspective, "")))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.String(http.StatusOK, text)
+	}
+
+	race, err := api.GetRace(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, race)
+}
+
+func (*BackgammonWebAPI) PostGetroute(c echo.Context) error {
+	var args openapi.RouteArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.RouteArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	route, err := api.GetRoute(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, route)
+}
+
+func (*BackgammonWebAPI) PostGetshots(c echo.Context, params openapi.PostGetshotsParams) error {
+	var args openapi.ShotsArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.ShotsArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	if acceptsText(c) {
+		text, err := api.GetShotsText(args, string(fromPtr(params.Perspective, "")))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.String(http.StatusOK, text)
+	}
+
+	shots, err := api.GetShots(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, shots)
+}
+
+func (*BackgammonWebAPI) PostGetstructure(c echo.Context, params openapi.PostGetstructureParams) error {
+	var args openapi.MoveArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.MoveArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	if acceptsText(c) {
+		text, err := api.GetStructureText(args, string(fromPtr(params.Perspective, "")))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.String(http.StatusOK, text)
+	}
+
+	structure, err := api.GetStructure(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, structure)
+}
+
+func (*BackgammonWebAPI) PostGrademove(c echo.Context, params openapi.PostGrademoveParams) error {
+	var args openapi.GradeArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.GradeArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	if acceptsText(c) {
+		text, err := api.GradeMoveText(args, string(fromPtr(params.Perspective, "")))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		return c.String(http.StatusOK, text)
+	}
+
+	grade, err := api.GradeMove(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, grade)
+}
+
+func (*BackgammonWebAPI) PostInfermove(c echo.Context) error {
+	var args openapi.InferArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.InferArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	inference, err := api.InferMove(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, inference)
+}
+
+func (*BackgammonWebAPI) PostApplymove(c echo.Context) error {
+	var args openapi.ApplyArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.ApplyArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	applied, err := api.ApplyMove(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, applied)
+}
+
+func (*BackgammonWebAPI) PostGetsvg(c echo.Context) error {
+	var args openapi.SvgArgs
+
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ids, err := api.SvgArgsIDs(args)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	setPositionIDs(c, ids)
+
+	svg, err := api.GetSVG(args)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	return c.Blob(http.StatusOK, "image/svg+xml", []byte(svg))
+}
+
+// Whether the request asks for text/plain rather than JSON.
+func acceptsText(c echo.Context) bool {
+	for _, accept := range strings.Split(c.Request().Header.Get(echo.HeaderAccept), ",") {
+		mediaType, _, _ := strings.Cut(strings.TrimSpace(accept), ";")
+		switch mediaType {
+		case echo.MIMETextPlain:
+			return true
+		case echo.MIMEApplicationJSON, "*/*":
+			return false
+		}
+	}
+	return false
+}
+
+func fromPtr[T any](val *T, def T) T {
+	if val == nil {
+		return def
+	}
+	return *val
+}
@@ -0,0 +1,151 @@
+// Command quantreport compares the quantized and float neural nets over a
+// corpus of positions and prints an accuracy report per position class.
+//
+// The corpus is a file of JSON lines, each holding at least the "board" and
+// "player" (or "positionId" and "matchId") of a /getmoves request, so
+// request logs can be used as they are.
+package main
+
+import (
+	"bgweb-api/internal/api"
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+var outputNames = [5]string{"win", "winG", "winBG", "loseG", "loseBG"}
+
+type classStats struct {
+	positions   int
+	maxErr      [5]float64
+	sumErr      [5]float64
+	maxEqErr    float64
+	sumEqErr    float64
+	rollWeight  int
+	agreeWeight int
+	equityLoss  float64
+}
+
+func main() {
+	var datadir = flag.String("datadir", "./cmd/bgweb-api/data", "Folder containing gnubg data")
+	var corpus = flag.String("corpus", "-", "JSON lines file of positions, - for stdin")
+	flag.Parse()
+
+	if err := gnubg.Init(os.DirFS(*datadir)); err != nil {
+		panic(fmt.Errorf("failed to initialize gnubg: %w", err))
+	}
+
+	var r io.Reader = os.Stdin
+	if *corpus != "-" {
+		f, err := os.Open(*corpus)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	stats, err := compare(r)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	report(os.Stdout, stats)
+}
+
+func compare(r io.Reader) (map[string]*classStats, error) {
+	var stats = map[string]*classStats{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var args openapi.MoveArgs
+		if err := json.Unmarshal(scanner.Bytes(), &args); err != nil {
+			return nil, fmt.Errorf("line %v: %v", line, err)
+		}
+
+		board, player, err := api.MoveArgsBoard(args)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", line, err)
+		}
+
+		c, err := gnubg.CompareQuantized(board, player)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", line, err)
+		}
+
+		s, ok := stats[c.Class]
+		if !ok {
+			s = &classStats{}
+			stats[c.Class] = s
+		}
+
+		s.positions++
+		for i := 0; i < 5; i++ {
+			e := math.Abs(float64(c.Quantized[i] - c.Float[i]))
+			s.maxErr[i] = math.Max(s.maxErr[i], e)
+			s.sumErr[i] += e
+		}
+		eqErr := math.Abs(float64(equity(c.Quantized) - equity(c.Float)))
+		s.maxEqErr = math.Max(s.maxEqErr, eqErr)
+		s.sumEqErr += eqErr
+		s.rollWeight += c.RollWeight
+		s.agreeWeight += c.AgreeWeight
+		s.equityLoss += float64(c.EquityLoss)
+	}
+
+	return stats, scanner.Err()
+}
+
+// cubeless money equity
+func equity(ar [5]float32) float32 {
+	return 2*ar[0] - 1 + ar[1] - ar[3] + ar[2] - ar[4]
+}
+
+func report(w io.Writer, stats map[string]*classStats) {
+	var classes = make([]string, 0, len(stats))
+	for class := range stats {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	defer tw.Flush()
+
+	fmt.Fprint(tw, "class\tpositions\t")
+	for _, name := range outputNames {
+		fmt.Fprintf(tw, "%v max\t%v mean\t", name, name)
+	}
+	fmt.Fprint(tw, "eq max\teq mean\tmove agree %\tloss/roll (mEq)\t\n")
+
+	for _, class := range classes {
+		s := stats[class]
+		n := float64(s.positions)
+
+		fmt.Fprintf(tw, "%v\t%v\t", class, s.positions)
+		for i := 0; i < 5; i++ {
+			fmt.Fprintf(tw, "%.4f\t%.4f\t", s.maxErr[i], s.sumErr[i]/n)
+		}
+		fmt.Fprintf(tw, "%.4f\t%.4f\t", s.maxEqErr, s.sumEqErr/n)
+		if s.rollWeight > 0 {
+			fmt.Fprintf(tw, "%.2f\t", 100*float64(s.agreeWeight)/float64(s.rollWeight))
+		} else {
+			fmt.Fprint(tw, "-\t")
+		}
+		fmt.Fprintf(tw, "%.3f\t\n", 1000*s.equityLoss/(36*n))
+	}
+}
@@ -31,6 +31,17 @@ func main() {
 	// register functions
 	{
 		js.Global().Set("wasm_get_moves", js.FuncOf(getMoves))
+		js.Global().Set("wasm_get_svg", js.FuncOf(getSVG))
+		js.Global().Set("wasm_get_race", js.FuncOf(getRace))
+		js.Global().Set("wasm_get_bearoff", js.FuncOf(getBearoff))
+		js.Global().Set("wasm_get_bearoff_cube", js.FuncOf(getBearoffCube))
+		js.Global().Set("wasm_get_route", js.FuncOf(getRoute))
+		js.Global().Set("wasm_get_features", js.FuncOf(getFeatures))
+		js.Global().Set("wasm_get_structure", js.FuncOf(getStructure))
+		js.Global().Set("wasm_get_shots", js.FuncOf(getShots))
+		js.Global().Set("wasm_grade_move", js.FuncOf(gradeMove))
+		js.Global().Set("wasm_infer_move", js.FuncOf(inferMove))
+		js.Global().Set("wasm_apply_move", js.FuncOf(applyMove))
 	}
 	<-c
 }
@@ -56,3 +67,239 @@ func getMoves(this js.Value, input []js.Value) interface{} {
 
 	return js.ValueOf(string(bytes))
 }
+
+func getSVG(this js.Value, input []js.Value) interface{} {
+	var args openapi.SvgArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	svg, err := api.GetSVG(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(svg)
+}
+
+func getRace(this js.Value, input []js.Value) interface{} {
+	var args openapi.RaceArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	race, err := api.GetRace(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(race)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
+
+func getBearoff(this js.Value, input []js.Value) interface{} {
+	var args openapi.BearoffArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bearoff, err := api.GetBearoff(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(bearoff)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
+
+func getBearoffCube(this js.Value, input []js.Value) interface{} {
+	var args openapi.BearoffArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	cube, err := api.GetBearoffCube(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(cube)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
+
+func getRoute(this js.Value, input []js.Value) interface{} {
+	var args openapi.RouteArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	route, err := api.GetRoute(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(route)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
+
+func getFeatures(this js.Value, input []js.Value) interface{} {
+	var args openapi.FeaturesArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	features, err := api.GetFeatures(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(features)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
+
+func getStructure(this js.Value, input []js.Value) interface{} {
+	var args openapi.MoveArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	structure, err := api.GetStructure(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(structure)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
+
+func getShots(this js.Value, input []js.Value) interface{} {
+	var args openapi.ShotsArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	shots, err := api.GetShots(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(shots)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
+
+func gradeMove(this js.Value, input []js.Value) interface{} {
+	var args openapi.GradeArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	grade, err := api.GradeMove(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(grade)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
+
+func inferMove(this js.Value, input []js.Value) interface{} {
+	var args openapi.InferArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	inference, err := api.InferMove(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(inference)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
+
+func applyMove(this js.Value, input []js.Value) interface{} {
+	var args openapi.ApplyArgs
+
+	if err := json.Unmarshal([]byte(input[0].String()), &args); err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	applied, err := api.ApplyMove(args)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	bytes, err := json.Marshal(applied)
+
+	if err != nil {
+		return fmt.Sprintf("{\"error\": \"%v\"}", err.Error())
+	}
+
+	return js.ValueOf(string(bytes))
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/layout"
+	"bgweb-api/internal/openapi"
+	"fmt"
+)
+
+// ApplyMove moves the chequers of the player on roll as the play of the
+// arguments tells and returns the position after it. With dice the play
+// must be legal; without, each chequer move only has to be possible.
+func ApplyMove(args openapi.ApplyArgs) (openapi.AppliedMove, error) {
+	var ret openapi.AppliedMove
+
+	pos, err := newPosition(applyArgsPositionArgs(args))
+	if err != nil {
+		return ret, err
+	}
+
+	moves, err := argsPlay(args.Play, args.Notation, pos.flipNotation(string(fromPtr(args.Numbering, ""))))
+	if err != nil {
+		return ret, err
+	}
+
+	var board = pos.moverBoard()
+	var after gnubg.TanBoard
+	if pos.ms.Dice[0] != 0 {
+		after, err = gnubg.CheckPlay(board, pos.ms.Dice, moves)
+		if err != nil {
+			return ret, fmt.Errorf("illegal play: %v", err)
+		}
+	} else {
+		after, err = gnubg.ApplyMoves(board, moves)
+		if err != nil {
+			return ret, err
+		}
+	}
+
+	var next = pos.afterMove(gnubg.SwapSides(after))
+	ret.Board = layout.Board(next.board)
+	ret.PositionId = openapi.PositionId(next.positionID())
+	ret.Xgid = openapi.Xgid(next.xgid())
+	ret.Hits, ret.BorneOff = playEffects(board, after)
+
+	return ret, nil
+}
+
+// ApplyArgsIDs returns the IDs of the position the play is made from.
+func ApplyArgsIDs(args openapi.ApplyArgs) (IDs, error) {
+	return positionIDs(applyArgsPositionArgs(args))
+}
+
+func applyArgsPositionArgs(args openapi.ApplyArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, args.Dice)
+}
+
+// the points where a play hits, counted from the mover, and the chequers
+// it bears off, from the boards before and after it seen from the mover
+func playEffects(before, after gnubg.TanBoard) ([]int, int) {
+	var hits = []int{}
+	for i := 0; i < 24; i++ {
+		if before[0][i] == 1 && after[0][i] == 0 {
+			hits = append(hits, 24-i)
+		}
+	}
+
+	var borneOff int
+	for i := 0; i < 25; i++ {
+		borneOff += before[1][i] - after[1][i]
+	}
+
+	return hits, borneOff
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"bgweb-api/internal/layout"
+	"bgweb-api/internal/openapi"
+	"reflect"
+	"testing"
+)
+
+func TestApplyMove(t *testing.T) {
+	var opening = &openapi.Board{
+		X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+	}
+	/* an o blot on x's 5-point */
+	var blot = &openapi.Board{
+		X: opening.X,
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N20: toPtr(1), N24: toPtr(1)},
+	}
+	var bearoff = &openapi.Board{
+		X: openapi.CheckerLayout{N1: toPtr(2), N2: toPtr(13)},
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(7)},
+	}
+	var pointed = openapi.CheckerLayout{N5: toPtr(2), N6: toPtr(4), N8: toPtr(2), N13: toPtr(5), N24: toPtr(2)}
+	var args = func(board *openapi.Board, dice []int, play ...openapi.CheckerPlay) openapi.ApplyArgs {
+		var ret = openapi.ApplyArgs{Board: board, Player: toPtr(openapi.ApplyArgsPlayer("x")), Play: &play}
+		if dice != nil {
+			ret.Dice = &dice
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name         string
+		args         openapi.ApplyArgs
+		want         openapi.Board
+		wantHits     []int
+		wantBorneOff int
+		wantErr      string
+	}{
+		{
+			name:     "should apply a legal play",
+			args:     args(opening, []int{3, 1}, openapi.CheckerPlay{From: "8", To: "5"}, openapi.CheckerPlay{From: "6", To: "5"}),
+			want:     openapi.Board{X: pointed, O: opening.O},
+			wantHits: []int{},
+		},
+		{
+			name: "should hit a blot",
+			args: args(blot, []int{3, 1}, openapi.CheckerPlay{From: "8", To: "5"}, openapi.CheckerPlay{From: "6", To: "5"}),
+			want: openapi.Board{
+				X: pointed,
+				O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(1), Bar: toPtr(1)},
+			},
+			wantHits: []int{5},
+		},
+		{
+			name:         "should bear off",
+			args:         args(bearoff, []int{2, 1}, openapi.CheckerPlay{From: "2", To: "off"}, openapi.CheckerPlay{From: "1", To: "off"}),
+			want:         openapi.Board{X: openapi.CheckerLayout{N1: toPtr(1), N2: toPtr(12)}, O: bearoff.O},
+			wantHits:     []int{},
+			wantBorneOff: 2,
+		},
+		{
+			name: "should apply a play without dice",
+			args: args(opening, nil, openapi.CheckerPlay{From: "13", To: "7"}),
+			want: openapi.Board{
+				X: openapi.CheckerLayout{N6: toPtr(5), N7: toPtr(1), N8: toPtr(3), N13: toPtr(4), N24: toPtr(2)},
+				O: opening.O,
+			},
+			wantHits: []int{},
+		},
+		{
+			name: "should apply a play in notation",
+			args: openapi.ApplyArgs{Board: blot, Dice: &[]int{3, 1}, Player: toPtr(openapi.ApplyArgsPlayer("x")), Notation: toPtr("8/5* 6/5")},
+			want: openapi.Board{
+				X: pointed,
+				O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(1), Bar: toPtr(1)},
+			},
+			wantHits: []int{5},
+		},
+		{
+			name:    "should refuse invalid notation",
+			args:    openapi.ApplyArgs{Board: opening, Player: toPtr(openapi.ApplyArgsPlayer("x")), Notation: toPtr("8-5")},
+			wantErr: `invalid move "8-5"`,
+		},
+		{
+			name:    "should refuse no play",
+			args:    openapi.ApplyArgs{Board: opening, Player: toPtr(openapi.ApplyArgsPlayer("x"))},
+			wantErr: "play or notation is required",
+		},
+		{
+			name:    "should refuse an illegal play of the dice",
+			args:    args(opening, []int{3, 1}, openapi.CheckerPlay{From: "8", To: "5"}),
+			wantErr: "illegal play: both dice must be played",
+		},
+		{
+			name:    "should refuse a chequer move that can't be made",
+			args:    args(opening, nil, openapi.CheckerPlay{From: "3", To: "1"}),
+			wantErr: "can't move 3/1: invalid point number, or source is empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyMove(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ApplyMove() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyMove() error = %v", err)
+			}
+			if layout.TanBoard(got.Board) != layout.TanBoard(tt.want) {
+				t.Errorf("ApplyMove() board = %v, want %v", layout.TanBoard(got.Board), layout.TanBoard(tt.want))
+			}
+			if !reflect.DeepEqual(got.Hits, tt.wantHits) || got.BorneOff != tt.wantBorneOff {
+				t.Errorf("ApplyMove() hits %v, borne off %v, want %v, %v", got.Hits, got.BorneOff, tt.wantHits, tt.wantBorneOff)
+			}
+
+			/* o is on roll after the play */
+			pos, err := newPosition(positionArgs{positionID: &got.PositionId, player: "o"})
+			if err != nil || pos.board != layout.TanBoard(got.Board) {
+				t.Errorf("ApplyMove() positionId %v is %v, %v", got.PositionId, pos.board, err)
+			}
+		})
+	}
+}
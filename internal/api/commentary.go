@@ -0,0 +1,47 @@
+package api
+
+import (
+	"bgweb-api/internal/commentary"
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+)
+
+// addCommentary sets the commentary of each of the scored moves of the
+// position, found as the gnubg moves of the same index, in the language of
+// the catalogue lang.
+func addCommentary(pos position, moves []openapi.Move, found []gnubg.Move, lang string) error {
+	c, err := commentary.Load(lang)
+	if err != nil {
+		return err
+	}
+
+	var x, o = pos.structure()
+	var mover, opp = byMover(x, o, pos.ms.Move)
+
+	var cms = make([]commentary.Move, 0, len(moves))
+	for i, move := range moves {
+		var ax, ao = pos.afterMove(found[i].GetBoard()).structure()
+		var m, mo = byMover(ax, ao, pos.ms.Move)
+		var p = move.Evaluation.Probability
+
+		cms = append(cms, commentary.Move{
+			Play:     fromPtr(move.Notation, ""),
+			Equity:   move.Evaluation.Eq,
+			WinG:     p.WinG,
+			LoseG:    p.LoseG,
+			Hits:     mo.Bar - opp.Bar,
+			Mover:    m,
+			Opponent: mo,
+		})
+	}
+
+	var opponent = "o"
+	if pos.ms.Move == 0 {
+		opponent = "x"
+	}
+	for i, s := range c.Comment(mover, opponent, cms) {
+		moves[i].Commentary = toPtr(s)
+	}
+
+	return nil
+}
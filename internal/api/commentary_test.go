@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"testing"
+)
+
+func TestGetMovesCommentary(t *testing.T) {
+	once.Do(setup)
+
+	/* 4-2 to hit o's blot on x's 4-point while making it */
+	var board = &openapi.Board{
+		X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(4), N21: toPtr(1), N24: toPtr(2)},
+	}
+
+	tests := []struct {
+		name     string
+		args     openapi.MoveArgs
+		wantBest string
+		wantLast string
+		wantErr  bool
+	}{
+		{
+			name: "should explain the hit in English",
+			args: openapi.MoveArgs{
+				Board: board, Dice: &[]int{4, 2}, Player: toPtr(openapi.MoveArgsPlayer("x")),
+				MaxMoves: toPtr(5), Quantized: toPtr(false), Commentary: toPtr(true),
+			},
+			wantBest: "8/4* 6/4 is best: it hits, makes the 4-point and leaves no shots.",
+			wantLast: "24/20 13/11 is 0.256 worse because it doesn't hit and doesn't make the 4-point.",
+		},
+		{
+			name: "should explain the hit in French",
+			args: openapi.MoveArgs{
+				Board: board, Dice: &[]int{4, 2}, Player: toPtr(openapi.MoveArgsPlayer("x")),
+				MaxMoves: toPtr(5), Quantized: toPtr(false), Commentary: toPtr(true), Language: toPtr(openapi.MoveArgsLanguageFr),
+			},
+			wantBest: "8/4* 6/4 est le meilleur coup : il frappe, fait la case 4 et ne laisse aucun tir.",
+			wantLast: "24/20 13/11 est moins bon de 0,256 car il ne frappe pas et ne fait pas la case 4.",
+		},
+		{
+			name: "should refuse a language without a catalogue",
+			args: openapi.MoveArgs{
+				Board: board, Dice: &[]int{4, 2}, Player: toPtr(openapi.MoveArgsPlayer("x")),
+				Commentary: toPtr(true), Language: toPtr(openapi.MoveArgsLanguage("de")),
+			},
+			wantErr: true,
+		},
+		{
+			name: "should refuse commentary on unscored moves",
+			args: openapi.MoveArgs{
+				Board: board, Dice: &[]int{4, 2}, Player: toPtr(openapi.MoveArgsPlayer("x")),
+				Commentary: toPtr(true), ScoreMoves: toPtr(false),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetMoves(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetMoves() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			for _, move := range got {
+				if move.Commentary == nil {
+					t.Fatalf("GetMoves() move %v has no commentary", fromPtr(move.Notation, ""))
+				}
+			}
+			if best := *got[0].Commentary; best != tt.wantBest {
+				t.Errorf("GetMoves() best commentary = %q, want %q", best, tt.wantBest)
+			}
+			if last := *got[len(got)-1].Commentary; last != tt.wantLast {
+				t.Errorf("GetMoves() last commentary = %q, want %q", last, tt.wantLast)
+			}
+		})
+	}
+}
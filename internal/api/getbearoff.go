@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+	"fmt"
+	"strings"
+)
+
+// GetBearoff reads the bearoff distributions of the position of the
+// arguments from the one-sided bearoff database.
+func GetBearoff(args openapi.BearoffArgs) (openapi.BearoffInfo, error) {
+	var ret openapi.BearoffInfo
+
+	pos, err := newPosition(bearoffArgsPositionArgs(args))
+	if err != nil {
+		return ret, err
+	}
+
+	bo, err := gnubg.BearoffDistribution(pos.moverBoard())
+	if err != nil {
+		return ret, err
+	}
+
+	/* the distributions are indexed from the player on roll: 1 is theirs */
+	var x, o = 1, 0
+	if pos.ms.Move == 0 {
+		x, o = 0, 1
+	}
+
+	ret.X = bearoffDistribution(bo.Dists[x])
+	ret.O = bearoffDistribution(bo.Dists[o])
+	ret.Win = fformat(bo.Win)
+	ret.WinG = fformat(bo.WinGammon)
+	ret.LoseG = fformat(bo.LoseGammon)
+
+	return ret, nil
+}
+
+// GetBearoffText draws the position as gnubg's ASCII board, from the side
+// of perspective ("x", "o", or "" for the player on roll), and writes the
+// distributions below it, a row for each number of rolls.
+func GetBearoffText(args openapi.BearoffArgs, perspective string) (string, error) {
+	pos, err := newPosition(bearoffArgsPositionArgs(args))
+	if err != nil {
+		return "", err
+	}
+
+	bo, err := GetBearoff(args)
+	if err != nil {
+		return "", err
+	}
+
+	board, err := pos.draw(perspective)
+	if err != nil {
+		return "", err
+	}
+
+	var dists = [][]float32{bo.X.Off, bo.X.Gammon, bo.O.Off, bo.O.Gammon}
+	var rows int
+	for _, d := range dists {
+		if len(d) > rows {
+			rows = len(d)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(board + "\n")
+	fmt.Fprintf(&sb, " %-5s %9s %9s %9s %9s\n", "Rolls", "X off", "X gammon", "O off", "O gammon")
+	for n := 0; n < rows; n++ {
+		fmt.Fprintf(&sb, " %5d", n)
+		for _, d := range dists {
+			var p float32
+			if n < len(d) {
+				p = d[n]
+			}
+			fmt.Fprintf(&sb, " %9.3f", p)
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, " %-5s %9.3f %9.3f %9.3f %9.3f\n", "Mean", bo.X.Rolls, bo.X.GammonRolls, bo.O.Rolls, bo.O.GammonRolls)
+	fmt.Fprintf(&sb, " %-5s %9.3f %9.3f %9.3f %9.3f\n", "SD", bo.X.RollsSd, bo.X.GammonRollsSd, bo.O.RollsSd, bo.O.GammonRollsSd)
+	fmt.Fprintf(&sb, "\n Player on roll: wins %.3f, wins a gammon %.3f, loses a gammon %.3f\n", bo.Win, bo.WinG, bo.LoseG)
+
+	return sb.String(), nil
+}
+
+// BearoffArgsIDs returns the IDs of the position read.
+func BearoffArgsIDs(args openapi.BearoffArgs) (IDs, error) {
+	return positionIDs(bearoffArgsPositionArgs(args))
+}
+
+func bearoffArgsPositionArgs(args openapi.BearoffArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, nil)
+}
+
+func bearoffDistribution(d gnubg.BearoffDist) openapi.BearoffDistribution {
+	return openapi.BearoffDistribution{
+		Off:           distribution(d.Off),
+		Rolls:         fformat(d.Mean),
+		RollsSd:       fformat(d.SD),
+		Gammon:        distribution(d.Gammon),
+		GammonRolls:   fformat(d.GammonMean),
+		GammonRollsSd: fformat(d.GammonSD),
+	}
+}
+
+// chances by number of rolls, up to the last that isn't 0
+func distribution(ar [32]float32) []float32 {
+	var n = len(ar)
+	for n > 1 && fformat(ar[n-1]) == 0 {
+		n--
+	}
+
+	var ret = make([]float32, n)
+	for i := range ret {
+		ret[i] = fformat(ar[i])
+	}
+	return ret
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetBearoff(t *testing.T) {
+	once.Do(setup)
+
+	/* x's last chequer on the ace point, o's on the 6 point; with chequers
+	 * off, neither can be gammoned */
+	var board = &openapi.Board{
+		X: openapi.CheckerLayout{N1: toPtr(1)},
+		O: openapi.CheckerLayout{N6: toPtr(1)},
+	}
+
+	tests := []struct {
+		name    string
+		args    openapi.BearoffArgs
+		want    openapi.BearoffInfo
+		wantErr bool
+	}{
+		{
+			name: "should read the distributions with x on roll",
+			args: openapi.BearoffArgs{Board: board, Player: toPtr(openapi.BearoffArgsPlayer("x"))},
+			want: openapi.BearoffInfo{
+				X:   openapi.BearoffDistribution{Off: []float32{0, 1}, Rolls: 1, Gammon: []float32{1}},
+				O:   openapi.BearoffDistribution{Off: []float32{0, 0.75, 0.25}, Rolls: 1.25, RollsSd: 0.433, Gammon: []float32{1}},
+				Win: 1,
+			},
+		},
+		{
+			name: "should work out the race with o on roll",
+			args: openapi.BearoffArgs{Board: board, Player: toPtr(openapi.BearoffArgsPlayer("o"))},
+			want: openapi.BearoffInfo{
+				X:   openapi.BearoffDistribution{Off: []float32{0, 1}, Rolls: 1, Gammon: []float32{1}},
+				O:   openapi.BearoffDistribution{Off: []float32{0, 0.75, 0.25}, Rolls: 1.25, RollsSd: 0.433, Gammon: []float32{1}},
+				Win: 0.75,
+			},
+		},
+		{
+			name:    "should refuse a position beyond the database",
+			args:    openapi.BearoffArgs{Xgid: toPtr(openapi.Xgid("XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:3:0:10"))},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetBearoff(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBearoff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBearoff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBearoffText(t *testing.T) {
+	once.Do(setup)
+
+	var args = openapi.BearoffArgs{
+		Board: &openapi.Board{
+			X: openapi.CheckerLayout{N1: toPtr(1)},
+			O: openapi.CheckerLayout{N6: toPtr(1)},
+		},
+		Player: toPtr(openapi.BearoffArgsPlayer("o")),
+	}
+
+	got, err := GetBearoffText(args, "x")
+	if err != nil {
+		t.Fatalf("GetBearoffText() error = %v", err)
+	}
+	for _, line := range []string{
+		" +13-14-15-16-17-18------19-20-21-22-23-24-+     X\n",
+		"     1     1.000     0.000     0.750     0.000\n",
+		"     2     0.000     0.000     0.250     0.000\n",
+		" Mean      1.000     0.000     1.250     0.000\n",
+		" Player on roll: wins 0.750, ",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("GetBearoffText() = \n%v\nwant %q", got, line)
+		}
+	}
+}
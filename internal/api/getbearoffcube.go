@@ -0,0 +1,59 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+	"errors"
+)
+
+// neural net evaluation of positions outside the two-sided bearoff
+// database, as /getmoves evaluates
+var bearoffCubeSettings = gnubg.Settings{Plies: 2, Prune: true, Deterministic: true}
+
+// GetBearoffCube reads the exact money game cube decisions of the position
+// of the arguments from the two-sided bearoff database, or evaluates them
+// with the neural nets when it isn't there.
+func GetBearoffCube(args openapi.BearoffArgs) (openapi.BearoffCubeInfo, error) {
+	var ret openapi.BearoffCubeInfo
+
+	pos, err := newPosition(bearoffArgsPositionArgs(args))
+	if err != nil {
+		return ret, err
+	}
+
+	bc, err := gnubg.PerfectCube(pos.moverBoard())
+	switch {
+	case err == nil:
+		ret.Exact = true
+	case errors.Is(err, gnubg.ErrNoCubefulBearoff):
+		var s = bearoffCubeSettings
+		s.Quantized = gnubg.IsQuantized()
+		if bc, err = gnubg.EstimateBearoffCube(pos.moverBoard(), pos.ms.Move, s); err != nil {
+			return ret, err
+		}
+		ret.Info = &openapi.EvalInfo{Cubeful: true, Plies: s.Plies + 1}
+	default:
+		return ret, err
+	}
+
+	ret.Equities = openapi.CubefulEquities{
+		Cubeless: fformat(bc.Cubeless),
+		Owned:    fformat(bc.Owned),
+		Centred:  fformat(bc.Centred),
+		OppOwned: fformat(bc.OppOwned),
+	}
+	ret.Double = cubeAction(bc.Double())
+	ret.Redouble = cubeAction(bc.Redouble())
+
+	return ret, nil
+}
+
+func cubeAction(d gnubg.CubeDecision) openapi.CubeAction {
+	return openapi.CubeAction{
+		NoDouble:   fformat(d.NoDouble),
+		DoubleTake: fformat(d.DoubleTake),
+		DoublePass: fformat(d.DoublePass),
+		Double:     d.Double(),
+		Take:       d.Take(),
+	}
+}
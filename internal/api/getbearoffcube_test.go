@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"testing"
+)
+
+func TestGetBearoffCube(t *testing.T) {
+	once.Do(setup)
+
+	/* no two-sided database is shipped, so these are evaluated */
+	tests := []struct {
+		name         string
+		args         openapi.BearoffArgs
+		wantDouble   bool
+		wantRedouble bool
+		wantTake     bool
+		wantErr      bool
+	}{
+		{
+			name: "should double out a last chequer",
+			args: openapi.BearoffArgs{
+				Board:  &openapi.Board{X: openapi.CheckerLayout{N1: toPtr(1)}, O: openapi.CheckerLayout{N1: toPtr(1)}},
+				Player: toPtr(openapi.BearoffArgsPlayer("o")),
+			},
+			wantDouble:   true,
+			wantRedouble: true,
+		},
+		{
+			name: "should hold at the start of a bearoff",
+			args: openapi.BearoffArgs{
+				Board: &openapi.Board{
+					X: openapi.CheckerLayout{N1: toPtr(2), N2: toPtr(2), N3: toPtr(2), N4: toPtr(3), N5: toPtr(3), N6: toPtr(3)},
+					O: openapi.CheckerLayout{N1: toPtr(2), N2: toPtr(2), N3: toPtr(2), N4: toPtr(3), N5: toPtr(3), N6: toPtr(3)},
+				},
+				Player: toPtr(openapi.BearoffArgsPlayer("x")),
+			},
+			wantTake: true,
+		},
+		{
+			name:    "should refuse a position without a player",
+			args:    openapi.BearoffArgs{Board: &openapi.Board{X: openapi.CheckerLayout{N1: toPtr(1)}, O: openapi.CheckerLayout{N1: toPtr(1)}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetBearoffCube(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBearoffCube() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Exact || got.Info == nil || got.Info.Plies != 3 {
+				t.Errorf("GetBearoffCube() exact %v, info %+v", got.Exact, got.Info)
+			}
+			if got.Double.Double != tt.wantDouble || got.Redouble.Double != tt.wantRedouble || got.Double.Take != tt.wantTake {
+				t.Errorf("GetBearoffCube() = %+v", got)
+			}
+		})
+	}
+}
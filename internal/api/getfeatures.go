@@ -0,0 +1,47 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+	"fmt"
+)
+
+// GetFeatures names the inputs of the neural net evaluating the position
+// of the arguments.
+func GetFeatures(args openapi.FeaturesArgs) (openapi.NetFeatures, error) {
+	var ret openapi.NetFeatures
+
+	pos, err := newPosition(featuresArgsPositionArgs(args))
+	if err != nil {
+		return ret, err
+	}
+
+	var board = pos.moverBoard()
+	var class = gnubg.PositionClass(board)
+
+	features, ok := gnubg.NetFeatures(board)
+	if !ok {
+		return ret, fmt.Errorf("no neural net evaluates %v positions", class)
+	}
+
+	/* the features are seen from the player on roll: index 1 is theirs */
+	var x, o = 1, 0
+	if pos.ms.Move == 0 {
+		x, o = 0, 1
+	}
+
+	ret.Class = openapi.NetFeaturesClass(class)
+	ret.X = openapi.Features{AdditionalProperties: features[x]}
+	ret.O = openapi.Features{AdditionalProperties: features[o]}
+
+	return ret, nil
+}
+
+// FeaturesArgsIDs returns the IDs of the position of the features.
+func FeaturesArgsIDs(args openapi.FeaturesArgs) (IDs, error) {
+	return positionIDs(featuresArgsPositionArgs(args))
+}
+
+func featuresArgsPositionArgs(args openapi.FeaturesArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, nil)
+}
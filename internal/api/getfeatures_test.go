@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"testing"
+)
+
+func TestGetFeatures(t *testing.T) {
+	once.Do(setup)
+
+	/* x has borne off 3 chequers */
+	var board = &openapi.Board{
+		X: openapi.CheckerLayout{N6: toPtr(4), N8: toPtr(3), N13: toPtr(3), N24: toPtr(2)},
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+	}
+
+	tests := []struct {
+		name      string
+		args      openapi.FeaturesArgs
+		wantClass openapi.NetFeaturesClass
+		wantX     map[string]float32 // features to check
+		wantO     map[string]float32
+		wantErr   bool
+	}{
+		{
+			name:      "should name the features with x on roll",
+			args:      openapi.FeaturesArgs{Board: board, Player: toPtr(openapi.FeaturesArgsPlayer("x"))},
+			wantClass: "contact",
+			wantX:     map[string]float32{"off1": 1, "timing": 0.25},
+			wantO:     map[string]float32{"off1": 0, "timing": 0.52},
+		},
+		{
+			name:      "should name the features with o on roll",
+			args:      openapi.FeaturesArgs{Board: board, Player: toPtr(openapi.FeaturesArgsPlayer("o"))},
+			wantClass: "contact",
+			wantX:     map[string]float32{"off1": 1, "timing": 0.25},
+			wantO:     map[string]float32{"off1": 0, "timing": 0.52},
+		},
+		{
+			name: "should name the race features",
+			args: openapi.FeaturesArgs{
+				Board:  &openapi.Board{X: openapi.CheckerLayout{N6: toPtr(3)}, O: openapi.CheckerLayout{N6: toPtr(5), N11: toPtr(5), N15: toPtr(5)}},
+				Player: toPtr(openapi.FeaturesArgsPlayer("o")),
+			},
+			wantClass: "race",
+			wantX:     map[string]float32{"menOff12": 1, "crossovers": 0},
+			wantO:     map[string]float32{"menOff12": 0, "crossovers": 1.5},
+		},
+		{
+			name: "should refuse a bearoff",
+			args: openapi.FeaturesArgs{
+				Board:  &openapi.Board{X: openapi.CheckerLayout{N1: toPtr(1)}, O: openapi.CheckerLayout{N1: toPtr(1)}},
+				Player: toPtr(openapi.FeaturesArgsPlayer("x")),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetFeatures(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFeatures() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Class != tt.wantClass {
+				t.Errorf("GetFeatures() class = %v, want %v", got.Class, tt.wantClass)
+			}
+			for name, want := range tt.wantX {
+				if v, ok := got.X.Get(name); !ok || v != want {
+					t.Errorf("GetFeatures() x %v = %v, want %v", name, v, want)
+				}
+			}
+			for name, want := range tt.wantO {
+				if v, ok := got.O.Get(name); !ok || v != want {
+					t.Errorf("GetFeatures() o %v = %v, want %v", name, v, want)
+				}
+			}
+		})
+	}
+}
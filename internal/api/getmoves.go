@@ -6,34 +6,53 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 )
 
 func GetMoves(args openapi.MoveArgs) ([]openapi.Move, error) {
-	var board = gnubg.TanBoard{
-		layoutToGNU(args.Board.X),
-		layoutToGNU(args.Board.O),
+	pos, err := moveArgsPosition(args)
+	if err != nil {
+		return nil, err
 	}
-	var dice = args.Dice
 
-	var player int = 1
+	moves, _, err := findMoves(pos, args)
+	return moves, err
+}
 
-	if args.Player == "o" {
-		player = 0
-	}
+// findMoves finds the moves of a position with dice, as the API returns
+// them and as gnubg found them, in the same order.
+func findMoves(pos position, args openapi.MoveArgs) ([]openapi.Move, []gnubg.Move, error) {
+	var err error
 
 	var maxMoves = fromPtr(args.MaxMoves, 9999)
 	var scoreMoves = fromPtr(args.ScoreMoves, true)
 	var cubeful = fromPtr(args.Cubeful, false)
+	var quantized = fromPtr(args.Quantized, gnubg.IsQuantized())
+
+	var flip = pos.flipNotation(string(fromPtr(args.Numbering, "")))
+
+	var comment = fromPtr(args.Commentary, false)
+	if comment && !scoreMoves {
+		return nil, nil, fmt.Errorf("commentary needs the moves scored")
+	}
 
-	var pml, err = gnubg.FindMoves(board, [2]int{dice[0], dice[1]}, player, scoreMoves, cubeful)
+	var routes map[routeKey]gnubg.Route
+	if scoreMoves && fromPtr(args.Explain, false) {
+		if routes, err = moveRoutes(pos, moveSettings(cubeful, quantized)); err != nil {
+			return nil, nil, fmt.Errorf("error explaining the moves: %v", err)
+		}
+	}
+
+	pml, err := gnubg.FindMoves(pos.board, pos.ms.Dice, pos.ms.Move, pos.ms.Cube, scoreMoves, cubeful, quantized)
 
 	if err != nil {
-		return nil, fmt.Errorf("error in gnubg.FindMoves(): %v", err)
+		return nil, nil, fmt.Errorf("error in gnubg.FindMoves(): %v", err)
 	}
 
 	var movesNum int = int(math.Min(float64(pml.GetMovesNum()), float64(maxMoves)))
 
 	var ret = make([]openapi.Move, 0, movesNum)
+	var found = make([]gnubg.Move, 0, movesNum)
 
 	var topMove gnubg.Move
 
@@ -42,76 +61,211 @@ func GetMoves(args openapi.MoveArgs) ([]openapi.Move, error) {
 		if i == 0 {
 			topMove = move
 		}
+		found = append(found, move)
 
 		// add return value
 		if scoreMoves {
-			evalInfo := move.GetEvalInfo()
-
-			ret = append(ret, openapi.Move{
-				Play: toPtr(playFromMove(move)),
-				Evaluation: &openapi.Evaluation{
-					Info: &openapi.EvalInfo{
-						Cubeful: evalInfo.Cubeful,
-						Plies:   evalInfo.Plies + 1,
-					},
-					Eq:   outputEquity(move.GetEquity()),
-					Diff: outputEquityDiff(move.GetEquity(), topMove.GetEquity()),
-					Probability: &openapi.Probability{
-						Win:    fformat(move.GetProbWin()),
-						WinG:   fformat(move.GetProbWinG()),
-						WinBG:  fformat(move.GetProbWinBG()),
-						Lose:   fformat(move.GetProbLose()),
-						LoseG:  fformat(move.GetProbLoseG()),
-						LoseBG: fformat(move.GetProbLoseBG()),
-					},
-				},
-			})
+			var m = pos.outputMove(move, topMove, flip)
+			if routes != nil {
+				m.Evaluation.Info.Route = toPtr(outputRoute(routes[routeKey{move.GetPositionID(), move.GetEvalInfo().Plies}]))
+			}
+			ret = append(ret, m)
 		} else {
 			ret = append(ret, openapi.Move{
-				Play: toPtr(playFromMove(move)),
+				Play:       toPtr(playFromMove(move)),
+				Notation:   toPtr(pos.notation(move, flip)),
+				PositionId: toPtr(openapi.PositionId(move.GetPositionID())),
+				Xgid:       toPtr(openapi.Xgid(pos.afterMove(move.GetBoard()).xgid())),
 			})
 		}
 	}
 
-	return ret, nil
-}
-
-func layoutToGNU(layout openapi.CheckerLayout) [25]int {
-	return [25]int{
-		fromPtr(layout.N1, 0),
-		fromPtr(layout.N2, 0),
-		fromPtr(layout.N3, 0),
-		fromPtr(layout.N4, 0),
-		fromPtr(layout.N5, 0),
-		fromPtr(layout.N6, 0),
-		fromPtr(layout.N7, 0),
-		fromPtr(layout.N8, 0),
-		fromPtr(layout.N9, 0),
-		fromPtr(layout.N10, 0),
-		fromPtr(layout.N11, 0),
-		fromPtr(layout.N12, 0),
-		fromPtr(layout.N13, 0),
-		fromPtr(layout.N14, 0),
-		fromPtr(layout.N15, 0),
-		fromPtr(layout.N16, 0),
-		fromPtr(layout.N17, 0),
-		fromPtr(layout.N18, 0),
-		fromPtr(layout.N19, 0),
-		fromPtr(layout.N20, 0),
-		fromPtr(layout.N21, 0),
-		fromPtr(layout.N22, 0),
-		fromPtr(layout.N23, 0),
-		fromPtr(layout.N24, 0),
-		fromPtr(layout.Bar, 0),
+	if comment {
+		if err := addCommentary(pos, ret, found, string(fromPtr(args.Language, openapi.MoveArgsLanguageEn))); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return ret, found, nil
+}
+
+// GetMovesText draws the position as gnubg's ASCII board, from the side of
+// perspective ("x", "o", or "" for the player on roll), and lists its
+// moves below it as gnubg's hint does.
+func GetMovesText(args openapi.MoveArgs, perspective string) (string, error) {
+	pos, err := moveArgsPosition(args)
+	if err != nil {
+		return "", err
+	}
+
+	moves, err := GetMoves(args)
+	if err != nil {
+		return "", err
+	}
+
+	board, err := pos.draw(perspective)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(board + "\n")
+	for i, move := range moves {
+		writeMoveText(&sb, i+1, move)
+	}
+
+	return sb.String(), nil
+}
+
+// writeMoveText writes a move ranked rank as gnubg's hint lists it: with
+// its equity, the difference from the best move's below the first, and
+// its chances on the next line.
+func writeMoveText(sb *strings.Builder, rank int, move openapi.Move) {
+	var play = fromPtr(move.Notation, "")
+
+	var ev = move.Evaluation
+	if ev == nil {
+		fmt.Fprintf(sb, "%5d. %v\n", rank, play)
+		return
+	}
+
+	var kind = "Cubeless"
+	if ev.Info != nil && ev.Info.Cubeful {
+		kind = "Cubeful"
+	}
+	if ev.Info != nil {
+		kind += fmt.Sprintf(" %v-ply", ev.Info.Plies)
+	}
+	fmt.Fprintf(sb, "%5d. %-16s %-28s Eq.: %+7.3f", rank, kind, play, ev.Eq)
+	if rank > 1 {
+		fmt.Fprintf(sb, " (%+7.3f)", ev.Diff)
+	}
+	sb.WriteString("\n")
+	if p := ev.Probability; p != nil {
+		fmt.Fprintf(sb, "       %.3f %.3f %.3f - %.3f %.3f %.3f\n", p.Win, p.WinG, p.WinBG, p.Lose, p.LoseG, p.LoseBG)
+	}
+}
+
+// IDs are the ways a position is written down.
+type IDs struct {
+	PositionID string
+	MatchID    string
+	XGID       string
+}
+
+// MoveArgsIDs returns the IDs of the position the moves are asked for.
+func MoveArgsIDs(args openapi.MoveArgs) (IDs, error) {
+	return positionIDs(moveArgsPositionArgs(args))
+}
+
+// The IDs of the position the arguments describe.
+func positionIDs(args positionArgs) (IDs, error) {
+	pos, err := newPosition(args)
+	if err != nil {
+		return IDs{}, err
+	}
+	return IDs{pos.positionID(), pos.matchID(), pos.xgid()}, nil
+}
+
+// MoveArgsBoard returns the board, in gnubg's {x, o} layout, and the
+// player on roll (1 for x, 0 for o) of the position; the dice may be
+// missing.
+func MoveArgsBoard(args openapi.MoveArgs) (gnubg.TanBoard, int, error) {
+	pos, err := newPosition(moveArgsPositionArgs(args))
+	return pos.board, pos.ms.Move, err
+}
+
+// The positionArgs of the fields every request describing a position has;
+// each request has its own type of player.
+func newPositionArgs[P ~string](board *openapi.Board, positionID *openapi.PositionId, matchID *openapi.MatchId, xgid *openapi.Xgid, fibsBoard *openapi.FibsBoard, player *P, dice *[]int) positionArgs {
+	return positionArgs{
+		board:      board,
+		positionID: positionID,
+		matchID:    matchID,
+		xgid:       xgid,
+		fibsBoard:  fibsBoard,
+		player:     string(fromPtr(player, "")),
+		dice:       dice,
+	}
+}
+
+func moveArgsPositionArgs(args openapi.MoveArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, args.Dice)
+}
+
+func moveArgsPosition(args openapi.MoveArgs) (position, error) {
+	pos, err := newPosition(moveArgsPositionArgs(args))
+	if err != nil {
+		return pos, err
+	}
+
+	return pos, checkDice(pos)
+}
+
+// Positions to play from need the dice, which not every way of giving one
+// has.
+func checkDice(pos position) error {
+	if pos.ms.Dice[0] == 0 {
+		return fmt.Errorf("dice, or a matchId, xgid or fibsBoard with dice, are required")
+	}
+	return nil
+}
+
+// a scored move of the position, with its equity against the top move's
+// and its notation counting points as flip tells
+func (p position) outputMove(move gnubg.Move, topMove gnubg.Move, flip bool) openapi.Move {
+	evalInfo := move.GetEvalInfo()
+
+	return openapi.Move{
+		Play:       toPtr(playFromMove(move)),
+		Notation:   toPtr(p.notation(move, flip)),
+		PositionId: toPtr(openapi.PositionId(move.GetPositionID())),
+		Xgid:       toPtr(openapi.Xgid(p.afterMove(move.GetBoard()).xgid())),
+		Evaluation: &openapi.Evaluation{
+			Info: &openapi.EvalInfo{
+				Cubeful: evalInfo.Cubeful,
+				Plies:   evalInfo.Plies + 1,
+			},
+			Eq:   outputEquity(move.GetEquity()),
+			Diff: outputEquityDiff(move.GetEquity(), topMove.GetEquity()),
+			Probability: &openapi.Probability{
+				Win:    fformat(move.GetProbWin()),
+				WinG:   fformat(move.GetProbWinG()),
+				WinBG:  fformat(move.GetProbWinBG()),
+				Lose:   fformat(move.GetProbLose()),
+				LoseG:  fformat(move.GetProbLoseG()),
+				LoseBG: fformat(move.GetProbLoseBG()),
+			},
+		},
 	}
 }
 
 func playFromMove(move gnubg.Move) []openapi.CheckerPlay {
-	var play = make([]openapi.CheckerPlay, 0, 4)
+	return outputPlay(moveChequers(move))
+}
+
+// the chequer moves of a move, a die at a time
+func moveChequers(move gnubg.Move) [][2]int {
+	var moves = make([][2]int, 0, 4)
 	for j := 0; j < move.GetPlaysNum(); j++ {
-		var ar = move.GetPlay(j)
-		var from = strconv.Itoa(ar[0] + 1)
-		var to = strconv.Itoa(ar[1] + 1)
+		moves = append(moves, move.GetPlay(j))
+	}
+	return moves
+}
+
+// a move in standard notation, counting points from the opponent of the
+// player on roll with flip
+func (p position) notation(move gnubg.Move, flip bool) string {
+	return gnubg.Notation(p.moverBoard(), moveChequers(move), flip)
+}
+
+// chequer moves, from and to points counted from 0 with 24 the bar and -1
+// off, as the API writes them
+func outputPlay(moves [][2]int) []openapi.CheckerPlay {
+	var play = make([]openapi.CheckerPlay, 0, len(moves))
+	for _, m := range moves {
+		var from = strconv.Itoa(m[0] + 1)
+		var to = strconv.Itoa(m[1] + 1)
 		if from == "25" {
 			from = "bar"
 		}
@@ -126,6 +280,15 @@ func playFromMove(move gnubg.Move) []openapi.CheckerPlay {
 	return play
 }
 
+// a play as gnubg writes it, such as "13/9 6/5"
+func playText(play []openapi.CheckerPlay) string {
+	var asz []string
+	for _, cp := range play {
+		asz = append(asz, string(cp.From)+"/"+string(cp.To))
+	}
+	return strings.Join(asz, " ")
+}
+
 func outputEquity(score float32) float32 {
 	return fformat(score)
 }
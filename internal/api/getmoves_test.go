@@ -5,6 +5,7 @@ import (
 	"bgweb-api/internal/openapi"
 	"os"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -31,12 +32,12 @@ func TestGetMoves(t *testing.T) {
 		{
 			name: "should get 3-1 with scores",
 			args: args{openapi.MoveArgs{
-				Board: openapi.Board{
+				Board: &openapi.Board{
 					X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
 					O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
 				},
-				Dice:       []int{3, 1},
-				Player:     "x",
+				Dice:       &[]int{3, 1},
+				Player:     toPtr(openapi.MoveArgsPlayer("x")),
 				MaxMoves:   toPtr(3),
 				ScoreMoves: toPtr(true),
 				Cubeful:    toPtr(true),
@@ -72,15 +73,65 @@ func TestGetMoves(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "should get 3-1 quantized",
+			args: args{openapi.MoveArgs{
+				Board: &openapi.Board{
+					X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+					O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+				},
+				Dice:       &[]int{3, 1},
+				Player:     toPtr(openapi.MoveArgsPlayer("x")),
+				MaxMoves:   toPtr(2),
+				ScoreMoves: toPtr(true),
+				Quantized:  toPtr(true),
+			}},
+			want: []openapi.Move{
+				{
+					Play: &[]openapi.CheckerPlay{{From: "8", To: "5"}, {From: "6", To: "5"}},
+					Evaluation: &openapi.Evaluation{
+						Info:        &openapi.EvalInfo{Cubeful: false, Plies: 1},
+						Eq:          0.153,
+						Diff:        0,
+						Probability: &openapi.Probability{Win: 0.548, WinG: 0.173, WinBG: 0.013, Lose: 0.452, LoseG: 0.124, LoseBG: 0.005},
+					},
+				},
+				{
+					Play: &[]openapi.CheckerPlay{{From: "13", To: "10"}, {From: "24", To: "23"}},
+					Evaluation: &openapi.Evaluation{
+						Info:        &openapi.EvalInfo{Cubeful: false, Plies: 1},
+						Eq:          -0.019,
+						Diff:        -0.172,
+						Probability: &openapi.Probability{Win: 0.493, WinG: 0.137, WinBG: 0.008, Lose: 0.507, LoseG: 0.143, LoseBG: 0.007},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "should get 3-1 without scores",
 			args: args{openapi.MoveArgs{
-				Board: openapi.Board{
+				Board: &openapi.Board{
 					O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
 					X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
 				},
-				Dice:       []int{3, 1},
-				Player:     "x",
+				Dice:       &[]int{3, 1},
+				Player:     toPtr(openapi.MoveArgsPlayer("x")),
+				MaxMoves:   toPtr(3),
+				ScoreMoves: toPtr(false),
+			}},
+			want: []openapi.Move{
+				{Play: &[]openapi.CheckerPlay{{From: "24", To: "21"}, {From: "24", To: "23"}}},
+				{Play: &[]openapi.CheckerPlay{{From: "24", To: "21"}, {From: "21", To: "20"}}},
+				{Play: &[]openapi.CheckerPlay{{From: "24", To: "21"}, {From: "8", To: "7"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should get 3-1 from IDs",
+			args: args{openapi.MoveArgs{
+				PositionId: toPtr(openapi.PositionId("4HPwATDgc/ABMA")),
+				MatchId:    toPtr(openapi.MatchId("cIkFAAAAAAAA")),
 				MaxMoves:   toPtr(3),
 				ScoreMoves: toPtr(false),
 			}},
@@ -91,15 +142,41 @@ func TestGetMoves(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "should fail without a board",
+			args: args{openapi.MoveArgs{
+				Dice:   &[]int{3, 1},
+				Player: toPtr(openapi.MoveArgsPlayer("x")),
+			}},
+			wantErr: true,
+		},
+		{
+			name: "should fail with both a board and a position ID",
+			args: args{openapi.MoveArgs{
+				Board:      &openapi.Board{},
+				PositionId: toPtr(openapi.PositionId("4HPwATDgc/ABMA")),
+				Dice:       &[]int{3, 1},
+				Player:     toPtr(openapi.MoveArgsPlayer("x")),
+			}},
+			wantErr: true,
+		},
+		{
+			name: "should fail without dice",
+			args: args{openapi.MoveArgs{
+				PositionId: toPtr(openapi.PositionId("4HPwATDgc/ABMA")),
+				MatchId:    toPtr(openapi.MatchId("cAkAAAAAAAAA")),
+			}},
+			wantErr: true,
+		},
 		{
 			name: "should consider player on bar",
 			args: args{openapi.MoveArgs{
-				Board: openapi.Board{
+				Board: &openapi.Board{
 					O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(4), N13: toPtr(4), N15: toPtr(1), N24: toPtr(1)},
 					X: openapi.CheckerLayout{N6: toPtr(5), N7: toPtr(2), N8: toPtr(3), N13: toPtr(2), N24: toPtr(2), Bar: toPtr(1)},
 				},
-				Dice:       []int{6, 1},
-				Player:     "x",
+				Dice:       &[]int{6, 1},
+				Player:     toPtr(openapi.MoveArgsPlayer("x")),
 				MaxMoves:   toPtr(9),
 				ScoreMoves: toPtr(false),
 			}},
@@ -114,12 +191,12 @@ func TestGetMoves(t *testing.T) {
 		{
 			name: "should bear off",
 			args: args{openapi.MoveArgs{
-				Board: openapi.Board{
+				Board: &openapi.Board{
 					X: openapi.CheckerLayout{N1: toPtr(1)},
 					O: openapi.CheckerLayout{N2: toPtr(1)},
 				},
-				Dice:       []int{6, 1},
-				Player:     "x",
+				Dice:       &[]int{6, 1},
+				Player:     toPtr(openapi.MoveArgsPlayer("x")),
 				MaxMoves:   toPtr(9),
 				ScoreMoves: toPtr(false),
 			}},
@@ -131,12 +208,12 @@ func TestGetMoves(t *testing.T) {
 		{
 			name: "should save gammon",
 			args: args{openapi.MoveArgs{
-				Board: openapi.Board{
+				Board: &openapi.Board{
 					X: openapi.CheckerLayout{N1: toPtr(1)},
 					O: openapi.CheckerLayout{N1: toPtr(4), N2: toPtr(3), N3: toPtr(1), N4: toPtr(2), N5: toPtr(2), N6: toPtr(3)},
 				},
-				Dice:       []int{4, 1},
-				Player:     "o",
+				Dice:       &[]int{4, 1},
+				Player:     toPtr(openapi.MoveArgsPlayer("o")),
 				MaxMoves:   toPtr(3),
 				ScoreMoves: toPtr(true),
 			}},
@@ -174,12 +251,12 @@ func TestGetMoves(t *testing.T) {
 		{
 			name: "should score x",
 			args: args{openapi.MoveArgs{
-				Board: openapi.Board{
+				Board: &openapi.Board{
 					X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(4), N13: toPtr(4), N23: toPtr(1), N24: toPtr(1)},
 					O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N21: toPtr(1), N24: toPtr(1)},
 				},
-				Dice:       []int{6, 5},
-				Player:     "x",
+				Dice:       &[]int{6, 5},
+				Player:     toPtr(openapi.MoveArgsPlayer("x")),
 				MaxMoves:   toPtr(3),
 				ScoreMoves: toPtr(true),
 			}},
@@ -237,3 +314,220 @@ func TestGetMoves(t *testing.T) {
 		})
 	}
 }
+
+func TestMoveArgsIDs(t *testing.T) {
+	once.Do(setup)
+
+	/* the starting position, x to play 3-1 */
+	var args = openapi.MoveArgs{
+		Board: &openapi.Board{
+			X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+			O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+		},
+		Dice:   &[]int{3, 1},
+		Player: toPtr(openapi.MoveArgsPlayer("x")),
+	}
+
+	var want = IDs{"4HPwATDgc/ABMA", "cIkFAAAAAAAA", "XGID=-b----E-C---eE---c-e----B-:0:0:1:31:0:0:3:0:10"}
+	ids, err := MoveArgsIDs(args)
+	if err != nil || ids != want {
+		t.Errorf("MoveArgsIDs() = %+v, %v, want %+v, nil", ids, err, want)
+	}
+
+	/* the IDs don't need the dice */
+	args.Dice = nil
+	want = IDs{"4HPwATDgc/ABMA", "cAkAAAAAAAAA", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:3:0:10"}
+	ids, err = MoveArgsIDs(args)
+	if err != nil || ids != want {
+		t.Errorf("MoveArgsIDs() = %+v, %v, want %+v, nil", ids, err, want)
+	}
+
+	/* o on roll instead, in a 5-point match */
+	args = openapi.MoveArgs{
+		PositionId: toPtr(openapi.PositionId("4HPwATDgc/ABMA")),
+		MatchId:    toPtr(openapi.MatchId("QYmlABAAGAAE")),
+		Player:     toPtr(openapi.MoveArgsPlayer("o")),
+	}
+
+	want = IDs{"4HPwATDgc/ABMA", "AYGlABAAGAAE", "XGID=-b----E-C---eE---c-e----B-:1:-1:-1:31:3:1:0:5:10"}
+	ids, err = MoveArgsIDs(args)
+	if err != nil || ids != want {
+		t.Errorf("MoveArgsIDs() = %+v, %v, want %+v, nil", ids, err, want)
+	}
+
+	moves, err := GetMoves(args)
+	if err != nil {
+		t.Fatalf("GetMoves() error = %v", err)
+	}
+	for _, m := range moves {
+		if m.PositionId == nil {
+			t.Fatalf("GetMoves() = %+v, want a position ID", m)
+		}
+		if _, err := gnubg.PositionFromID(string(*m.PositionId)); err != nil {
+			t.Errorf("GetMoves() position ID %v: %v", *m.PositionId, err)
+		}
+	}
+}
+
+func TestGetMovesXGID(t *testing.T) {
+	once.Do(setup)
+
+	/* the starting position, x to play 3-1 */
+	var args = openapi.MoveArgs{
+		Xgid:     toPtr(openapi.Xgid("XGID=-b----E-C---eE---c-e----B-:0:0:1:31:0:0:3:0:10")),
+		MaxMoves: toPtr(1),
+	}
+
+	ids, err := MoveArgsIDs(args)
+	if err != nil || ids.PositionID != "4HPwATDgc/ABMA" || ids.MatchID != "cIkFAAAAAAAA" {
+		t.Errorf("MoveArgsIDs() = %+v, %v, want 4HPwATDgc/ABMA, cIkFAAAAAAAA", ids, err)
+	}
+
+	moves, err := GetMoves(args)
+	if err != nil {
+		t.Fatalf("GetMoves() error = %v", err)
+	}
+
+	/* 8/5 6/5, o to roll */
+	var want = "XGID=-b---BD-B---eE---c-e----B-:0:0:-1:00:0:0:3:0:10"
+	if len(moves) != 1 || moves[0].Xgid == nil || string(*moves[0].Xgid) != want {
+		t.Errorf("GetMoves() = %+v, want xgid %v", moves, want)
+	}
+	if len(moves) == 1 && (moves[0].PositionId == nil || *moves[0].PositionId != "sGfwATDgc/ABMA") {
+		t.Errorf("GetMoves() = %+v, want position ID sGfwATDgc/ABMA", moves[0])
+	}
+
+	/* the xgid can't be mixed with a board */
+	args.PositionId = toPtr(openapi.PositionId("4HPwATDgc/ABMA"))
+	if _, err := GetMoves(args); err == nil {
+		t.Errorf("GetMoves() error = nil, want an error")
+	}
+}
+
+func TestGetMovesFibsBoard(t *testing.T) {
+	once.Do(setup)
+
+	/* the starting position of a 3 point match, O to play 6-2 */
+	var args = openapi.MoveArgs{
+		FibsBoard: toPtr(openapi.FibsBoard("board:You:someplayer:3:0:0:0:-2:0:0:0:0:5:0:3:0:0:0:-5:5:0:0:0:-3:0:-5:0:0:0:0:2:0:1:6:2:0:0:1:1:1:0:1:-1:0:25:0:0:0:0:2:0:0:0")),
+		MaxMoves:  toPtr(1),
+	}
+
+	ids, err := MoveArgsIDs(args)
+	if err != nil || ids.XGID != "XGID=-b----E-C---eE---c-e----B-:0:0:-1:62:0:0:0:3:10" {
+		t.Errorf("MoveArgsIDs() = %+v, %v, want o to play 6-2 in a 3 point match", ids, err)
+	}
+
+	moves, err := GetMoves(args)
+	if err != nil || len(moves) != 1 {
+		t.Fatalf("GetMoves() = %v, %v, want a move", moves, err)
+	}
+
+	/* the FIBS board can't be mixed with a Match ID */
+	args.MatchId = toPtr(openapi.MatchId("cAkAAAAAAAAA"))
+	if _, err := GetMoves(args); err == nil {
+		t.Errorf("GetMoves() error = nil, want an error")
+	}
+}
+
+func TestGetMovesText(t *testing.T) {
+	once.Do(setup)
+
+	var args = openapi.MoveArgs{
+		Xgid:     toPtr(openapi.Xgid("XGID=-b----E-C---eE---c-e----B-:0:0:1:31:0:0:3:0:10")),
+		MaxMoves: toPtr(2),
+	}
+
+	tests := []struct {
+		name        string
+		perspective string
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name: "should draw the board from the player on roll and list the moves",
+			want: []string{
+				"v|                  |BAR|                  |     (Cube: 1)",
+				" | O           X    |   | X              O |     Rolled 31",
+				" +12-11-10--9--8--7-------6--5--4--3--2--1-+     X\n",
+				"    1. Cubeless 1-ply   8/5 6/5                      Eq.:  +0.",
+				"    2. Cubeless 1-ply   ",
+			},
+		},
+		{
+			name:        "should draw the board from o",
+			perspective: "o",
+			want:        []string{"^|                  |BAR|                  |     (Cube: 1)"},
+		},
+		{
+			name:        "should refuse an unknown perspective",
+			perspective: "y",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetMovesText(args, tt.perspective)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetMovesText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(got, line) {
+					t.Errorf("GetMovesText() = \n%v\nwant %q", got, line)
+				}
+			}
+		})
+	}
+}
+
+func TestGetMovesNotation(t *testing.T) {
+	once.Do(setup)
+
+	var opening = &openapi.Board{
+		X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+	}
+	/* an o blot on x's 5-point */
+	var blot = &openapi.Board{
+		X: opening.X,
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N20: toPtr(1), N24: toPtr(1)},
+	}
+
+	tests := []struct {
+		name string
+		args openapi.MoveArgs
+		want string
+	}{
+		{
+			name: "should group identical moves",
+			args: openapi.MoveArgs{Board: opening, Dice: &[]int{6, 6}, Player: toPtr(openapi.MoveArgsPlayer("x"))},
+			want: "24/18(2) 13/7(2)",
+		},
+		{
+			name: "should mark a hit",
+			args: openapi.MoveArgs{Board: blot, Dice: &[]int{3, 1}, Player: toPtr(openapi.MoveArgsPlayer("x"))},
+			want: "8/5* 6/5",
+		},
+		{
+			name: "should count points from the opponent",
+			args: openapi.MoveArgs{Board: opening, Dice: &[]int{6, 6}, Player: toPtr(openapi.MoveArgsPlayer("x")), Numbering: toPtr(openapi.MoveArgsNumbering("o"))},
+			want: "1/7(2) 12/18(2)",
+		},
+		{
+			name: "should write the notation of unscored moves",
+			args: openapi.MoveArgs{Board: blot, Dice: &[]int{3, 1}, Player: toPtr(openapi.MoveArgsPlayer("x")), ScoreMoves: toPtr(false), MaxMoves: toPtr(1)},
+			want: "24/23 24/21",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetMoves(tt.args)
+			if err != nil {
+				t.Fatalf("GetMoves() error = %v", err)
+			}
+			if n := fromPtr(got[0].Notation, ""); n != tt.want {
+				t.Errorf("GetMoves()[0].Notation = %q, want %q", n, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+	"fmt"
+	"strings"
+)
+
+// GetRace works out the racing counts of the position of the arguments.
+func GetRace(args openapi.RaceArgs) (openapi.RaceInfo, error) {
+	var ret openapi.RaceInfo
+
+	pos, err := newPosition(raceArgsPositionArgs(args))
+	if err != nil {
+		return ret, err
+	}
+
+	race, err := gnubg.AnalyseRace(pos.moverBoard())
+	if err != nil {
+		return ret, err
+	}
+
+	/* the race is seen from the player on roll: index 1 is theirs */
+	var x, o = 1, 0
+	if pos.ms.Move == 0 {
+		x, o = 0, 1
+	}
+
+	ret.Race = race.Race
+	ret.Pips = openapi.PipCounts{X: race.Pips[x], O: race.Pips[o]}
+	ret.Keith = openapi.RaceCount{X: race.Keith[x], O: race.Keith[o], Adjusted: fformat(race.KeithAdjusted), Action: raceAction(race.KeithAction)}
+	ret.Thorp = openapi.RaceCount{X: race.Thorp[x], O: race.Thorp[o], Adjusted: fformat(race.ThorpAdjusted), Action: raceAction(race.ThorpAction)}
+	ret.Kleinman = openapi.KleinmanCount{K: fformat(race.Kleinman), Win: fformat(race.KleinmanWin), Action: raceAction(race.KleinmanAction)}
+
+	var epc = func(i int) *openapi.EffectivePipCount {
+		if !race.Bearoff[i] {
+			return nil
+		}
+		return &openapi.EffectivePipCount{
+			Epc:     fformat(race.EPC[i]),
+			Wastage: fformat(race.Wastage[i]),
+			Rolls:   fformat(race.Rolls[i]),
+			RollsSd: fformat(race.RollsSD[i]),
+		}
+	}
+	ret.Epc = openapi.EffectivePipCounts{X: epc(x), O: epc(o)}
+
+	return ret, nil
+}
+
+// GetRaceText draws the position as gnubg's ASCII board, from the side of
+// perspective ("x", "o", or "" for the player on roll), and writes its
+// racing counts below the pip counts the board ends with.
+func GetRaceText(args openapi.RaceArgs, perspective string) (string, error) {
+	pos, err := newPosition(raceArgsPositionArgs(args))
+	if err != nil {
+		return "", err
+	}
+
+	race, err := GetRace(args)
+	if err != nil {
+		return "", err
+	}
+
+	board, err := pos.draw(perspective)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(board + "\n")
+	fmt.Fprintf(&sb, " Keith       X %v, O %v, adjusted %.3f: %v\n", race.Keith.X, race.Keith.O, race.Keith.Adjusted, raceActionText(race.Keith.Action))
+	fmt.Fprintf(&sb, " Thorp       X %v, O %v, adjusted %.3f: %v\n", race.Thorp.X, race.Thorp.O, race.Thorp.Adjusted, raceActionText(race.Thorp.Action))
+	fmt.Fprintf(&sb, " Kleinman    K %.3f, wins %.3f: %v\n", race.Kleinman.K, race.Kleinman.Win, raceActionText(race.Kleinman.Action))
+	for _, epc := range []struct {
+		player string
+		epc    *openapi.EffectivePipCount
+	}{{"X", race.Epc.X}, {"O", race.Epc.O}} {
+		if epc.epc != nil {
+			fmt.Fprintf(&sb, " EPC         %v %.2f, wastage %.2f, %.3f rolls (sd %.3f)\n", epc.player, epc.epc.Epc, epc.epc.Wastage, epc.epc.Rolls, epc.epc.RollsSd)
+		}
+	}
+	if !race.Race {
+		sb.WriteString(" There is still contact: the formulas are meant for races.\n")
+	}
+
+	return sb.String(), nil
+}
+
+// RaceArgsIDs returns the IDs of the position counted.
+func RaceArgsIDs(args openapi.RaceArgs) (IDs, error) {
+	return positionIDs(raceArgsPositionArgs(args))
+}
+
+func raceArgsPositionArgs(args openapi.RaceArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, nil)
+}
+
+// The cube action of a formula, for the player on roll and their opponent.
+func raceActionText(a openapi.RaceAction) string {
+	var asz []string
+	for _, action := range []struct {
+		ok      bool
+		yes, no string
+	}{{a.Double, "double", "no double"}, {a.Redouble, "redouble", "no redouble"}, {a.Take, "take", "pass"}} {
+		if action.ok {
+			asz = append(asz, action.yes)
+		} else {
+			asz = append(asz, action.no)
+		}
+	}
+	return strings.Join(asz, ", ")
+}
+
+func raceAction(d gnubg.RaceDecision) openapi.RaceAction {
+	return openapi.RaceAction{Double: d.Double, Redouble: d.Redouble, Take: d.Take}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"strings"
+	"testing"
+)
+
+func TestGetRace(t *testing.T) {
+	once.Do(setup)
+
+	/* x 80 pips to o's 90, with x's chequers all in the home board */
+	var race = &openapi.Board{
+		X: openapi.CheckerLayout{N5: toPtr(4), N6: toPtr(10)},
+		O: openapi.CheckerLayout{N6: toPtr(15)},
+	}
+
+	tests := []struct {
+		name    string
+		args    openapi.RaceArgs
+		check   func(t *testing.T, got openapi.RaceInfo)
+		wantErr bool
+	}{
+		{
+			name: "should count a race for x",
+			args: openapi.RaceArgs{Board: race, Player: toPtr(openapi.RaceArgsPlayer("x"))},
+			check: func(t *testing.T, got openapi.RaceInfo) {
+				if !got.Race || got.Pips != (openapi.PipCounts{X: 80, O: 90}) {
+					t.Errorf("GetRace() = %+v", got)
+				}
+				if got.Keith.X != 81 || got.Keith.O != 92 || got.Keith.Adjusted != 92.571 {
+					t.Errorf("GetRace() Keith = %+v", got.Keith)
+				}
+				if !got.Kleinman.Action.Double || !got.Kleinman.Action.Take {
+					t.Errorf("GetRace() Kleinman = %+v", got.Kleinman)
+				}
+				if got.Epc.X == nil || got.Epc.O == nil || got.Epc.X.Epc <= 80 || got.Epc.O.Epc <= 90 {
+					t.Errorf("GetRace() EPC = %+v, %+v", got.Epc.X, got.Epc.O)
+				}
+			},
+		},
+		{
+			name: "should count the same race with o on roll",
+			args: openapi.RaceArgs{Board: race, Player: toPtr(openapi.RaceArgsPlayer("o"))},
+			check: func(t *testing.T, got openapi.RaceInfo) {
+				if got.Pips != (openapi.PipCounts{X: 80, O: 90}) || got.Keith.X != 81 || got.Keith.O != 92 {
+					t.Errorf("GetRace() = %+v", got)
+				}
+				/* o is 10 pips behind */
+				if got.Kleinman.K != 0 || got.Kleinman.Action.Double || got.Kleinman.Win > 0.4 {
+					t.Errorf("GetRace() Kleinman = %+v", got.Kleinman)
+				}
+			},
+		},
+		{
+			name: "should leave out the EPC outside the bearoff database",
+			args: openapi.RaceArgs{Xgid: toPtr(openapi.Xgid("XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:3:0:10"))},
+			check: func(t *testing.T, got openapi.RaceInfo) {
+				if got.Race || got.Pips != (openapi.PipCounts{X: 167, O: 167}) || got.Epc.X != nil || got.Epc.O != nil {
+					t.Errorf("GetRace() = %+v", got)
+				}
+			},
+		},
+		{
+			name:    "should refuse a position without a player",
+			args:    openapi.RaceArgs{Board: race},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetRace(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetRace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, got)
+			}
+		})
+	}
+}
+
+func TestGetRaceText(t *testing.T) {
+	once.Do(setup)
+
+	var args = openapi.RaceArgs{
+		Board: &openapi.Board{
+			X: openapi.CheckerLayout{N5: toPtr(4), N6: toPtr(10)},
+			O: openapi.CheckerLayout{N6: toPtr(15)},
+		},
+		Player: toPtr(openapi.RaceArgsPlayer("x")),
+	}
+
+	got, err := GetRaceText(args, "")
+	if err != nil {
+		t.Fatalf("GetRaceText() error = %v", err)
+	}
+	for _, line := range []string{
+		" Pip counts: O 90, X 80\n",
+		" Keith       X 81, O 92, adjusted 92.571: ",
+		" EPC         X ",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("GetRaceText() = \n%v\nwant %q", got, line)
+		}
+	}
+	if strings.Contains(got, "contact") {
+		t.Errorf("GetRaceText() = \n%v\nwant no contact", got)
+	}
+}
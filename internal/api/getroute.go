@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+)
+
+// the evaluation of the position after each move, as gnubg.FindMoves
+// scores them
+func moveSettings(cubeful bool, quantized bool) gnubg.Settings {
+	return gnubg.Settings{Plies: 2, Cubeful: cubeful, Prune: true, Deterministic: true, Quantized: quantized}
+}
+
+// GetRoute explains how the engine evaluates the position of the
+// arguments, with the player on roll before rolling.
+func GetRoute(args openapi.RouteArgs) (openapi.Route, error) {
+	pos, err := newPosition(routeArgsPositionArgs(args))
+	if err != nil {
+		return openapi.Route{}, err
+	}
+
+	var s = moveSettings(fromPtr(args.Cubeful, false), fromPtr(args.Quantized, gnubg.IsQuantized()))
+	route, err := gnubg.ExplainEvaluation(pos.moverBoard(), pos.ms.Move, pos.ms.Cube, s)
+	if err != nil {
+		return openapi.Route{}, err
+	}
+
+	return outputRoute(route), nil
+}
+
+// RouteArgsIDs returns the IDs of the position whose route is asked for.
+func RouteArgsIDs(args openapi.RouteArgs) (IDs, error) {
+	return positionIDs(routeArgsPositionArgs(args))
+}
+
+func routeArgsPositionArgs(args openapi.RouteArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, nil)
+}
+
+type routeKey struct {
+	positionID string
+	plies      int
+}
+
+// moveRoutes works out the routes of the positions after each move, for
+// every plies the move filters may stop scoring them at. They must be
+// worked out before the moves are scored to tell what the cache held.
+func moveRoutes(pos position, s gnubg.Settings) (map[routeKey]gnubg.Route, error) {
+	pml, err := gnubg.FindMoves(pos.board, pos.ms.Dice, pos.ms.Move, pos.ms.Cube, false, s.Cubeful, s.Quantized)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret = map[routeKey]gnubg.Route{}
+	var nPlies = s.Plies
+	for i := 0; i < pml.GetMovesNum(); i++ {
+		var move = pml.GetMove(i)
+		for s.Plies = 0; s.Plies <= nPlies; s.Plies++ {
+			route, err := gnubg.ExplainEvaluation(move.GetBoard(), 1-pos.ms.Move, pos.ms.Cube, s)
+			if err != nil {
+				return nil, err
+			}
+			ret[routeKey{move.GetPositionID(), s.Plies}] = route
+		}
+	}
+
+	return ret, nil
+}
+
+func outputRoute(route gnubg.Route) openapi.Route {
+	return openapi.Route{
+		Class:          openapi.RouteClass(route.Class),
+		Evaluator:      route.Evaluator,
+		Plies:          route.Plies + 1,
+		SanityAdjusted: route.SanityAdjusted,
+		Cached:         route.Cached,
+	}
+}
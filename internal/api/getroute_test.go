@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"testing"
+)
+
+func TestGetRoute(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name    string
+		args    openapi.RouteArgs
+		want    openapi.Route
+		wantErr bool
+	}{
+		{
+			name: "should search the contact net",
+			args: openapi.RouteArgs{
+				Board: &openapi.Board{
+					X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+					O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+				},
+				Player:    toPtr(openapi.RouteArgsPlayer("x")),
+				Cubeful:   toPtr(true),
+				Quantized: toPtr(false),
+			},
+			want: openapi.Route{Class: "contact", Evaluator: "contact net", Plies: 3},
+		},
+		{
+			name: "should sanity check a race without gammons",
+			args: openapi.RouteArgs{
+				Board: &openapi.Board{
+					X: openapi.CheckerLayout{N6: toPtr(5), N11: toPtr(5), N15: toPtr(5)},
+					O: openapi.CheckerLayout{N6: toPtr(3)},
+				},
+				Player:    toPtr(openapi.RouteArgsPlayer("x")),
+				Cubeful:   toPtr(true),
+				Quantized: toPtr(true),
+			},
+			want: openapi.Route{Class: "race", Evaluator: "quantized race net", Plies: 3, SanityAdjusted: true},
+		},
+		{
+			name: "should read the one-sided database",
+			args: openapi.RouteArgs{
+				Board:  &openapi.Board{X: openapi.CheckerLayout{N1: toPtr(1)}, O: openapi.CheckerLayout{N6: toPtr(1)}},
+				Player: toPtr(openapi.RouteArgsPlayer("o")),
+			},
+			want: openapi.Route{Class: "bearoff1", Evaluator: "gnubg_os0.bd", Plies: 3},
+		},
+		{
+			name:    "should refuse a position without a player",
+			args:    openapi.RouteArgs{Board: &openapi.Board{X: openapi.CheckerLayout{N1: toPtr(1)}, O: openapi.CheckerLayout{N1: toPtr(1)}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetRoute(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetRoute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetRoute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMovesExplain(t *testing.T) {
+	once.Do(setup)
+
+	/* a position no other test evaluates, so nothing is cached before the
+	 * first call */
+	var args = openapi.MoveArgs{
+		Board: &openapi.Board{
+			X: openapi.CheckerLayout{N5: toPtr(1), N6: toPtr(4), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+			O: openapi.CheckerLayout{N6: toPtr(5), N7: toPtr(1), N8: toPtr(2), N13: toPtr(5), N24: toPtr(2)},
+		},
+		Dice:      &[]int{4, 2},
+		Player:    toPtr(openapi.MoveArgsPlayer("x")),
+		Quantized: toPtr(false),
+		Explain:   toPtr(true),
+	}
+
+	for i, wantCached := range []bool{false, true} {
+		moves, err := GetMoves(args)
+		if err != nil {
+			t.Fatalf("GetMoves() error = %v", err)
+		}
+		for _, move := range moves {
+			var info = move.Evaluation.Info
+			if info.Route == nil {
+				t.Fatalf("GetMoves() call %v has no route: %+v", i+1, info)
+			}
+			var route = *info.Route
+			if route.Class != "contact" || route.Evaluator != "contact net" || route.Plies != info.Plies || route.Cached != wantCached {
+				t.Errorf("GetMoves() call %v route %+v, info %+v, want cached %v", i+1, route, info, wantCached)
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetShots counts the rolls on which the opponent hits the blots of the
+// player of the arguments and, with dice, ranks the player's moves by the
+// shots they leave.
+func GetShots(args openapi.ShotsArgs) (openapi.ShotsInfo, error) {
+	var ret openapi.ShotsInfo
+
+	pos, err := newPosition(shotsArgsPositionArgs(args))
+	if err != nil {
+		return ret, err
+	}
+
+	/* the player's side of the {x, o} board */
+	var side = 1 - pos.ms.Move
+
+	ret.Player = openapi.ShotsInfoPlayer("o")
+	if pos.ms.Move == 1 {
+		ret.Player = openapi.ShotsInfoPlayer("x")
+	}
+	ret.Shots = outputShots(gnubg.CountShots(pos.board, side))
+
+	if pos.ms.Dice[0] == 0 {
+		return ret, nil
+	}
+
+	pml, err := gnubg.FindMoves(pos.board, pos.ms.Dice, pos.ms.Move, pos.ms.Cube, false, false, false)
+	if err != nil {
+		return ret, fmt.Errorf("error in gnubg.FindMoves(): %v", err)
+	}
+
+	var ret2 = make([]openapi.MoveShots, 0, pml.GetMovesNum())
+	for i := 0; i < pml.GetMovesNum(); i++ {
+		var move = pml.GetMove(i)
+		var after = pos.afterMove(move.GetBoard())
+
+		ret2 = append(ret2, openapi.MoveShots{
+			Play:       toPtr(playFromMove(move)),
+			PositionId: toPtr(openapi.PositionId(move.GetPositionID())),
+			Xgid:       toPtr(openapi.Xgid(after.xgid())),
+			Shots:      outputShots(gnubg.CountShots(after.board, side)),
+		})
+	}
+
+	/* safest first; the moves are otherwise left in the order they were
+	 * generated */
+	sort.SliceStable(ret2, func(i, j int) bool {
+		var a, b = ret2[i].Shots, ret2[j].Shots
+		if a.Hits != b.Hits {
+			return a.Hits < b.Hits
+		}
+		return a.DoubleHits < b.DoubleHits
+	})
+	if n := fromPtr(args.MaxMoves, len(ret2)); n < len(ret2) {
+		ret2 = ret2[:n]
+	}
+	ret.Moves = &ret2
+
+	return ret, nil
+}
+
+// GetShotsText draws the position as gnubg's ASCII board, from the side of
+// perspective ("x", "o", or "" for the player on roll), and lists the
+// shots at the player's blots below it and, with dice, the shots each move
+// leaves.
+func GetShotsText(args openapi.ShotsArgs, perspective string) (string, error) {
+	pos, err := newPosition(shotsArgsPositionArgs(args))
+	if err != nil {
+		return "", err
+	}
+
+	shots, err := GetShots(args)
+	if err != nil {
+		return "", err
+	}
+
+	board, err := pos.draw(perspective)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	var line = func(format string, a ...interface{}) {
+		sb.WriteString(strings.TrimRight(fmt.Sprintf(format, a...), " ") + "\n")
+	}
+
+	var s = shots.Shots
+	sb.WriteString(board + "\n")
+	line(" Shots at %v   %2d/36  %v", strings.ToUpper(string(shots.Player)), s.Hits, rollsText(s.Rolls))
+	for _, b := range s.Blots {
+		var direct = "indirect"
+		if b.Direct {
+			direct = "direct"
+		}
+		line("   %2d-point   %2d/36  %v (%v)", b.Point, b.Shots, rollsText(b.Rolls), direct)
+	}
+	line(" Two at once  %2d/36  %v", s.DoubleHits, rollsText(s.DoubleHitRolls))
+	line(" Entering     %2d/36", s.Enter)
+
+	if shots.Moves != nil {
+		sb.WriteString("\n")
+	}
+	for i, move := range fromPtr(shots.Moves, nil) {
+		line("%5d. %-28s %2d/36, two at once %2d/36", i+1, playText(fromPtr(move.Play, nil)), move.Shots.Hits, move.Shots.DoubleHits)
+	}
+
+	return sb.String(), nil
+}
+
+// rolls as gnubg writes them, such as "64 31 22"
+func rollsText(rolls []openapi.Roll) string {
+	var asz = make([]string, len(rolls))
+	for i, r := range rolls {
+		asz[i] = fmt.Sprintf("%v%v", r[0], r[1])
+	}
+	return strings.Join(asz, " ")
+}
+
+// ShotsArgsIDs returns the IDs of the position whose shots are counted.
+func ShotsArgsIDs(args openapi.ShotsArgs) (IDs, error) {
+	return positionIDs(shotsArgsPositionArgs(args))
+}
+
+func shotsArgsPositionArgs(args openapi.ShotsArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, args.Dice)
+}
+
+func outputShots(s gnubg.Shots) openapi.HitChances {
+	var ret = openapi.HitChances{
+		Hits:                 s.Shots,
+		Probability:          fformat(float32(s.Shots) / 36),
+		Rolls:                outputRolls(s.Rolls),
+		DoubleHits:           s.DoubleHits,
+		DoubleHitProbability: fformat(float32(s.DoubleHits) / 36),
+		DoubleHitRolls:       outputRolls(s.DoubleRolls),
+		Blots:                make([]openapi.BlotShots, 0, len(s.Blots)),
+		Enter:                s.Enter,
+		EnterProbability:     fformat(float32(s.Enter) / 36),
+	}
+	for _, b := range s.Blots {
+		ret.Blots = append(ret.Blots, openapi.BlotShots{
+			Point:       b.Point,
+			Shots:       b.Shots,
+			Probability: fformat(float32(b.Shots) / 36),
+			Rolls:       outputRolls(b.Rolls),
+			Direct:      b.Direct,
+		})
+	}
+	return ret
+}
+
+func outputRolls(rolls [][2]int) []openapi.Roll {
+	var ret = make([]openapi.Roll, 0, len(rolls))
+	for _, r := range rolls {
+		ret = append(ret, openapi.Roll{r[0], r[1]})
+	}
+	return ret
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetShots(t *testing.T) {
+	once.Do(setup)
+
+	var opening = openapi.Board{
+		X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+	}
+
+	tests := []struct {
+		name      string
+		args      openapi.ShotsArgs
+		wantHits  int
+		wantBlots []openapi.BlotShots
+		wantMoves []int // shots left by each move
+		wantErr   bool
+	}{
+		{
+			name: "should count the shots at a slotted 5-point",
+			args: openapi.ShotsArgs{
+				Board: &openapi.Board{
+					X: openapi.CheckerLayout{N5: toPtr(1), N6: toPtr(4), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+					O: opening.O,
+				},
+				Player: toPtr(openapi.ShotsArgsPlayer("x")),
+			},
+			wantHits: 15,
+			wantBlots: []openapi.BlotShots{{
+				Point: 5, Shots: 15, Probability: 0.417, Direct: true,
+				Rolls: []openapi.Roll{{6, 4}, {5, 4}, {4, 4}, {4, 3}, {4, 2}, {4, 1}, {3, 1}, {2, 2}, {1, 1}},
+			}},
+		},
+		{
+			name:      "should rank the moves safest first",
+			args:      openapi.ShotsArgs{Board: &opening, Player: toPtr(openapi.ShotsArgsPlayer("x")), Dice: &[]int{6, 4}, MaxMoves: toPtr(3)},
+			wantBlots: []openapi.BlotShots{},
+			wantMoves: []int{0, 12, 17},
+		},
+		{
+			name:    "should refuse a position without a player",
+			args:    openapi.ShotsArgs{Board: &opening},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetShots(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetShots() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Player != "x" || got.Shots.Hits != tt.wantHits || !reflect.DeepEqual(got.Shots.Blots, tt.wantBlots) || got.Shots.Enter != 35 {
+				t.Errorf("GetShots() = %+v", got.Shots)
+			}
+
+			if tt.wantMoves == nil {
+				if got.Moves != nil {
+					t.Errorf("GetShots() moves = %+v, want none", *got.Moves)
+				}
+				return
+			}
+			var moves []int
+			for _, m := range *got.Moves {
+				moves = append(moves, m.Shots.Hits)
+			}
+			if !reflect.DeepEqual(moves, tt.wantMoves) {
+				t.Errorf("GetShots() moves leave %v shots, want %v", moves, tt.wantMoves)
+			}
+		})
+	}
+}
+
+func TestGetShotsText(t *testing.T) {
+	once.Do(setup)
+
+	var args = openapi.ShotsArgs{
+		Board: &openapi.Board{
+			X: openapi.CheckerLayout{N5: toPtr(1), N6: toPtr(4), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+			O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+		},
+		Player:   toPtr(openapi.ShotsArgsPlayer("x")),
+		Dice:     &[]int{3, 1},
+		MaxMoves: toPtr(2),
+	}
+
+	got, err := GetShotsText(args, "")
+	if err != nil {
+		t.Fatalf("GetShotsText() error = %v", err)
+	}
+	for _, line := range []string{
+		" Shots at X   15/36  64 54 44 43 42 41 31 22 11\n",
+		"    5-point   15/36  64 54 44 43 42 41 31 22 11 (direct)\n",
+		" Two at once   0/36\n",
+		" Entering     35/36\n",
+		"    1. 8/5 6/5                       0/36, two at once  0/36\n",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("GetShotsText() = \n%v\nwant %q", got, line)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+	"fmt"
+	"strings"
+)
+
+// GetStructure describes the structure of the position of the arguments
+// and, with dice, of the position after each of its moves.
+func GetStructure(args openapi.MoveArgs) (openapi.StructureInfo, error) {
+	var ret openapi.StructureInfo
+
+	pos, err := newPosition(moveArgsPositionArgs(args))
+	if err != nil {
+		return ret, err
+	}
+
+	var x, o = pos.structure()
+	ret.X, ret.O = outputStructure(x), outputStructure(o)
+
+	if pos.ms.Dice[0] == 0 {
+		return ret, nil
+	}
+
+	moves, found, err := findMoves(pos, args)
+	if err != nil {
+		return ret, err
+	}
+
+	/* the mover's chequers and their opponent's, before the move */
+	var mover, opp = byMover(x, o, pos.ms.Move)
+
+	var best gnubg.Structure
+	var bestHits int
+	var ret2 = make([]openapi.MoveStructure, 0, len(moves))
+	for i, move := range moves {
+		var ax, ao = pos.afterMove(found[i].GetBoard()).structure()
+		var m, mo = byMover(ax, ao, pos.ms.Move)
+		var hits = mo.Bar - opp.Bar
+
+		if i == 0 {
+			best, bestHits = m, hits
+		}
+
+		ret2 = append(ret2, openapi.MoveStructure{
+			Play:       fromPtr(move.Play, nil),
+			Evaluation: move.Evaluation,
+			Hits:       hits,
+			X:          outputStructure(ax),
+			O:          outputStructure(ao),
+			Comparison: compareStructures(mover, best, m, bestHits, hits),
+		})
+	}
+	ret.Moves = &ret2
+
+	return ret, nil
+}
+
+// GetStructureText draws the position as gnubg's ASCII board, from the
+// side of perspective ("x", "o", or "" for the player on roll), and
+// describes the structure of both sides below it and, with dice, how each
+// move compares with the best.
+func GetStructureText(args openapi.MoveArgs, perspective string) (string, error) {
+	pos, err := newPosition(moveArgsPositionArgs(args))
+	if err != nil {
+		return "", err
+	}
+
+	structure, err := GetStructure(args)
+	if err != nil {
+		return "", err
+	}
+
+	board, err := pos.draw(perspective)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(board + "\n")
+	fmt.Fprintf(&sb, " X  %v\n", structureText(structure.X))
+	fmt.Fprintf(&sb, " O  %v\n", structureText(structure.O))
+
+	if structure.Moves != nil {
+		sb.WriteString("\n")
+	}
+	for i, move := range fromPtr(structure.Moves, nil) {
+		var line = fmt.Sprintf("%5d. %-28s %v", i+1, playText(move.Play), strings.Join(move.Comparison, ", "))
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	return sb.String(), nil
+}
+
+// the structure of one side on a line, such as "shots 15/36, blots 5 (15),
+// points 6 8 13 24, ..."
+func structureText(s openapi.Structure) string {
+	var asz = []string{fmt.Sprintf("shots %v/36", s.Shots)}
+
+	if len(s.Blots) > 0 {
+		var blots []string
+		for _, b := range s.Blots {
+			blots = append(blots, fmt.Sprintf("%v (%v)", b.Point, b.Shots))
+		}
+		asz = append(asz, "blots "+strings.Join(blots, " "))
+	}
+	if len(s.Points) > 0 {
+		asz = append(asz, "points "+pointsText(s.Points))
+	}
+	if len(s.Anchors) > 0 {
+		asz = append(asz, "anchors "+pointsText(s.Anchors))
+	}
+	if s.Prime.Length > 1 {
+		asz = append(asz, fmt.Sprintf("%v-point prime %v-%v", s.Prime.Length, s.Prime.From, s.Prime.To))
+	}
+	if s.Bar > 0 {
+		asz = append(asz, fmt.Sprintf("%v on the bar", s.Bar))
+	}
+	asz = append(asz, fmt.Sprintf("escapes %v/36", s.Escapes), fmt.Sprintf("builders %v", s.Builders))
+	if len(s.Stripped) > 0 {
+		asz = append(asz, "stripped "+pointsText(s.Stripped))
+	}
+
+	return strings.Join(asz, ", ")
+}
+
+func pointsText(points []int) string {
+	var asz = make([]string, len(points))
+	for i, p := range points {
+		asz[i] = fmt.Sprint(p)
+	}
+	return strings.Join(asz, " ")
+}
+
+// the structure of x's and o's chequers
+func (p position) structure() (gnubg.Structure, gnubg.Structure) {
+	var s = gnubg.AnalyseStructure(p.board)
+	return s[0], s[1]
+}
+
+// x's and o's structures as the mover's and their opponent's, with move
+// the player on roll
+func byMover(x, o gnubg.Structure, move int) (gnubg.Structure, gnubg.Structure) {
+	if move == 0 {
+		return o, x
+	}
+	return x, o
+}
+
+func outputStructure(s gnubg.Structure) openapi.Structure {
+	var ret = openapi.Structure{
+		Blots:    make([]openapi.Blot, 0, len(s.Blots)),
+		Shots:    s.Shots,
+		Points:   append([]int{}, s.Points...),
+		Anchors:  append([]int{}, s.Anchors...),
+		Bar:      s.Bar,
+		Escapes:  s.Escapes,
+		Builders: s.Builders,
+		Stripped: append([]int{}, s.Stripped...),
+	}
+	for _, b := range s.Blots {
+		ret.Blots = append(ret.Blots, openapi.Blot{Point: b.Point, Shots: b.Shots, Direct: b.Direct})
+	}
+	if s.Prime > 0 {
+		ret.Prime = openapi.Prime{Length: s.Prime, From: s.PrimeFrom, To: s.PrimeFrom + s.Prime - 1}
+	}
+	return ret
+}
+
+// compareStructures tells how the mover's chequers after a move differ
+// from after the best move, from before the move to tell a point broken
+// from one not made.
+func compareStructures(before, best, s gnubg.Structure, bestHits, hits int) []string {
+	var ret = []string{}
+
+	switch d := hits - bestHits; {
+	case bestHits == 0 && hits > 0:
+		ret = append(ret, "hits "+plural(hits, "chequer"))
+	case hits == 0 && bestHits > 0:
+		ret = append(ret, "doesn't hit")
+	case d != 0:
+		ret = append(ret, "hits "+comparative(d, "chequer"))
+	}
+
+	if d := s.Shots - best.Shots; d != 0 {
+		ret = append(ret, "leaves "+comparative(d, "shot"))
+	}
+	if d := len(s.Blots) - len(best.Blots); d != 0 {
+		ret = append(ret, "leaves "+comparative(d, "blot"))
+	}
+
+	for _, p := range best.Points {
+		if contains(s.Points, p) {
+			continue
+		}
+		if contains(before.Points, p) {
+			ret = append(ret, "breaks the "+pointName(p))
+		} else {
+			ret = append(ret, "doesn't make the "+pointName(p))
+		}
+	}
+	for _, p := range s.Points {
+		if contains(best.Points, p) {
+			continue
+		}
+		if contains(before.Points, p) {
+			ret = append(ret, "keeps the "+pointName(p))
+		} else {
+			ret = append(ret, "makes the "+pointName(p))
+		}
+	}
+
+	if s.Prime != best.Prime && (s.Prime > 1 || best.Prime > 1) {
+		ret = append(ret, fmt.Sprintf("has a %v-point prime instead of %v", s.Prime, best.Prime))
+	}
+
+	if d := s.Builders - best.Builders; d != 0 {
+		ret = append(ret, "has "+comparative(d, "builder"))
+	}
+
+	for _, p := range s.Stripped {
+		if contains(best.Points, p) && !contains(best.Stripped, p) {
+			ret = append(ret, "strips the "+pointName(p))
+		}
+	}
+
+	return ret
+}
+
+func pointName(p int) string {
+	if p >= 19 {
+		return fmt.Sprintf("%v-point anchor", p)
+	}
+	return fmt.Sprintf("%v-point", p)
+}
+
+// "1 chequer", "2 chequers"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%v %v", n, noun)
+	}
+	return fmt.Sprintf("%v %vs", n, noun)
+}
+
+// "2 more shots", "1 fewer blot"
+func comparative(d int, noun string) string {
+	if d < 0 {
+		return plural(-d, "fewer "+noun)
+	}
+	return plural(d, "more "+noun)
+}
+
+func contains(a []int, n int) bool {
+	for _, v := range a {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetStructure(t *testing.T) {
+	once.Do(setup)
+
+	var start = &openapi.Board{
+		X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+	}
+
+	tests := []struct {
+		name           string
+		args           openapi.MoveArgs
+		wantX          openapi.Structure
+		wantMoves      int
+		wantComparison [][]string
+		wantHits       []int
+		wantErr        bool
+	}{
+		{
+			name: "should describe a position without dice",
+			args: openapi.MoveArgs{Board: start, Player: toPtr(openapi.MoveArgsPlayer("x"))},
+			wantX: openapi.Structure{
+				Blots: []openapi.Blot{}, Points: []int{6, 8, 13, 24}, Anchors: []int{24},
+				Prime: openapi.Prime{Length: 1, From: 6, To: 6}, Escapes: 24, Builders: 4, Stripped: []int{24},
+			},
+		},
+		{
+			name: "should compare the moves with the best",
+			args: openapi.MoveArgs{Board: start, Player: toPtr(openapi.MoveArgsPlayer("x")), Dice: &[]int{3, 1}, MaxMoves: toPtr(4)},
+			wantX: openapi.Structure{
+				Blots: []openapi.Blot{}, Points: []int{6, 8, 13, 24}, Anchors: []int{24},
+				Prime: openapi.Prime{Length: 1, From: 6, To: 6}, Escapes: 24, Builders: 4, Stripped: []int{24},
+			},
+			wantMoves: 4,
+			wantComparison: [][]string{
+				{},
+				{"leaves 34 more shots", "leaves 3 more blots", "doesn't make the 5-point", "breaks the 24-point anchor", "has a 1-point prime instead of 2", "has 3 more builders"},
+				{"leaves 32 more shots", "leaves 2 more blots", "doesn't make the 5-point", "breaks the 24-point anchor", "has a 1-point prime instead of 2", "has 2 more builders"},
+				{"leaves 6 more shots", "leaves 1 more blot", "doesn't make the 5-point", "has a 1-point prime instead of 2", "has 3 more builders"},
+			},
+			wantHits: []int{0, 0, 0, 0},
+		},
+		{
+			name: "should count a hit",
+			args: openapi.MoveArgs{
+				Board: &openapi.Board{
+					X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+					O: openapi.CheckerLayout{N6: toPtr(4), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2), N21: toPtr(1)},
+				},
+				Player:   toPtr(openapi.MoveArgsPlayer("x")),
+				Dice:     &[]int{4, 2},
+				MaxMoves: toPtr(4),
+			},
+			wantX: openapi.Structure{
+				Blots: []openapi.Blot{}, Points: []int{6, 8, 13, 24}, Anchors: []int{24},
+				Prime: openapi.Prime{Length: 1, From: 6, To: 6}, Escapes: 24, Builders: 4, Stripped: []int{24},
+			},
+			wantMoves: 4,
+			wantComparison: [][]string{
+				{},
+				{"leaves 27 more shots", "leaves 3 more blots", "doesn't make the 4-point", "breaks the 24-point anchor", "has 2 more builders"},
+				{"leaves 25 more shots", "leaves 3 more blots", "doesn't make the 4-point", "breaks the 24-point anchor", "has 2 more builders"},
+				{"doesn't hit", "leaves 34 more shots", "leaves 3 more blots", "doesn't make the 4-point", "breaks the 24-point anchor", "has 3 more builders"},
+			},
+			wantHits: []int{1, 1, 1, 0},
+		},
+		{
+			name:    "should refuse a position without a player",
+			args:    openapi.MoveArgs{Board: start},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetStructure(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetStructure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got.X, tt.wantX) {
+				t.Errorf("GetStructure() x = %+v, want %+v", got.X, tt.wantX)
+			}
+			if tt.wantMoves == 0 {
+				if got.Moves != nil {
+					t.Errorf("GetStructure() moves = %+v, want none", *got.Moves)
+				}
+				return
+			}
+			if got.Moves == nil || len(*got.Moves) != tt.wantMoves {
+				t.Fatalf("GetStructure() moves = %+v, want %v", got.Moves, tt.wantMoves)
+			}
+			for i, m := range *got.Moves {
+				if m.Hits != tt.wantHits[i] {
+					t.Errorf("GetStructure() move %v hits = %v, want %v", i+1, m.Hits, tt.wantHits[i])
+				}
+				if !reflect.DeepEqual(m.Comparison, tt.wantComparison[i]) {
+					t.Errorf("GetStructure() move %v comparison = %q, want %q", i+1, m.Comparison, tt.wantComparison[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetStructureText(t *testing.T) {
+	once.Do(setup)
+
+	var args = openapi.MoveArgs{
+		Xgid:     toPtr(openapi.Xgid("XGID=-b----E-C---eE---c-e----B-:0:0:1:31:0:0:3:0:10")),
+		MaxMoves: toPtr(2),
+	}
+
+	got, err := GetStructureText(args, "")
+	if err != nil {
+		t.Fatalf("GetStructureText() error = %v", err)
+	}
+	for _, line := range []string{
+		" X  shots 0/36, points 6 8 13 24, anchors 24, escapes 24/36, builders 4, stripped 24\n",
+		"    1. 8/5 6/5\n",
+		"    2. ",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("GetStructureText() = \n%v\nwant %q", got, line)
+		}
+	}
+}
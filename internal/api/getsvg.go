@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bgweb-api/internal/diagram"
+	"bgweb-api/internal/openapi"
+	"fmt"
+	"strconv"
+)
+
+// GetSVG draws the position of the arguments as an SVG image.
+func GetSVG(args openapi.SvgArgs) (string, error) {
+	pos, err := newPosition(svgArgsPositionArgs(args))
+	if err != nil {
+		return "", err
+	}
+
+	var d = diagram.Diagram{
+		Board:    pos.board,
+		Match:    pos.ms,
+		Bottom:   pos.ms.Move,
+		HomeLeft: fromPtr(args.Home, "right") == "left",
+		Pips:     fromPtr(args.Pips, true),
+	}
+
+	if args.Perspective != nil {
+		d.Bottom = playerNumber(string(*args.Perspective))
+	}
+
+	d.Numbers = d.Bottom
+	switch n := fromPtr(args.Numbers, ""); n {
+	case "none":
+		d.Numbers = -1
+	case "x", "o":
+		d.Numbers = playerNumber(string(n))
+	}
+
+	var ok bool
+	var theme = string(fromPtr(args.Theme, diagram.DefaultTheme))
+	if d.Theme, ok = diagram.Themes[theme]; !ok {
+		return "", fmt.Errorf("invalid theme: %v", theme)
+	}
+
+	if args.Play != nil || args.Notation != nil {
+		var flip = pos.flipNotation(string(fromPtr(args.Numbering, "")))
+		if d.Play, err = argsPlay(args.Play, args.Notation, flip); err != nil {
+			return "", err
+		}
+	}
+
+	return diagram.SVG(d), nil
+}
+
+// SvgArgsIDs returns the IDs of the position drawn.
+func SvgArgsIDs(args openapi.SvgArgs) (IDs, error) {
+	return positionIDs(svgArgsPositionArgs(args))
+}
+
+func svgArgsPositionArgs(args openapi.SvgArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, args.Dice)
+}
+
+// 1 for x, 0 for o
+func playerNumber(player string) int {
+	if player == "x" {
+		return 1
+	}
+	return 0
+}
+
+// the chequer moves of a play as in gnubg.Play: counted from 0, the bar
+// 24 and off -1
+func movesFromPlay(play []openapi.CheckerPlay) ([][2]int, error) {
+	var moves = make([][2]int, 0, len(play))
+	for _, p := range play {
+		var m [2]int
+		for i, s := range []string{string(p.From), string(p.To)} {
+			switch s {
+			case "bar":
+				m[i] = 24
+			case "off":
+				m[i] = -1
+			default:
+				n, err := strconv.Atoi(s)
+				if err != nil || n < 1 || n > 24 {
+					return nil, fmt.Errorf("invalid point in play: %v", s)
+				}
+				m[i] = n - 1
+			}
+		}
+		if m[0] < 0 || m[1] == 24 {
+			return nil, fmt.Errorf("invalid move in play: %v/%v", p.From, p.To)
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"strings"
+	"testing"
+)
+
+func TestGetSVG(t *testing.T) {
+	var xgid = toPtr(openapi.Xgid("XGID=-b----E-C---eE---c-e----B-:0:0:1:31:0:0:3:0:10"))
+
+	tests := []struct {
+		name    string
+		args    openapi.SvgArgs
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "should draw a position with the play of /getmoves",
+			args: openapi.SvgArgs{
+				Xgid: xgid,
+				Play: &[]openapi.CheckerPlay{{From: "8", To: "5"}, {From: "6", To: "5"}},
+			},
+			want: []string{"<svg ", "<line ", ">X: 167 pips</text>", "#f3e5ab"},
+		},
+		{
+			name: "should draw the chosen theme without pips or numbers",
+			args: openapi.SvgArgs{
+				Xgid:    xgid,
+				Theme:   toPtr(openapi.SvgArgsTheme("mono")),
+				Pips:    toPtr(false),
+				Numbers: toPtr(openapi.SvgArgsNumbers("none")),
+			},
+			want: []string{"<svg ", "#9e9e9e"},
+		},
+		{
+			name: "should refuse a position without a player",
+			args: openapi.SvgArgs{
+				Board: &openapi.Board{X: openapi.CheckerLayout{N6: toPtr(1)}, O: openapi.CheckerLayout{N6: toPtr(1)}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "should refuse an unknown theme",
+			args:    openapi.SvgArgs{Xgid: xgid, Theme: toPtr(openapi.SvgArgsTheme("pink"))},
+			wantErr: true,
+		},
+		{
+			name: "should draw a play in notation",
+			args: openapi.SvgArgs{Xgid: xgid, Notation: toPtr("8/5 6/5")},
+			want: []string{"<svg ", "<line "},
+		},
+		{
+			name:    "should refuse invalid notation",
+			args:    openapi.SvgArgs{Xgid: xgid, Notation: toPtr("8/5 6")},
+			wantErr: true,
+		},
+		{
+			name:    "should refuse a play from off",
+			args:    openapi.SvgArgs{Xgid: xgid, Play: &[]openapi.CheckerPlay{{From: "off", To: "5"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSVG(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSVG() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("GetSVG() = \n%v\nwant %q", got, s)
+				}
+			}
+		})
+	}
+}
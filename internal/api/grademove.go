@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+	"fmt"
+	"strings"
+)
+
+// GradeMove checks that the play of the arguments is legal and scores it
+// against the best move.
+func GradeMove(args openapi.GradeArgs) (openapi.MoveGrade, error) {
+	var ret openapi.MoveGrade
+
+	pos, err := newPosition(gradeArgsPositionArgs(args))
+	if err != nil {
+		return ret, err
+	}
+	if err := checkDice(pos); err != nil {
+		return ret, err
+	}
+
+	var flip = pos.flipNotation(string(fromPtr(args.Numbering, "")))
+	moves, err := argsPlay(args.Play, args.Notation, flip)
+	if err != nil {
+		return ret, err
+	}
+
+	var board = pos.moverBoard()
+	after, err := gnubg.CheckPlay(board, pos.ms.Dice, moves)
+	if err != nil {
+		return ret, fmt.Errorf("illegal play: %v", err)
+	}
+	if len(gnubg.LegalPlays(board, pos.ms.Dice)) == 0 {
+		return ret, fmt.Errorf("there is no move to grade: %v-%v can't be played", pos.ms.Dice[0], pos.ms.Dice[1])
+	}
+
+	var cubeful = fromPtr(args.Cubeful, false)
+	var quantized = fromPtr(args.Quantized, gnubg.IsQuantized())
+
+	ml, i, err := gnubg.GradePlay(board, pos.ms.Dice, pos.ms.Move, pos.ms.Cube, after, moveSettings(cubeful, quantized))
+	if err != nil {
+		return ret, fmt.Errorf("error grading the play: %v", err)
+	}
+
+	var best, move = ml.GetMove(0), ml.GetMove(i)
+	ret.Move = pos.outputMove(move, best, flip)
+	ret.Best = pos.outputMove(best, best, flip)
+	ret.Rank = i + 1
+	ret.Moves = ml.GetMovesNum()
+	ret.Error = outputEquityDiff(best.GetEquity(), move.GetEquity())
+
+	return ret, nil
+}
+
+// GradeMoveText draws the position as gnubg's ASCII board, from the side
+// of perspective ("x", "o", or "" for the player on roll), and lists the
+// best move and the play below it as gnubg's hint does.
+func GradeMoveText(args openapi.GradeArgs, perspective string) (string, error) {
+	pos, err := newPosition(gradeArgsPositionArgs(args))
+	if err != nil {
+		return "", err
+	}
+
+	grade, err := GradeMove(args)
+	if err != nil {
+		return "", err
+	}
+
+	board, err := pos.draw(perspective)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(board + "\n")
+	writeMoveText(&sb, 1, grade.Best)
+	if grade.Rank > 1 {
+		writeMoveText(&sb, grade.Rank, grade.Move)
+	}
+	fmt.Fprintf(&sb, "\n Rank %v of %v, error %.3f\n", grade.Rank, grade.Moves, grade.Error)
+
+	return sb.String(), nil
+}
+
+// GradeArgsIDs returns the IDs of the position the play is made from.
+func GradeArgsIDs(args openapi.GradeArgs) (IDs, error) {
+	return positionIDs(gradeArgsPositionArgs(args))
+}
+
+func gradeArgsPositionArgs(args openapi.GradeArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, args.Dice)
+}
+
+// the chequer moves of a play given by play or, in standard notation, by
+// notation
+func argsPlay(play *[]openapi.CheckerPlay, notation *string, flip bool) ([][2]int, error) {
+	switch {
+	case play != nil && notation != nil:
+		return nil, fmt.Errorf("play and notation can't both be given")
+	case notation != nil:
+		return gnubg.ParseNotation(*notation, flip)
+	case play != nil:
+		return movesFromPlay(*play)
+	}
+	return nil, fmt.Errorf("play or notation is required")
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"strings"
+	"testing"
+)
+
+func TestGradeMove(t *testing.T) {
+	once.Do(setup)
+
+	var opening = &openapi.Board{
+		X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+	}
+	var args = func(dice []int, play ...openapi.CheckerPlay) openapi.GradeArgs {
+		return openapi.GradeArgs{Board: opening, Dice: &dice, Player: toPtr(openapi.GradeArgsPlayer("x")), Quantized: toPtr(false), Play: &play}
+	}
+
+	tests := []struct {
+		name      string
+		args      openapi.GradeArgs
+		wantRank  int
+		wantMoves int
+		wantPlies int
+		wantErr   string
+	}{
+		{
+			name:      "should grade the best move",
+			args:      args([]int{3, 1}, openapi.CheckerPlay{From: "8", To: "5"}, openapi.CheckerPlay{From: "6", To: "5"}),
+			wantRank:  1,
+			wantMoves: 16,
+			wantPlies: 1,
+		},
+		{
+			name: "should grade a chequer moved with both dice as deeply as the best",
+			args: args([]int{3, 1}, openapi.CheckerPlay{From: "24", To: "20"}),
+			/* the move filters keep only 8/5 6/5 after 0 plies */
+			wantRank:  2,
+			wantMoves: 16,
+			wantPlies: 3,
+		},
+		{
+			name: "should grade a play in notation",
+			args: openapi.GradeArgs{Board: opening, Dice: &[]int{3, 1}, Player: toPtr(openapi.GradeArgsPlayer("x")), Quantized: toPtr(false), Notation: toPtr("24/20")},
+			/* as 24/20 given as a play */
+			wantRank:  2,
+			wantMoves: 16,
+			wantPlies: 3,
+		},
+		{
+			name: "should grade a play in the opponent's numbering",
+			args: openapi.GradeArgs{
+				Board: opening, Dice: &[]int{3, 1}, Player: toPtr(openapi.GradeArgsPlayer("x")), Quantized: toPtr(false),
+				Notation: toPtr("17/20 19/20"), Numbering: toPtr(openapi.GradeArgsNumbering("o")),
+			},
+			wantRank:  1,
+			wantMoves: 16,
+			wantPlies: 1,
+		},
+		{
+			name: "should refuse both a play and notation",
+			args: func() openapi.GradeArgs {
+				var a = args([]int{3, 1}, openapi.CheckerPlay{From: "24", To: "20"})
+				a.Notation = toPtr("24/20")
+				return a
+			}(),
+			wantErr: "play and notation can't both be given",
+		},
+		{
+			name:    "should refuse a play leaving a die unplayed",
+			args:    args([]int{3, 1}, openapi.CheckerPlay{From: "8", To: "5"}),
+			wantErr: "illegal play: both dice must be played",
+		},
+		{
+			name:    "should refuse a play onto a made point",
+			args:    args([]int{6, 5}, openapi.CheckerPlay{From: "24", To: "19"}, openapi.CheckerPlay{From: "24", To: "18"}),
+			wantErr: "illegal play: the 19-point is blocked",
+		},
+		{
+			name:    "should refuse a play the dice don't make",
+			args:    args([]int{3, 1}, openapi.CheckerPlay{From: "24", To: "18"}),
+			wantErr: "illegal play: 24/18 can't be played with 3-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GradeMove(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("GradeMove() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GradeMove() error = %v", err)
+			}
+
+			var ev, best = got.Move.Evaluation, got.Best.Evaluation
+			if got.Rank != tt.wantRank || got.Moves != tt.wantMoves || ev.Info.Plies != tt.wantPlies || best.Info.Plies != tt.wantPlies {
+				t.Errorf("GradeMove() rank %v of %v, plies %v, best plies %v", got.Rank, got.Moves, ev.Info.Plies, best.Info.Plies)
+			}
+			if got.Error != -ev.Diff || (got.Rank == 1) != (got.Error == 0) {
+				t.Errorf("GradeMove() error %v, diff %v", got.Error, ev.Diff)
+			}
+		})
+	}
+}
+
+func TestGradeMoveText(t *testing.T) {
+	once.Do(setup)
+
+	var args = openapi.GradeArgs{
+		Xgid:      toPtr(openapi.Xgid("XGID=-b----E-C---eE---c-e----B-:0:0:1:31:0:0:3:0:10")),
+		Quantized: toPtr(false),
+		Play:      &[]openapi.CheckerPlay{{From: "24", To: "20"}},
+	}
+
+	got, err := GradeMoveText(args, "")
+	if err != nil {
+		t.Fatalf("GradeMoveText() error = %v", err)
+	}
+	for _, line := range []string{
+		" +12-11-10--9--8--7-------6--5--4--3--2--1-+     X\n",
+		"    1. Cubeless 3-ply   8/5 6/5 ",
+		"    2. Cubeless 3-ply   24/20 ",
+		" Rank 2 of 16, error ",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("GradeMoveText() = \n%v\nwant %q", got, line)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/layout"
+	"bgweb-api/internal/openapi"
+	"fmt"
+)
+
+// InferMove finds the legal play of the dice that turns the board of the
+// arguments into the board after, or without dice the play of each roll
+// that does, higher rolls first.
+func InferMove(args openapi.InferArgs) (openapi.MoveInference, error) {
+	var ret openapi.MoveInference
+
+	pos, err := newPosition(inferArgsPositionArgs(args))
+	if err != nil {
+		return ret, err
+	}
+
+	var rolls = [][2]int{pos.ms.Dice}
+	if pos.ms.Dice[0] == 0 {
+		rolls = nil
+		for n0 := 6; n0 >= 1; n0-- {
+			for n1 := n0; n1 >= 1; n1-- {
+				rolls = append(rolls, [2]int{n0, n1})
+			}
+		}
+	}
+
+	var flip = pos.flipNotation(string(fromPtr(args.Numbering, "")))
+
+	/* both boards seen from the mover */
+	var board = pos.moverBoard()
+	var after = pos
+	after.board = layout.TanBoard(args.After)
+	var afterBoard = after.moverBoard()
+
+	ret.Moves = make([]openapi.InferredMove, 0, 1)
+	for _, roll := range rolls {
+		play, ok := gnubg.FindPlay(board, roll, afterBoard)
+		if !ok {
+			continue
+		}
+
+		var cp = outputPlay(play.Moves)
+		var move = openapi.InferredMove{
+			Dice:     openapi.Roll{roll[0], roll[1]},
+			Play:     cp,
+			Notation: gnubg.Notation(board, play.Moves, flip),
+		}
+		move.Hits, move.BorneOff = playEffects(board, play.Board)
+		ret.Moves = append(ret.Moves, move)
+	}
+
+	if len(ret.Moves) == 0 {
+		if len(rolls) == 1 {
+			return ret, fmt.Errorf("no legal play of %v-%v reaches the board after", rolls[0][0], rolls[0][1])
+		}
+		return ret, fmt.Errorf("no legal play of any roll reaches the board after")
+	}
+
+	return ret, nil
+}
+
+// InferArgsIDs returns the IDs of the position before the play.
+func InferArgsIDs(args openapi.InferArgs) (IDs, error) {
+	return positionIDs(inferArgsPositionArgs(args))
+}
+
+func inferArgsPositionArgs(args openapi.InferArgs) positionArgs {
+	return newPositionArgs(args.Board, args.PositionId, args.MatchId, args.Xgid, args.FibsBoard, args.Player, args.Dice)
+}
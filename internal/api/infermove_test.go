@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bgweb-api/internal/openapi"
+	"reflect"
+	"testing"
+)
+
+func TestInferMove(t *testing.T) {
+	var opening = &openapi.Board{
+		X: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(2)},
+	}
+	/* an o blot on x's 5-point */
+	var blot = &openapi.Board{
+		X: opening.X,
+		O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N20: toPtr(1), N24: toPtr(1)},
+	}
+	var pointed = openapi.CheckerLayout{N5: toPtr(2), N6: toPtr(4), N8: toPtr(2), N13: toPtr(5), N24: toPtr(2)}
+	var args = func(board *openapi.Board, dice []int, after openapi.Board) openapi.InferArgs {
+		var ret = openapi.InferArgs{Board: board, Player: toPtr(openapi.InferArgsPlayer("x")), After: after}
+		if dice != nil {
+			ret.Dice = &dice
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name    string
+		args    openapi.InferArgs
+		want    []openapi.InferredMove
+		wantErr string
+	}{
+		{
+			name: "should find the play of the dice",
+			args: args(opening, []int{3, 1}, openapi.Board{X: pointed, O: opening.O}),
+			want: []openapi.InferredMove{{
+				Dice:     openapi.Roll{3, 1},
+				Play:     []openapi.CheckerPlay{{From: "8", To: "5"}, {From: "6", To: "5"}},
+				Notation: "8/5 6/5",
+				Hits:     []int{},
+			}},
+		},
+		{
+			name: "should find the rolls playing to the board",
+			args: args(opening, nil, openapi.Board{
+				X: openapi.CheckerLayout{N6: toPtr(5), N7: toPtr(1), N8: toPtr(3), N13: toPtr(4), N24: toPtr(2)},
+				O: opening.O,
+			}),
+			want: []openapi.InferredMove{
+				{
+					Dice:     openapi.Roll{5, 1},
+					Play:     []openapi.CheckerPlay{{From: "13", To: "8"}, {From: "8", To: "7"}},
+					Notation: "13/7",
+					Hits:     []int{},
+				},
+				{
+					Dice:     openapi.Roll{4, 2},
+					Play:     []openapi.CheckerPlay{{From: "13", To: "9"}, {From: "9", To: "7"}},
+					Notation: "13/7",
+					Hits:     []int{},
+				},
+			},
+		},
+		{
+			name: "should find a hit",
+			args: args(blot, []int{3, 1}, openapi.Board{
+				X: pointed,
+				O: openapi.CheckerLayout{N6: toPtr(5), N8: toPtr(3), N13: toPtr(5), N24: toPtr(1), Bar: toPtr(1)},
+			}),
+			want: []openapi.InferredMove{{
+				Dice:     openapi.Roll{3, 1},
+				Play:     []openapi.CheckerPlay{{From: "8", To: "5"}, {From: "6", To: "5"}},
+				Notation: "8/5* 6/5",
+				Hits:     []int{5},
+			}},
+		},
+		{
+			name: "should count points from the opponent",
+			args: func() openapi.InferArgs {
+				var a = args(opening, []int{3, 1}, openapi.Board{X: pointed, O: opening.O})
+				a.Numbering = toPtr(openapi.InferArgsNumbering("o"))
+				return a
+			}(),
+			want: []openapi.InferredMove{{
+				Dice:     openapi.Roll{3, 1},
+				Play:     []openapi.CheckerPlay{{From: "8", To: "5"}, {From: "6", To: "5"}},
+				Notation: "17/20 19/20",
+				Hits:     []int{},
+			}},
+		},
+		{
+			name:    "should refuse a board the dice don't play to",
+			args:    args(opening, []int{4, 2}, openapi.Board{X: pointed, O: opening.O}),
+			wantErr: "no legal play of 4-2 reaches the board after",
+		},
+		{
+			name:    "should refuse a board no roll plays to",
+			args:    args(opening, nil, *opening),
+			wantErr: "no legal play of any roll reaches the board after",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InferMove(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("InferMove() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InferMove() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Moves, tt.want) {
+				t.Errorf("InferMove() = %+v, want %+v", got.Moves, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bgweb-api/internal/fibs"
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/layout"
+	"bgweb-api/internal/openapi"
+	"bgweb-api/internal/xgid"
+	"fmt"
+)
+
+// position is the game state a request describes: the board in gnubg's
+// {x, o} layout and the match state, whose Move is the player on roll
+// (1 for x, 0 for o).
+type position struct {
+	board gnubg.TanBoard
+	ms    gnubg.MatchState
+	// cube limit of an XGID, as a power of 2
+	maxCube int
+}
+
+// money game with a centred cube, as positions are evaluated unless a
+// Match ID, XGID or FIBS board says otherwise
+var defaultCube = gnubg.Cube{Value: 1, Owner: -1, Jacoby: true, Beavers: true}
+
+// the ways a request may describe a position
+type positionArgs struct {
+	board      *openapi.Board
+	positionID *openapi.PositionId
+	matchID    *openapi.MatchId
+	xgid       *openapi.Xgid
+	fibsBoard  *openapi.FibsBoard
+	player     string
+	dice       *[]int
+}
+
+// Work out the position from a board or Position ID, and the player on
+// roll and dice from the arguments or a Match ID; or all of them from an
+// XGID or FIBS board. Arguments given take precedence over the IDs; dice
+// are left 0 if none has them.
+func newPosition(args positionArgs) (position, error) {
+	var ret = position{ms: gnubg.MatchState{Cube: defaultCube, GameState: 1}, maxCube: xgid.DefaultMaxCube}
+
+	var fPlayer bool
+	switch {
+	case args.xgid != nil && args.fibsBoard != nil:
+		return ret, fmt.Errorf("xgid and fibsBoard can't both be given")
+	case args.xgid != nil || args.fibsBoard != nil:
+		if args.board != nil || args.positionID != nil || args.matchID != nil {
+			return ret, fmt.Errorf("xgid or fibsBoard can't be given with board, positionId or matchId")
+		}
+	}
+
+	switch {
+	case args.fibsBoard != nil:
+		b, err := fibs.ParseBoard(string(*args.fibsBoard))
+		if err != nil {
+			return ret, err
+		}
+		ret.board = b.Board
+		ret.ms = b.Match
+		fPlayer = true
+	case args.xgid != nil:
+		x, err := xgid.Parse(string(*args.xgid))
+		if err != nil {
+			return ret, err
+		}
+		ret.board = layout.TanBoard(x.Board)
+		ret.ms = x.Match
+		ret.maxCube = x.MaxCube
+		fPlayer = true
+	case args.matchID != nil:
+		ms, err := gnubg.MatchFromID(string(*args.matchID))
+		if err != nil {
+			return ret, err
+		}
+		ret.ms = ms
+		fPlayer = true
+	}
+
+	switch args.player {
+	case "x":
+		ret.ms.Move, ret.ms.Turn = 1, 1
+	case "o":
+		ret.ms.Move, ret.ms.Turn = 0, 0
+	default:
+		if !fPlayer {
+			return ret, fmt.Errorf("player or matchId is required")
+		}
+	}
+
+	if dice := args.dice; dice != nil {
+		if len(*dice) != 2 {
+			return ret, fmt.Errorf("dice must hold 2 values")
+		}
+		ret.ms.Dice = [2]int{(*dice)[0], (*dice)[1]}
+	}
+
+	switch {
+	case args.xgid != nil || args.fibsBoard != nil:
+	case args.board != nil && args.positionID != nil:
+		return ret, fmt.Errorf("board and positionId can't both be given")
+	case args.board != nil:
+		ret.board = layout.TanBoard(*args.board)
+	case args.positionID != nil:
+		b, err := gnubg.PositionFromID(string(*args.positionID))
+		if err != nil {
+			return ret, err
+		}
+		/* from the player on roll to {x, o} */
+		ret.board = b
+		if ret.ms.Move == 1 {
+			ret.board = gnubg.SwapSides(b)
+		}
+	default:
+		return ret, fmt.Errorf("board, positionId, xgid or fibsBoard is required")
+	}
+
+	return ret, nil
+}
+
+// the position after the player on roll plays to the board, given as
+// gnubg.Move.GetBoard does, with the opponent to roll
+func (p position) afterMove(board gnubg.TanBoard) position {
+	var ret = p
+
+	/* from the opponent's view to {x, o} */
+	ret.board = board
+	if p.ms.Move == 0 {
+		ret.board = gnubg.SwapSides(board)
+	}
+
+	ret.ms.Move = 1 - p.ms.Move
+	ret.ms.Turn = ret.ms.Move
+	ret.ms.Dice = [2]int{}
+	ret.ms.Doubled = false
+
+	return ret
+}
+
+// the board seen from the player on roll
+func (p position) moverBoard() gnubg.TanBoard {
+	if p.ms.Move == 1 {
+		return gnubg.SwapSides(p.board)
+	}
+	return p.board
+}
+
+// whether notation counts points from the opponent of the player on roll,
+// as it does when numbering ("x", "o", or "" for the player on roll) is
+// that opponent
+func (p position) flipNotation(numbering string) bool {
+	switch numbering {
+	case "x":
+		return p.ms.Move != 1
+	case "o":
+		return p.ms.Move != 0
+	}
+	return false
+}
+
+func (p position) positionID() string {
+	return gnubg.PositionID(p.moverBoard())
+}
+
+func (p position) matchID() string {
+	return gnubg.MatchID(p.ms)
+}
+
+func (p position) xgid() string {
+	return xgid.XGID{Board: layout.Board(p.board), Match: p.ms, MaxCube: p.maxCube}.String()
+}
+
+// the position drawn as gnubg's ASCII board, with the player perspective
+// names ("x" or "o", or "" for the player on roll) at the bottom
+func (p position) draw(perspective string) (string, error) {
+	var bottom = p.ms.Move
+	switch perspective {
+	case "x":
+		bottom = 1
+	case "o":
+		bottom = 0
+	case "":
+	default:
+		return "", fmt.Errorf("invalid perspective: %v", perspective)
+	}
+
+	return gnubg.DrawBoard(p.moverBoard(), p.ms, [2]string{}, bottom), nil
+}
@@ -0,0 +1,240 @@
+// Package commentary explains in a few words why the engine prefers its
+// top move to the others, from the equities and gammon chances of the
+// moves and the structure of the positions they leave. The rules pick what
+// to say; the words come from message catalogues, one per language, so
+// that they can be translated apart from the rules.
+package commentary
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"bgweb-api/internal/gnubg"
+)
+
+//go:embed catalogues/*.json
+var catalogues embed.FS
+
+// language of the messages missing from the other catalogues
+const fallback = "en"
+
+const (
+	// change in gammon chances worth a word
+	gammonChange = 0.03
+	// change in the rolls out of 36 on which the opponent's back chequer
+	// escapes worth a word
+	escapeChange = 4
+	// reasons given for the top move, and against each of the others
+	maxBestReasons  = 3
+	maxWorseReasons = 2
+)
+
+// Catalogue holds the messages of one language by key. Messages name
+// their arguments in braces, as in "{play} is best"; a message counting
+// something has a ".one" and an ".other" form.
+type Catalogue map[string]string
+
+// Languages lists the languages there is a catalogue for.
+func Languages() []string {
+	var ret []string
+	entries, _ := catalogues.ReadDir("catalogues")
+	for _, e := range entries {
+		ret = append(ret, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Load reads the catalogue of a language, such as "en" or "fr". Messages
+// it lacks are taken from the English one.
+func Load(lang string) (Catalogue, error) {
+	var ret = Catalogue{}
+	for _, l := range []string{fallback, lang} {
+		b, err := catalogues.ReadFile(path.Join("catalogues", l+".json"))
+		if err != nil {
+			return nil, fmt.Errorf("no commentary in %q; languages are %v", lang, strings.Join(Languages(), ", "))
+		}
+		if err := json.Unmarshal(b, &ret); err != nil {
+			return nil, fmt.Errorf("error reading the %q catalogue: %v", l, err)
+		}
+	}
+	return ret, nil
+}
+
+// Move is what the commentary knows of a move.
+type Move struct {
+	Play   string // such as "13/9 6/5"
+	Equity float32
+	// chances of the mover winning and losing a gammon or backgammon
+	WinG, LoseG float32
+	Hits        int // chequers hit
+	// structure of the mover's and their opponent's chequers after the move
+	Mover, Opponent gnubg.Structure
+}
+
+// Comment returns a sentence for each of the moves, best first: why the
+// first is best, then for each of the others what makes it worse. before
+// is the mover's structure before moving, to tell points made from points
+// kept, and opponent is "x" or "o".
+func (c Catalogue) Comment(before gnubg.Structure, opponent string, moves []Move) []string {
+	var ret = make([]string, 0, len(moves))
+	if len(moves) == 0 {
+		return ret
+	}
+
+	var best = moves[0]
+	ret = append(ret, c.best(before, moves))
+	for _, m := range moves[1:] {
+		ret = append(ret, c.worse(before, opponent, best, m))
+	}
+	return ret
+}
+
+func (c Catalogue) best(before gnubg.Structure, moves []Move) string {
+	var best = moves[0]
+	var reasons []string
+
+	if best.Hits > 0 {
+		reasons = append(reasons, c.count("hits", best.Hits))
+	}
+	for _, p := range best.Mover.Points {
+		if !contains(before.Points, p) {
+			reasons = append(reasons, c.format("makes", "point", c.point(p)))
+		}
+	}
+	if best.Mover.Prime >= 3 && best.Mover.Prime > before.Prime {
+		reasons = append(reasons, c.format("prime", "n", best.Mover.Prime))
+	}
+	if before.Escapes < 36 && best.Mover.Escapes == 36 {
+		reasons = append(reasons, c.format("escapes"))
+	}
+
+	var unsafe, moreGammons bool
+	for _, m := range moves[1:] {
+		unsafe = unsafe || m.Mover.Shots > 0
+		moreGammons = moreGammons || best.WinG-m.WinG >= gammonChange
+	}
+	if best.Mover.Shots == 0 && unsafe {
+		reasons = append(reasons, c.format("safe"))
+	}
+	if moreGammons {
+		reasons = append(reasons, c.format("moreGammons"))
+	}
+
+	if len(reasons) == 0 {
+		return c.sentence("best.plain", "play", best.Play)
+	}
+	return c.sentence("best", "play", best.Play, "reasons", c.list(reasons, maxBestReasons))
+}
+
+func (c Catalogue) worse(before gnubg.Structure, opponent string, best, m Move) string {
+	var diff = math.Round(float64(best.Equity-m.Equity)*1000) / 1000
+	if diff <= 0 {
+		return c.sentence("equal", "play", m.Play)
+	}
+
+	var reasons []string
+	if best.Hits > 0 && m.Hits == 0 {
+		reasons = append(reasons, c.format("noHit"))
+	}
+	for _, p := range best.Mover.Points {
+		if contains(m.Mover.Points, p) {
+			continue
+		}
+		if contains(before.Points, p) {
+			reasons = append(reasons, c.format("breaks", "point", c.point(p)))
+		} else {
+			reasons = append(reasons, c.format("misses", "point", c.point(p)))
+		}
+	}
+	if m.Opponent.Escapes-best.Opponent.Escapes >= escapeChange {
+		reasons = append(reasons, c.format("letsEscape", "opponent", c.format("player."+opponent)))
+	}
+	if d := m.Mover.Shots - best.Mover.Shots; d > 0 {
+		reasons = append(reasons, c.count("moreShots", d))
+	}
+	if best.Mover.Prime >= 3 && m.Mover.Prime < best.Mover.Prime {
+		reasons = append(reasons, c.format("shorterPrime"))
+	}
+	if best.WinG-m.WinG >= gammonChange {
+		reasons = append(reasons, c.format("fewerGammons"))
+	}
+	if m.LoseG-best.LoseG >= gammonChange {
+		reasons = append(reasons, c.format("riskGammons"))
+	}
+
+	var key = "worse"
+	if m.Mover.Shots < best.Mover.Shots {
+		key = "worse.safer"
+	}
+	var sDiff = strings.Replace(fmt.Sprintf("%.3f", diff), ".", c.format("decimal"), 1)
+	if len(reasons) == 0 {
+		return c.sentence(key+".plain", "play", m.Play, "diff", sDiff)
+	}
+	return c.sentence(key, "play", m.Play, "diff", sDiff, "reasons", c.list(reasons, maxWorseReasons))
+}
+
+// format fills in the message of key with the arguments, given as name
+// and value pairs.
+func (c Catalogue) format(key string, args ...interface{}) string {
+	var msg, ok = c[key]
+	if !ok {
+		return key
+	}
+
+	var oldnew []string
+	for i := 0; i+1 < len(args); i += 2 {
+		oldnew = append(oldnew, "{"+fmt.Sprint(args[i])+"}", fmt.Sprint(args[i+1]))
+	}
+	return strings.NewReplacer(oldnew...).Replace(msg)
+}
+
+// the ".one" or ".other" form of the message of key, with n filled in
+func (c Catalogue) count(key string, n int) string {
+	if n == 1 {
+		return c.format(key+".one", "n", n)
+	}
+	return c.format(key+".other", "n", n)
+}
+
+// a message starting with a capital, as a sentence does
+func (c Catalogue) sentence(key string, args ...interface{}) string {
+	var s = c.format(key, args...)
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
+
+func (c Catalogue) point(p int) string {
+	if p >= 19 {
+		return c.format("anchor", "n", p)
+	}
+	return c.format("point", "n", p)
+}
+
+// the first n items, as in "a, b and c"
+func (c Catalogue) list(items []string, n int) string {
+	if len(items) > n {
+		items = items[:n]
+	}
+	if len(items) == 1 {
+		return items[0]
+	}
+	var last = len(items) - 1
+	return strings.Join(items[:last], c.format("list.sep")) + c.format("list.and") + items[last]
+}
+
+func contains(a []int, n int) bool {
+	for _, v := range a {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package commentary
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"bgweb-api/internal/gnubg"
+)
+
+func TestLoad(t *testing.T) {
+	var en map[string]string
+	b, _ := catalogues.ReadFile("catalogues/en.json")
+	if err := json.Unmarshal(b, &en); err != nil {
+		t.Fatalf("reading the English catalogue: %v", err)
+	}
+
+	for _, lang := range Languages() {
+		t.Run("should translate every message in "+lang, func(t *testing.T) {
+			var c map[string]string
+			b, _ := catalogues.ReadFile("catalogues/" + lang + ".json")
+			if err := json.Unmarshal(b, &c); err != nil {
+				t.Fatalf("reading the %v catalogue: %v", lang, err)
+			}
+			for key := range en {
+				if _, ok := c[key]; !ok {
+					t.Errorf("the %v catalogue has no %q", lang, key)
+				}
+			}
+		})
+	}
+
+	t.Run("should refuse a language without a catalogue", func(t *testing.T) {
+		if _, err := Load("xx"); err == nil {
+			t.Errorf("Load() found a catalogue for xx")
+		}
+	})
+}
+
+func TestComment(t *testing.T) {
+	var opening = gnubg.Structure{Points: []int{6, 8, 13, 24}, Prime: 1, Escapes: 24}
+
+	tests := []struct {
+		name   string
+		lang   string
+		before gnubg.Structure
+		moves  []Move
+		want   []string
+	}{
+		{
+			name:   "should say why making a point is best",
+			lang:   "en",
+			before: opening,
+			moves: []Move{
+				{Play: "8/5 6/5", Equity: 0.159, WinG: 0.18, Mover: gnubg.Structure{Points: []int{5, 6, 8, 13, 24}, Prime: 2}, Opponent: gnubg.Structure{Escapes: 20}},
+				{Play: "13/10 24/23", Equity: -0.009, WinG: 0.14, Mover: gnubg.Structure{Blots: []gnubg.Blot{{}, {}}, Shots: 17, Points: []int{6, 8, 13}, Prime: 1}, Opponent: gnubg.Structure{Escapes: 24}},
+				{Play: "24/20", Equity: -0.02, WinG: 0.179, Mover: gnubg.Structure{Points: []int{5, 6, 8, 13, 24}, Prime: 2}, Opponent: gnubg.Structure{Escapes: 20}},
+			},
+			want: []string{
+				"8/5 6/5 is best: it makes the 5-point, leaves no shots and wins more gammons.",
+				"13/10 24/23 is 0.168 worse because it doesn't make the 5-point and breaks the 24-point anchor.",
+				"24/20 is 0.179 worse.",
+			},
+		},
+		{
+			name:   "should call a safer play safer",
+			lang:   "en",
+			before: opening,
+			moves: []Move{
+				{Play: "13/7 13/8", Equity: 0.1, Hits: 2, Mover: gnubg.Structure{Shots: 11, Points: []int{6, 8, 13, 24}}},
+				{Play: "24/13", Equity: 0.02, Mover: gnubg.Structure{Points: []int{6, 8, 13}}, Opponent: gnubg.Structure{Escapes: 30}},
+				{Play: "13/2", Equity: 0.1, Mover: gnubg.Structure{Points: []int{6, 8, 13}}},
+			},
+			want: []string{
+				"13/7 13/8 is best: it hits 2 chequers.",
+				"The safer 24/13 is 0.080 worse because it doesn't hit and breaks the 24-point anchor.",
+				"13/2 is as good.",
+			},
+		},
+		{
+			name:   "should count shots in French",
+			lang:   "fr",
+			before: opening,
+			moves: []Move{
+				{Play: "24/18 13/11", Equity: 0.05, Mover: gnubg.Structure{Points: []int{6, 8, 13}, Escapes: 36}},
+				{Play: "13/7 13/11", Equity: 0.03, Mover: gnubg.Structure{Shots: 1, Points: []int{6, 8, 13}}, Opponent: gnubg.Structure{Escapes: 28}, LoseG: 0.04},
+			},
+			want: []string{
+				"24/18 13/11 est le meilleur coup : il sort un pion arrière et ne laisse aucun tir.",
+				"13/7 13/11 est moins bon de 0,020 car il laisse X s'échapper et laisse 1 tir de plus.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(tt.lang)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := c.Comment(tt.before, "x", tt.moves); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Comment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Package datagen generates training data: positions from self-play games
+// or from files, each with its engine key, position class, net inputs,
+// evaluations and optionally rollout results.
+package datagen
+
+import (
+	"bgweb-api/internal/gnubg"
+	"math/rand"
+	"sync"
+)
+
+// Record is a position with everything the engine knows about it. The
+// board is seen from the player on roll: board[1] holds their chequers,
+// board[0] the opponent's, each counted from their own side with the bar
+// at index 24.
+type Record struct {
+	Game  int            `json:"game"` // game, or file line, the position is from
+	Move  int            `json:"move"` // position in the game, 0 being the start
+	Board gnubg.TanBoard `json:"board"`
+	Key   string         `json:"key"` // see gnubg.PositionKey
+	// gnubg Position ID
+	PositionID string `json:"positionId"`
+	Class      string `json:"class"`
+	// inputs of the net evaluating the class, absent when none does
+	Inputs      []float32    `json:"inputs,omitempty"`
+	Evaluations []Evaluation `json:"evaluations"`
+	Rollout     *Rollout     `json:"rollout,omitempty"`
+}
+
+// Evaluation holds the cubeless chances of the player on roll, win, win
+// gammon, win backgammon, lose gammon and lose backgammon, at some plies.
+type Evaluation struct {
+	Plies         int        `json:"plies"`
+	Probabilities [5]float32 `json:"probabilities"`
+	Equity        float32    `json:"equity"` // cubeless money equity
+}
+
+// Config holds the settings of Generate.
+type Config struct {
+	// Plies lists the plies each position is evaluated at, with the
+	// filter and nets of Eval.
+	Plies []int
+	Eval  gnubg.Settings
+
+	// RolloutTrials, if positive, rolls every position out with Rollout
+	// settings to give training targets.
+	RolloutTrials int
+	Rollout       gnubg.Settings
+
+	// Sample is the chance of keeping a position, 0 keeping all.
+	Sample float64
+	// Seed makes the sampling and rollouts reproducible.
+	Seed int64
+	// Parallel is the number of games worked on at the same time.
+	Parallel int
+}
+
+// A Source produces the games to take positions from. Each game is a list
+// of positions seen from the player on roll, see Record.
+type Source interface {
+	// Next returns the next game, or false when there are no more.
+	Next() ([]gnubg.TanBoard, bool, error)
+}
+
+// Generate turns the positions of every game from the source into records
+// and passes them to write, in order.
+func Generate(src Source, cfg Config, write func(Record) error) error {
+	var parallel = cfg.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	for iGame := 0; ; {
+		/* a batch of games at a time keeps the records in order without
+		 * holding them all */
+		var batch [][]gnubg.TanBoard
+		for len(batch) < 4*parallel {
+			game, ok, err := src.Next()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			batch = append(batch, game)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		var records = make([][]Record, len(batch))
+		var aErr = make([]error, len(batch))
+		var ch = make(chan int)
+		var wg sync.WaitGroup
+
+		for i := 0; i < parallel; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range ch {
+					records[i], aErr[i] = gameRecords(iGame+i, batch[i], cfg)
+				}
+			}()
+		}
+		for i := range batch {
+			ch <- i
+		}
+		close(ch)
+		wg.Wait()
+
+		for i := range batch {
+			if aErr[i] != nil {
+				return aErr[i]
+			}
+			for _, r := range records[i] {
+				if err := write(r); err != nil {
+					return err
+				}
+			}
+		}
+
+		iGame += len(batch)
+	}
+}
+
+func gameRecords(iGame int, game []gnubg.TanBoard, cfg Config) ([]Record, error) {
+	var ret []Record
+	var rnd = rand.New(rand.NewSource(cfg.Seed + int64(iGame)))
+
+	for iMove, board := range game {
+		if cfg.Sample > 0 && rnd.Float64() >= cfg.Sample {
+			continue
+		}
+
+		r, err := NewRecord(board, cfg, rnd)
+		if err != nil {
+			return nil, err
+		}
+		r.Game, r.Move = iGame, iMove
+
+		ret = append(ret, r)
+	}
+
+	return ret, nil
+}
+
+// NewRecord evaluates, and rolls out, a single position.
+func NewRecord(board gnubg.TanBoard, cfg Config, rnd *rand.Rand) (Record, error) {
+	var r = Record{
+		Board:      board,
+		Key:        gnubg.PositionKey(board),
+		PositionID: gnubg.PositionID(board),
+		Class:      gnubg.PositionClass(board),
+		Inputs:     gnubg.NetInputs(board),
+	}
+
+	for _, plies := range cfg.Plies {
+		s := cfg.Eval
+		s.Plies = plies
+
+		ar, err := gnubg.EvaluatePosition(board, 0, money, s)
+		if err != nil {
+			return r, err
+		}
+
+		r.Evaluations = append(r.Evaluations, Evaluation{Plies: plies, Probabilities: ar, Equity: equity(ar)})
+	}
+
+	if cfg.RolloutTrials > 0 && r.Class != "over" {
+		ro, err := RolloutPosition(board, cfg.RolloutTrials, cfg.Rollout, rnd)
+		if err != nil {
+			return r, err
+		}
+		r.Rollout = &ro
+	}
+
+	return r, nil
+}
+
+// centred cube in a money game
+var money = gnubg.Cube{Value: 1, Owner: -1}
+
+func equity(ar [5]float32) float32 {
+	return 2*ar[0] - 1 + ar[1] - ar[3] + ar[2] - ar[4]
+}
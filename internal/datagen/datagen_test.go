@@ -0,0 +1,208 @@
+package datagen
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var once sync.Once
+
+func setup() {
+	if err := gnubg.Init(os.DirFS("../../cmd/bgweb-api/data")); err != nil {
+		panic(err)
+	}
+}
+
+func generate(t *testing.T, src Source, cfg Config) []Record {
+	var ret []Record
+	if err := Generate(src, cfg, func(r Record) error {
+		ret = append(ret, r)
+		return nil
+	}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	return ret
+}
+
+func TestGenerate(t *testing.T) {
+	once.Do(setup)
+
+	var cfg = Config{Plies: []int{0, 1}, Seed: 1, Parallel: 2}
+	var selfPlay = func() Source {
+		return &SelfPlay{Games: 3, Settings: gnubg.Settings{Noise: 0.05, Deterministic: true}, Rand: rand.New(rand.NewSource(1))}
+	}
+
+	got := generate(t, selfPlay(), cfg)
+
+	var games int
+	for i, r := range got {
+		if r.Move == 0 {
+			games++
+			if r.Board != startBoard {
+				t.Errorf("Generate() game %v starts with %v", r.Game, r.Board)
+			}
+		} else if prev := got[i-1]; prev.Game != r.Game || prev.Move != r.Move-1 {
+			t.Errorf("Generate() record %v out of order", i)
+		}
+
+		if len(r.Evaluations) != 2 || r.Evaluations[1].Plies != 1 {
+			t.Errorf("Generate() evaluations = %+v", r.Evaluations)
+		}
+		if r.Class == "contact" && len(r.Inputs) != 250 {
+			t.Errorf("Generate() got %v inputs for a contact position", len(r.Inputs))
+		}
+	}
+	if games != 3 {
+		t.Errorf("Generate() got %v games, want 3", games)
+	}
+	if last := got[len(got)-1]; last.Class != "over" {
+		t.Errorf("Generate() last position class = %v, want over", last.Class)
+	}
+
+	/* the same seed gives the same data */
+	if again := generate(t, selfPlay(), cfg); !reflect.DeepEqual(again, got) {
+		t.Errorf("Generate() is not reproducible")
+	}
+
+	cfg.Sample = 0.2
+	if sampled := generate(t, selfPlay(), cfg); len(sampled) == 0 || len(sampled) >= len(got)/2 {
+		t.Errorf("Generate() kept %v of %v positions sampling 0.2", len(sampled), len(got))
+	}
+}
+
+func TestPositions(t *testing.T) {
+	once.Do(setup)
+
+	const data = `{"board":{"x":{"6":5,"8":3,"13":5,"24":2},"o":{"6":5,"8":3,"13":5,"24":2}},"player":"x"}
+
+{"board":{"x":{"1":1},"o":{"6":15}},"player":"x","target":{"win":1}}
+`
+
+	got := generate(t, NewPositions(strings.NewReader(data)), Config{Plies: []int{0}, RolloutTrials: 10, Rollout: gnubg.Settings{Prune: true}})
+
+	if len(got) != 2 {
+		t.Fatalf("Generate() got %v records, want 2", len(got))
+	}
+	if got[0].Class != "contact" || got[0].Game != 0 || got[1].Game != 1 {
+		t.Errorf("Generate() = %+v, %+v", got[0], got[1])
+	}
+
+	/* x bears off the last chequer, with o's all in their home board */
+	if ro := got[1].Rollout; ro == nil || ro.Probabilities != [5]float32{1, 1, 0, 0, 0} || ro.Equity != 2 || ro.StdErr != 0 {
+		t.Errorf("Generate() rollout = %+v, want a sure gammon", ro)
+	}
+
+	if _, _, err := NewPositions(strings.NewReader("{")).Next(); err == nil {
+		t.Errorf("Next() error = nil, want an error")
+	}
+}
+
+const matMatch = ` 3 point match
+
+ Game 1
+ alice : 0                           bob : 0
+  1)                                 31: 8/5 6/5
+  2) 64: 24/14                        Doubles => 2
+  3)  Drops
+                                      Wins 1 point
+
+ Game 2
+ alice : 0                           bob : 1
+  1) 52: 13/8 13/11                  43: 24/20 24/21
+  2) 31: 8/5* 6/5                    64: bar/21 13/7
+  3)  Doubles => 2                    Takes
+  4) 66: 13/7(2) 8/2(2)
+      Wins 4 points and the match
+`
+
+func TestMatchFiles(t *testing.T) {
+	once.Do(setup)
+
+	var dir = t.TempDir()
+	var file = filepath.Join(dir, "match.mat")
+	if err := os.WriteFile(file, []byte(matMatch), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := generate(t, &MatchFiles{Files: []string{file}}, Config{Plies: []int{0}})
+
+	/* the plays of each game, cube actions left out */
+	var moves [2]int
+	for _, r := range got {
+		moves[r.Game]++
+	}
+	if len(got) != 7 || moves != [2]int{2, 5} {
+		t.Fatalf("Generate() got %v records, %v per game, want 2 and 5", len(got), moves)
+	}
+	if got[0].Board != startBoard || got[5].Class != "contact" {
+		t.Errorf("Generate() = %+v, %+v", got[0], got[5])
+	}
+	/* o's first play, 24/18 18/14 after x's 8/5 6/5 */
+	if want := (gnubg.TanBoard{{4: 2, 5: 4, 7: 2, 12: 5, 23: 2}, {5: 5, 7: 3, 12: 5, 23: 2}}); got[1].Board != want {
+		t.Errorf("Generate() second board = %v, want %v", got[1].Board, want)
+	}
+
+	if _, _, err := (&MatchFiles{Files: []string{filepath.Join(dir, "match.gam")}}).Next(); err == nil {
+		t.Errorf("Next() error = nil, want an error")
+	}
+}
+
+func TestBinary(t *testing.T) {
+	once.Do(setup)
+
+	var src = &SelfPlay{Games: 1, Settings: gnubg.Settings{}, Rand: rand.New(rand.NewSource(2))}
+	want := generate(t, src, Config{Plies: []int{0}, Sample: 0.5, RolloutTrials: 2})
+	want[0].Rollout = nil
+
+	var buf bytes.Buffer
+	bw, err := NewBinaryWriter(&buf)
+	if err != nil {
+		t.Fatalf("NewBinaryWriter() error = %v", err)
+	}
+	for _, r := range want {
+		if err := bw.Write(r); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	br, err := NewBinaryReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewBinaryReader() error = %v", err)
+	}
+
+	var got []Record
+	for {
+		r, err := br.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		got = append(got, r)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %+v, want %+v", got, want)
+	}
+
+	/* a record cut short */
+	br, _ = NewBinaryReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	for err = nil; err == nil; _, err = br.Read() {
+	}
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("Read() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+
+	if _, err := NewBinaryReader(strings.NewReader("GNUBG\n")); err == nil {
+		t.Errorf("NewBinaryReader() error = nil, want an error")
+	}
+}
@@ -0,0 +1,209 @@
+package datagen
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// NewJSONWriter returns a function writing records as JSON lines.
+func NewJSONWriter(w io.Writer) func(Record) error {
+	enc := json.NewEncoder(w)
+	return func(r Record) error {
+		return enc.Encode(r)
+	}
+}
+
+// The binary format starts with the magic bytes "BGDG" and a uint16
+// version, followed by the records. All numbers are little-endian.
+//
+//	int32     game
+//	int32     move
+//	50 bytes  board[0][0..24], board[1][0..24]
+//	28 bytes  key (7 big-endian uint32, as in the hexadecimal string)
+//	uint8     length of the class name, then the name
+//	uint16    number of inputs, then the inputs as float32
+//	uint8     number of evaluations, then for each:
+//	            uint8 plies, 5 float32 probabilities, float32 equity
+//	uint8     1 if a rollout follows, else 0; then:
+//	            uint32 trials, 5 float32 probabilities,
+//	            float32 equity, float32 std-err
+const (
+	binaryMagic   = "BGDG"
+	binaryVersion = 1
+)
+
+// BinaryWriter writes records in the binary format.
+type BinaryWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+// NewBinaryWriter writes the header of the binary format and returns a
+// writer for the records.
+func NewBinaryWriter(w io.Writer) (*BinaryWriter, error) {
+	var header = append([]byte(binaryMagic), 0, 0)
+	binary.LittleEndian.PutUint16(header[4:], binaryVersion)
+
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &BinaryWriter{w: w}, nil
+}
+
+// Write writes a record.
+func (bw *BinaryWriter) Write(r Record) error {
+	key, err := hex.DecodeString(r.Key)
+	if err != nil || len(key) != 28 {
+		return fmt.Errorf("invalid key: %v", r.Key)
+	}
+	if len(r.Class) > math.MaxUint8 || len(r.Inputs) > math.MaxUint16 || len(r.Evaluations) > math.MaxUint8 {
+		return errors.New("record too large")
+	}
+
+	var b = &bw.buf
+	b.Reset()
+
+	put(b, int32(r.Game), int32(r.Move))
+	for side := range r.Board {
+		for _, n := range r.Board[side] {
+			b.WriteByte(byte(n))
+		}
+	}
+	b.Write(key)
+	b.WriteByte(byte(len(r.Class)))
+	b.WriteString(r.Class)
+	put(b, uint16(len(r.Inputs)), r.Inputs)
+
+	b.WriteByte(byte(len(r.Evaluations)))
+	for _, e := range r.Evaluations {
+		put(b, uint8(e.Plies), e.Probabilities, e.Equity)
+	}
+
+	if r.Rollout == nil {
+		b.WriteByte(0)
+	} else {
+		b.WriteByte(1)
+		put(b, uint32(r.Rollout.Trials), r.Rollout.Probabilities, r.Rollout.Equity, r.Rollout.StdErr)
+	}
+
+	_, err = bw.w.Write(b.Bytes())
+	return err
+}
+
+func put(b *bytes.Buffer, values ...interface{}) {
+	for _, v := range values {
+		/* writing to a buffer can't fail */
+		_ = binary.Write(b, binary.LittleEndian, v)
+	}
+}
+
+// BinaryReader reads records written by BinaryWriter.
+type BinaryReader struct {
+	r io.Reader
+}
+
+// NewBinaryReader checks the header of the binary format and returns a
+// reader for the records.
+func NewBinaryReader(r io.Reader) (*BinaryReader, error) {
+	var header [6]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != binaryMagic {
+		return nil, errors.New("not a training data file")
+	}
+	if v := binary.LittleEndian.Uint16(header[4:]); v != binaryVersion {
+		return nil, fmt.Errorf("unsupported version: %v", v)
+	}
+
+	return &BinaryReader{r: r}, nil
+}
+
+// Read reads the next record; io.EOF tells there are no more.
+func (br *BinaryReader) Read() (Record, error) {
+	var r Record
+
+	var game, move int32
+	if err := binary.Read(br.r, binary.LittleEndian, &game); err != nil {
+		/* io.EOF only between records */
+		return r, err
+	}
+
+	var key [28]byte
+	var cClass uint8
+	var board [2][25]uint8
+
+	if err := get(br.r, &move, &board, &key, &cClass); err != nil {
+		return r, err
+	}
+
+	var class = make([]byte, cClass)
+	var cInputs uint16
+	if err := get(br.r, class, &cInputs); err != nil {
+		return r, err
+	}
+
+	if cInputs > 0 {
+		r.Inputs = make([]float32, cInputs)
+	}
+	var cEvals uint8
+	if err := get(br.r, r.Inputs, &cEvals); err != nil {
+		return r, err
+	}
+
+	r.Evaluations = make([]Evaluation, cEvals)
+	for i := range r.Evaluations {
+		var plies uint8
+		e := &r.Evaluations[i]
+		if err := get(br.r, &plies, &e.Probabilities, &e.Equity); err != nil {
+			return r, err
+		}
+		e.Plies = int(plies)
+	}
+
+	var fRollout uint8
+	if err := get(br.r, &fRollout); err != nil {
+		return r, err
+	}
+	if fRollout != 0 {
+		var trials uint32
+		r.Rollout = &Rollout{}
+		if err := get(br.r, &trials, &r.Rollout.Probabilities, &r.Rollout.Equity, &r.Rollout.StdErr); err != nil {
+			return r, err
+		}
+		r.Rollout.Trials = int(trials)
+	}
+
+	r.Game, r.Move = int(game), int(move)
+	for side := range board {
+		for i, n := range board[side] {
+			r.Board[side][i] = int(n)
+		}
+	}
+	r.Key = hex.EncodeToString(key[:])
+	r.PositionID = gnubg.PositionID(r.Board)
+	r.Class = string(class)
+
+	return r, nil
+}
+
+func get(r io.Reader, values ...interface{}) error {
+	for _, v := range values {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			if err == io.EOF {
+				/* a record cut short */
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package datagen
+
+import (
+	"bgweb-api/internal/gnubg"
+	"math"
+	"math/rand"
+)
+
+// Rollout is the result of playing a position out many times.
+type Rollout struct {
+	Trials        int        `json:"trials"`
+	Probabilities [5]float32 `json:"probabilities"` // as in Evaluation
+	Equity        float32    `json:"equity"`
+	StdErr        float32    `json:"std-err"` // of the equity
+}
+
+// RolloutPosition plays the position out the given number of times,
+// cubeless, with the engine choosing the plays for both sides, and
+// averages the results for the player on roll.
+func RolloutPosition(board gnubg.TanBoard, trials int, s gnubg.Settings, rnd *rand.Rand) (Rollout, error) {
+	var ret = Rollout{Trials: trials}
+	var sum, sumSquares float64
+
+	s.Cubeful = false
+
+	for i := 0; i < trials; i++ {
+		game, err := playGame(board, s, rnd)
+		if err != nil {
+			return ret, err
+		}
+
+		ar := outcome(game)
+		for j := range ar {
+			ret.Probabilities[j] += ar[j]
+		}
+
+		r := float64(equity(ar))
+		sum += r
+		sumSquares += r * r
+	}
+
+	var n = float64(trials)
+	for j := range ret.Probabilities {
+		ret.Probabilities[j] /= float32(trials)
+	}
+	ret.Equity = float32(sum / n)
+	if trials > 1 {
+		ret.StdErr = float32(math.Sqrt((sumSquares - sum*sum/n) / (n - 1) / n))
+	}
+
+	return ret, nil
+}
+
+// The result of a played out game, as chances of the player on roll at
+// its start. Unfinished games count as even.
+func outcome(game []gnubg.TanBoard) [5]float32 {
+	var ar [5]float32
+
+	final := game[len(game)-1]
+	if !gameOver(final) {
+		ar[0] = 0.5
+		return ar
+	}
+
+	/* after the winner's last play the loser is on roll */
+	var loser = final[1]
+	var firstWon = len(game)%2 == 0
+
+	var gammon = chequers(loser) == 15
+	var backgammon = false
+	if gammon {
+		/* chequers on the bar or in the winner's home board */
+		for i := 18; i < 25; i++ {
+			if loser[i] > 0 {
+				backgammon = true
+			}
+		}
+	}
+
+	var iGammon, iBackgammon = 1, 2
+	if firstWon {
+		ar[0] = 1
+	} else {
+		iGammon, iBackgammon = 3, 4
+	}
+	if gammon {
+		ar[iGammon] = 1
+	}
+	if backgammon {
+		ar[iBackgammon] = 1
+	}
+
+	return ar
+}
@@ -0,0 +1,182 @@
+package datagen
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/layout"
+	"bgweb-api/internal/match"
+	"bgweb-api/internal/openapi"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// starting position, seen from the player on roll
+var startBoard = gnubg.TanBoard{
+	{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+	{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+}
+
+// SelfPlay is a source of cubeless money games the engine plays against
+// itself. Noise in the settings makes the games more varied; without
+// deterministic noise they can't be reproduced from the seed.
+type SelfPlay struct {
+	Games    int
+	Settings gnubg.Settings
+	Rand     *rand.Rand // dice
+
+	played int
+}
+
+// Next plays the next game and returns every position in it, ending with
+// the final one.
+func (sp *SelfPlay) Next() ([]gnubg.TanBoard, bool, error) {
+	if sp.played >= sp.Games {
+		return nil, false, nil
+	}
+	sp.played++
+
+	game, err := playGame(startBoard, sp.Settings, sp.Rand)
+	return game, err == nil, err
+}
+
+// a game longer than this is abandoned
+const maxGameMoves = 2000
+
+// Play to the end from the board, the player on roll rolling first.
+func playGame(board gnubg.TanBoard, s gnubg.Settings, rnd *rand.Rand) ([]gnubg.TanBoard, error) {
+	var ret = []gnubg.TanBoard{board}
+
+	/* the players swap sides on the board but keep their seat */
+	for n := 0; n < maxGameMoves && !gameOver(board); n++ {
+		dice := [2]int{rnd.Intn(6) + 1, rnd.Intn(6) + 1}
+
+		after, err := gnubg.BestPlay(board, dice, n%2, money, s)
+		if err != nil {
+			return nil, err
+		}
+
+		board = gnubg.SwapSides(after)
+		ret = append(ret, board)
+	}
+
+	return ret, nil
+}
+
+func gameOver(board gnubg.TanBoard) bool {
+	return chequers(board[0]) == 0 || chequers(board[1]) == 0
+}
+
+func chequers(side [25]int) int {
+	var c int
+	for _, n := range side {
+		c += n
+	}
+	return c
+}
+
+type positionLine struct {
+	Board  openapi.Board `json:"board"`
+	Player string        `json:"player"`
+}
+
+// Positions is a source reading one position per line, as JSON with the
+// "board" and "player" of a /getmoves request; other fields are ignored,
+// so training example files can be read too. Each position makes a game
+// of its own.
+type Positions struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewPositions returns a source reading positions from r.
+func NewPositions(r io.Reader) *Positions {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	return &Positions{scanner: scanner}
+}
+
+// Next reads the next position.
+func (p *Positions) Next() ([]gnubg.TanBoard, bool, error) {
+	for p.scanner.Scan() {
+		p.line++
+		if len(p.scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var l positionLine
+		if err := json.Unmarshal(p.scanner.Bytes(), &l); err != nil {
+			return nil, false, fmt.Errorf("line %v: %v", p.line, err)
+		}
+
+		board := layout.TanBoard(l.Board)
+		if l.Player == "x" {
+			board = gnubg.SwapSides(board)
+		}
+
+		return []gnubg.TanBoard{board}, true, nil
+	}
+
+	return nil, false, p.scanner.Err()
+}
+
+// MatchFiles is a source reading the games of gnubg .sgf and Jellyfish
+// .mat or .txt match files, in turn. A game gives the positions its
+// players rolled in, seen from the player on roll; Config.Sample keeps
+// some of them.
+type MatchFiles struct {
+	Files []string
+
+	next  int
+	games [][]gnubg.TanBoard
+}
+
+// Next returns the next game, reading the next file when the games of the
+// last one are used up.
+func (mf *MatchFiles) Next() ([]gnubg.TanBoard, bool, error) {
+	for len(mf.games) == 0 {
+		if mf.next >= len(mf.Files) {
+			return nil, false, nil
+		}
+		mf.next++
+
+		games, err := matchGames(mf.Files[mf.next-1])
+		if err != nil {
+			return nil, false, err
+		}
+		mf.games = games
+	}
+
+	game := mf.games[0]
+	mf.games = mf.games[1:]
+	return game, true, nil
+}
+
+// The positions before every play of every game of a match file, leaving
+// out games without any.
+func matchGames(file string) ([][]gnubg.TanBoard, error) {
+	m, err := match.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var games = make([][]gnubg.TanBoard, len(m.Games))
+	if err := m.Replay(func(p match.Position, a match.Action) error {
+		if a.Type == match.ActionMove {
+			games[p.Game] = append(games[p.Game], p.MoverBoard())
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("%v: %w", file, err)
+	}
+
+	var ret [][]gnubg.TanBoard
+	for _, game := range games {
+		if len(game) > 0 {
+			ret = append(ret, game)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,432 @@
+// Package diagram draws backgammon positions as SVG images.
+//
+// Players are numbered as in gnubg: 0 is o and 1 is x. Boards are in the
+// {x, o} layout of the API.
+package diagram
+
+import (
+	"fmt"
+	"strings"
+
+	"bgweb-api/internal/gnubg"
+)
+
+// Diagram is a position and how to draw it.
+type Diagram struct {
+	Board gnubg.TanBoard
+	// the cube, dice and player on roll; Move is 1 when x is on roll
+	Match gnubg.MatchState
+
+	// player drawn at the bottom, and whether their home board is on the
+	// left rather than the right
+	Bottom   int
+	HomeLeft bool
+	// player whose point numbers are shown, -1 for none
+	Numbers int
+	// whether the pip counts are shown
+	Pips  bool
+	Theme Theme
+
+	// chequer moves of the player on roll drawn as arrows, from their side
+	// as gnubg.Play.Moves: counted from 0, 24 the bar and -1 off
+	Play [][2]int
+}
+
+// Theme holds the colours of a diagram.
+type Theme struct {
+	Background, Frame, Board string
+	// alternate points
+	Points [2]string
+	// chequers of o and x, and the outlines and numbers on them
+	Chequers, ChequerLines [2]string
+	Cube, CubeText         string
+	Text, Arrow            string
+}
+
+// Themes are the colour themes diagrams can be drawn with.
+var Themes = map[string]Theme{
+	"classic": {
+		Background: "#ffffff", Frame: "#6d4c41", Board: "#f3e5ab",
+		Points:       [2]string{"#a1887f", "#5d4037"},
+		Chequers:     [2]string{"#212121", "#fafafa"},
+		ChequerLines: [2]string{"#fafafa", "#212121"},
+		Cube:         "#ffffff", CubeText: "#212121",
+		Text: "#212121", Arrow: "#d32f2f",
+	},
+	"blue": {
+		Background: "#ffffff", Frame: "#0d47a1", Board: "#e3f2fd",
+		Points:       [2]string{"#90caf9", "#1976d2"},
+		Chequers:     [2]string{"#c62828", "#fafafa"},
+		ChequerLines: [2]string{"#fafafa", "#0d47a1"},
+		Cube:         "#ffffff", CubeText: "#0d47a1",
+		Text: "#0d47a1", Arrow: "#ff6f00",
+	},
+	"dark": {
+		Background: "#121212", Frame: "#37474f", Board: "#263238",
+		Points:       [2]string{"#546e7a", "#8d6e63"},
+		Chequers:     [2]string{"#ef5350", "#eceff1"},
+		ChequerLines: [2]string{"#212121", "#212121"},
+		Cube:         "#eceff1", CubeText: "#212121",
+		Text: "#eceff1", Arrow: "#ffca28",
+	},
+	"mono": {
+		Background: "#ffffff", Frame: "#000000", Board: "#ffffff",
+		Points:       [2]string{"#ffffff", "#9e9e9e"},
+		Chequers:     [2]string{"#000000", "#ffffff"},
+		ChequerLines: [2]string{"#ffffff", "#000000"},
+		Cube:         "#ffffff", CubeText: "#000000",
+		Text: "#000000", Arrow: "#000000",
+	},
+}
+
+// DefaultTheme is the theme used unless another is asked for.
+const DefaultTheme = "classic"
+
+/* sizes */
+const (
+	margin     = 10
+	trayWidth  = 50
+	pointWidth = 40
+	barWidth   = 50
+	radius     = 18
+	// height of a point, and of the band between the two rows of points
+	pointHeight = 5 * 2 * radius
+	midBand     = 40
+	// bands of point numbers and pip counts
+	textBand = 20
+	// chequers drawn on a point before the stack is numbered
+	maxStack = 5
+
+	halfWidth   = 6 * pointWidth
+	boardHeight = 2*pointHeight + midBand
+	width       = 2*margin + 2*trayWidth + 2*halfWidth + barWidth
+)
+
+/* where things are drawn */
+type layout struct {
+	d                   Diagram
+	boardTop, boardLeft int
+	height              int
+}
+
+func newLayout(d Diagram) layout {
+	var l = layout{d: d, boardLeft: margin + trayWidth}
+
+	var y = margin
+	if d.Pips {
+		y += textBand
+	}
+	if d.Numbers >= 0 {
+		y += textBand
+	}
+	l.boardTop = y
+	l.height = 2*y + boardHeight
+
+	return l
+}
+
+func (l layout) boardBottom() int {
+	return l.boardTop + boardHeight
+}
+
+func (l layout) midY() int {
+	return l.boardTop + boardHeight/2
+}
+
+/* left edge of a column of points, 0 to 11 from the left as if the
+ * bottom player's home board were on the right */
+func (l layout) columnX(c int) int {
+	if l.d.HomeLeft {
+		c = 11 - c
+	}
+	if c < 6 {
+		return l.boardLeft + c*pointWidth
+	}
+	return l.boardLeft + halfWidth + barWidth + (c-6)*pointWidth
+}
+
+/* column and row (true for the bottom) of a point of a player, counted
+ * from 0 on their side */
+func (l layout) point(player int, n int) (int, bool) {
+	if player != l.d.Bottom {
+		n = 23 - n
+	}
+	if n < 12 {
+		return 11 - n, true
+	}
+	return n - 12, false
+}
+
+func (l layout) barX() int {
+	return l.boardLeft + halfWidth + barWidth/2
+}
+
+/* left edge of the tray holding borne off chequers, on the home side, or
+ * else the cube */
+func (l layout) trayX(home bool) int {
+	var right = margin + trayWidth + 2*halfWidth + barWidth
+	if home != l.d.HomeLeft {
+		return right
+	}
+	return margin
+}
+
+/* centre of the i-th chequer from the edge on a point, the bar (24) or
+ * the tray (-1) of a player */
+func (l layout) slot(player int, n int, i int) (int, int) {
+	var bottom = player == l.d.Bottom
+	if i >= maxStack {
+		i = maxStack - 1
+	}
+
+	switch n {
+	case 24:
+		/* on the bar, from the middle out */
+		if bottom {
+			return l.barX(), l.midY() + 2*radius + 2*radius*i
+		}
+		return l.barX(), l.midY() - 2*radius - 2*radius*i
+	case -1:
+		var x = l.trayX(true) + trayWidth/2
+		if bottom {
+			return x, l.boardBottom() - 4 - 10*i
+		}
+		return x, l.boardTop + 4 + 10*i
+	}
+
+	c, fBottom := l.point(player, n)
+	var x = l.columnX(c) + pointWidth/2
+	if fBottom {
+		return x, l.boardBottom() - radius - 2*radius*i
+	}
+	return x, l.boardTop + radius + 2*radius*i
+}
+
+// SVG draws a diagram as an SVG image.
+func SVG(d Diagram) string {
+	var l = newLayout(d)
+	var t = d.Theme
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" font-family="sans-serif">`+"\n", width, l.height, width, l.height)
+	fmt.Fprintf(&sb, `<defs><marker id="arrowhead" markerWidth="8" markerHeight="8" refX="6" refY="4" orient="auto"><path d="M0,0 L8,4 L0,8 z" fill="%v"/></marker></defs>`+"\n", t.Arrow)
+	fmt.Fprintf(&sb, `<rect width="%v" height="%v" fill="%v"/>`+"\n", width, l.height, t.Background)
+
+	/* frame, playing areas and trays */
+	fmt.Fprintf(&sb, `<rect x="%v" y="%v" width="%v" height="%v" fill="%v"/>`+"\n", margin, l.boardTop-4, width-2*margin, boardHeight+8, t.Frame)
+	for _, x := range []int{l.boardLeft, l.boardLeft + halfWidth + barWidth} {
+		fmt.Fprintf(&sb, `<rect x="%v" y="%v" width="%v" height="%v" fill="%v"/>`+"\n", x, l.boardTop, halfWidth, boardHeight, t.Board)
+	}
+	for _, home := range []bool{true, false} {
+		fmt.Fprintf(&sb, `<rect x="%v" y="%v" width="%v" height="%v" fill="%v"/>`+"\n", l.trayX(home)+4, l.boardTop, trayWidth-8, boardHeight, t.Board)
+	}
+
+	drawPoints(&sb, l)
+	drawChequers(&sb, l)
+	drawCube(&sb, l)
+	drawDice(&sb, l)
+	drawPlay(&sb, l)
+	drawText(&sb, l)
+
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func drawPoints(sb *strings.Builder, l layout) {
+	for c := 0; c < 12; c++ {
+		var x = l.columnX(c)
+		var top, bottom = l.boardTop, l.boardBottom()
+		fmt.Fprintf(sb, `<polygon points="%v,%v %v,%v %v,%v" fill="%v"/>`+"\n",
+			x, top, x+pointWidth, top, x+pointWidth/2, top+pointHeight-4, l.d.Theme.Points[c%2])
+		fmt.Fprintf(sb, `<polygon points="%v,%v %v,%v %v,%v" fill="%v"/>`+"\n",
+			x, bottom, x+pointWidth, bottom, x+pointWidth/2, bottom-pointHeight+4, l.d.Theme.Points[(c+1)%2])
+	}
+}
+
+/* the chequers of each player, from their own side */
+func sides(board gnubg.TanBoard) [2][25]int {
+	return [2][25]int{board[1], board[0]}
+}
+
+func drawChequers(sb *strings.Builder, l layout) {
+	var t = l.d.Theme
+	var aSide = sides(l.d.Board)
+
+	for player := 0; player < 2; player++ {
+		var cOff = 15
+		for n := 0; n < 25; n++ {
+			var c = aSide[player][n]
+			cOff -= c
+			for i := 0; i < c && i < maxStack; i++ {
+				x, y := l.slot(player, n, i)
+				fmt.Fprintf(sb, `<circle cx="%v" cy="%v" r="%v" fill="%v" stroke="%v" stroke-width="1.5"/>`+"\n", x, y, radius-1, t.Chequers[player], t.ChequerLines[player])
+			}
+			if c > maxStack {
+				x, y := l.slot(player, n, maxStack-1)
+				fmt.Fprintf(sb, `<text x="%v" y="%v" font-size="16" font-weight="bold" text-anchor="middle" fill="%v">%v</text>`+"\n", x, y+6, t.ChequerLines[player], c)
+			}
+		}
+
+		/* borne off chequers, on edge */
+		for i := 0; i < cOff; i++ {
+			x, y := l.slot(player, -1, 0)
+			var dy = 10 * i
+			if player == l.d.Bottom {
+				y -= dy + 8
+			} else {
+				y += dy
+			}
+			fmt.Fprintf(sb, `<rect x="%v" y="%v" width="%v" height="8" fill="%v" stroke="%v"/>`+"\n", x-(trayWidth-16)/2, y, trayWidth-16, t.Chequers[player], t.ChequerLines[player])
+		}
+	}
+}
+
+func drawCube(sb *strings.Builder, l layout) {
+	var cube = l.d.Match.Cube
+	if cube.Value < 1 {
+		return
+	}
+
+	const size = 36
+	var x = l.trayX(false) + (trayWidth-size)/2
+	var y = l.midY() - size/2
+	var value = cube.Value
+	switch {
+	case l.d.Match.Doubled:
+		/* offered, in the middle of the board on the doubler's side */
+		value *= 2
+		x = l.boardLeft + halfWidth/2 - size/2
+		if (l.d.Match.Move == l.d.Bottom) != l.d.HomeLeft {
+			x += halfWidth + barWidth
+		}
+	case cube.Owner == l.d.Bottom:
+		y = l.boardBottom() - size - 4
+	case cube.Owner >= 0:
+		y = l.boardTop + 4
+	case value == 1:
+		value = 64
+	}
+
+	fmt.Fprintf(sb, `<rect x="%v" y="%v" width="%v" height="%v" rx="4" fill="%v" stroke="%v"/>`+"\n", x, y, size, size, l.d.Theme.Cube, l.d.Theme.CubeText)
+	fmt.Fprintf(sb, `<text x="%v" y="%v" font-size="18" font-weight="bold" text-anchor="middle" fill="%v">%v</text>`+"\n", x+size/2, y+size/2+6, l.d.Theme.CubeText, value)
+}
+
+/* pips of a die, on a 3 by 3 grid */
+var diePips = [7][][2]int{
+	{},
+	{{1, 1}},
+	{{0, 0}, {2, 2}},
+	{{0, 0}, {1, 1}, {2, 2}},
+	{{0, 0}, {2, 0}, {0, 2}, {2, 2}},
+	{{0, 0}, {2, 0}, {1, 1}, {0, 2}, {2, 2}},
+	{{0, 0}, {2, 0}, {0, 1}, {2, 1}, {0, 2}, {2, 2}},
+}
+
+/* the dice of the player on roll, in the middle of their half: the right
+ * one for the bottom player */
+func drawDice(sb *strings.Builder, l layout) {
+	var ms = l.d.Match
+	if ms.Dice[0] == 0 || ms.Doubled {
+		return
+	}
+
+	const size = 30
+	var player = ms.Move
+	var x = l.boardLeft + halfWidth/2
+	if (player == l.d.Bottom) != l.d.HomeLeft {
+		x += halfWidth + barWidth
+	}
+	var y = l.midY() - size/2
+
+	for i, n := range ms.Dice {
+		if n < 1 || n > 6 {
+			continue
+		}
+		var dx = x - size - 4 + i*(size+8)
+		fmt.Fprintf(sb, `<rect x="%v" y="%v" width="%v" height="%v" rx="5" fill="%v" stroke="%v" stroke-width="1.5"/>`+"\n", dx, y, size, size, l.d.Theme.Chequers[player], l.d.Theme.ChequerLines[player])
+		for _, p := range diePips[n] {
+			fmt.Fprintf(sb, `<circle cx="%v" cy="%v" r="3" fill="%v"/>`+"\n", dx+7+p[0]*8, y+7+p[1]*8, l.d.Theme.ChequerLines[player])
+		}
+	}
+}
+
+/* arrows from each chequer moved to where it lands, the landing spot
+ * outlined */
+func drawPlay(sb *strings.Builder, l layout) {
+	if len(l.d.Play) == 0 {
+		return
+	}
+
+	var mover, opp = l.d.Match.Move, 1 - l.d.Match.Move
+	var aSide = sides(l.d.Board)
+	var cOff = 15
+	for _, c := range aSide[mover] {
+		cOff -= c
+	}
+
+	for _, m := range l.d.Play {
+		var from, to = m[0], m[1]
+		if from < 0 || from > 24 || to < -1 || to > 23 || aSide[mover][from] == 0 {
+			continue
+		}
+
+		aSide[mover][from]--
+		x1, y1 := l.slot(mover, from, aSide[mover][from])
+
+		var x2, y2 int
+		if to < 0 {
+			x2, y2 = l.slot(mover, -1, 0)
+			if mover == l.d.Bottom {
+				y2 -= 10*cOff + 4
+			} else {
+				y2 += 10*cOff + 4
+			}
+			cOff++
+		} else {
+			if aSide[opp][23-to] == 1 {
+				/* hit */
+				aSide[opp][23-to] = 0
+				aSide[opp][24]++
+			}
+			x2, y2 = l.slot(mover, to, aSide[mover][to])
+			aSide[mover][to]++
+			fmt.Fprintf(sb, `<circle cx="%v" cy="%v" r="%v" fill="none" stroke="%v" stroke-width="2" stroke-dasharray="4 3"/>`+"\n", x2, y2, radius-1, l.d.Theme.Arrow)
+		}
+
+		fmt.Fprintf(sb, `<line x1="%v" y1="%v" x2="%v" y2="%v" stroke="%v" stroke-width="3" marker-end="url(#arrowhead)"/>`+"\n", x1, y1, x2, y2, l.d.Theme.Arrow)
+	}
+}
+
+func drawText(sb *strings.Builder, l layout) {
+	var t = l.d.Theme
+	var bottom, top = l.d.Bottom, 1 - l.d.Bottom
+
+	if n := l.d.Numbers; n >= 0 {
+		for c := 0; c < 12; c++ {
+			var x = l.columnX(c) + pointWidth/2
+			/* the bottom player's points on this column */
+			var nBottom, nTop = 11 - c, 12 + c
+			if n != bottom {
+				nBottom, nTop = 23-nBottom, 23-nTop
+			}
+			fmt.Fprintf(sb, `<text x="%v" y="%v" font-size="12" text-anchor="middle" fill="%v">%v</text>`+"\n", x, l.boardTop-8, t.Text, nTop+1)
+			fmt.Fprintf(sb, `<text x="%v" y="%v" font-size="12" text-anchor="middle" fill="%v">%v</text>`+"\n", x, l.boardBottom()+18, t.Text, nBottom+1)
+		}
+	}
+
+	if l.d.Pips {
+		var aSide = sides(l.d.Board)
+		var letters = [2]string{"O", "X"}
+		var yTop, yBottom = margin + 14, l.height - margin - 4
+		fmt.Fprintf(sb, `<text x="%v" y="%v" font-size="14" fill="%v">%v: %v pips</text>`+"\n", l.boardLeft, yTop, t.Text, letters[top], pips(aSide[top]))
+		fmt.Fprintf(sb, `<text x="%v" y="%v" font-size="14" fill="%v">%v: %v pips</text>`+"\n", l.boardLeft, yBottom, t.Text, letters[bottom], pips(aSide[bottom]))
+	}
+}
+
+func pips(side [25]int) int {
+	var n int
+	for i, c := range side {
+		n += (i + 1) * c
+	}
+	return n
+}
@@ -0,0 +1,109 @@
+package diagram
+
+import (
+	"bgweb-api/internal/gnubg"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+	var start = gnubg.TanBoard{
+		{5: 5, 7: 3, 12: 5, 23: 2},
+		{5: 5, 7: 3, 12: 5, 23: 2},
+	}
+	var money = gnubg.MatchState{Cube: gnubg.Cube{Value: 1, Owner: -1}, Move: 1, Turn: 1, Dice: [2]int{3, 1}, GameState: 1}
+
+	/* x with 7 on their 6 point, one on the bar and one off */
+	var stacked = gnubg.TanBoard{
+		{5: 5, 7: 3, 12: 5, 23: 2},
+		{5: 7, 7: 3, 12: 2, 23: 1, 24: 1},
+	}
+
+	tests := []struct {
+		name    string
+		d       Diagram
+		want    []string
+		notWant []string
+		// chequers, pips of the dice and landing spots of a play
+		circles int
+		lines   int
+	}{
+		{
+			name:    "should draw the starting position",
+			d:       Diagram{Board: start, Match: money, Bottom: 1, Numbers: 1, Pips: true, Theme: Themes["classic"]},
+			want:    []string{`>24</text>`, `>X: 167 pips</text>`, `>O: 167 pips</text>`, `>64</text>`},
+			circles: 30 + 4,
+		},
+		{
+			name:    "should draw the bottom player's home board on the left",
+			d:       Diagram{Board: start, Match: money, Bottom: 1, HomeLeft: true, Numbers: 1, Theme: Themes["classic"]},
+			want:    []string{`<text x="80" y="448" font-size="12" text-anchor="middle" fill="#212121">1</text>`},
+			notWant: []string{`pips`},
+		},
+		{
+			name:    "should number the points from the other side",
+			d:       Diagram{Board: start, Match: money, Bottom: 1, Numbers: 0, Theme: Themes["classic"]},
+			want:    []string{`<text x="570" y="448" font-size="12" text-anchor="middle" fill="#212121">24</text>`},
+			circles: 30 + 4,
+		},
+		{
+			name:    "should leave out the point numbers",
+			d:       Diagram{Board: start, Match: money, Bottom: 1, Numbers: -1, Theme: Themes["mono"]},
+			notWant: []string{`font-size="12"`},
+		},
+		{
+			name:    "should number tall stacks and draw the bar and borne off chequers",
+			d:       Diagram{Board: stacked, Match: gnubg.MatchState{Cube: gnubg.Cube{Value: 2, Owner: 0}, Move: 0}, Bottom: 1, Numbers: 1, Theme: Themes["blue"]},
+			want:    []string{`>7</text>`, `>2</text>`, `height="8"`},
+			circles: 15 + 5 + 3 + 2 + 1 + 1,
+		},
+		{
+			name:    "should draw a play as arrows",
+			d:       Diagram{Board: start, Match: money, Bottom: 1, Numbers: 1, Theme: Themes["dark"], Play: [][2]int{{7, 4}, {5, 4}}},
+			want:    []string{`stroke-dasharray`},
+			circles: 30 + 4 + 2,
+			lines:   2,
+		},
+		{
+			name:    "should draw a hit and a chequer borne off",
+			d:       Diagram{Board: gnubg.TanBoard{{0: 1, 3: 2}, {22: 1}}, Match: money, Bottom: 1, Numbers: 1, Theme: Themes["classic"], Play: [][2]int{{3, 1}, {0, -1}}},
+			circles: 4 + 4 + 1,
+			lines:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SVG(tt.d)
+
+			var dec = xml.NewDecoder(strings.NewReader(got))
+			for {
+				if _, err := dec.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("SVG() is not well formed: %v", err)
+				}
+			}
+
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("SVG() = \n%v\nwant %q", got, s)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got, s) {
+					t.Errorf("SVG() = \n%v\ndon't want %q", got, s)
+				}
+			}
+			if tt.circles > 0 {
+				if n := strings.Count(got, "<circle"); n != tt.circles {
+					t.Errorf("SVG() has %v circles, want %v", n, tt.circles)
+				}
+			}
+			if n := strings.Count(got, "<line"); n != tt.lines {
+				t.Errorf("SVG() has %v arrows, want %v", n, tt.lines)
+			}
+		})
+	}
+}
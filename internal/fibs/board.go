@@ -0,0 +1,312 @@
+// Package fibs speaks the FIBS client protocol: the board: state strings
+// of FIBS and the servers and bots copying it. With them it plays as a bot
+// on FIBS servers and as an external player of gnubg.
+package fibs
+
+import (
+	"bgweb-api/internal/gnubg"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Board is the state a FIBS board: string describes, from the side of
+// the player it is sent to.
+//
+//	board:You:someplayer:3:0:0:0:-2:0:0:0:0:5:0:3:0:0:0:-5:5:0:0:0:-3:0:-5:0:0:0:0:2:0:1:6:2:0:0:1:1:1:0:1:-1:0:25:0:0:0:0:2:0:0:0
+//
+// FIBS names the players X and O; X is x here, player 1 as in gnubg, and
+// O is o, player 0. On the FIBS board positive counts are O's chequers and
+// negative ones X's.
+type Board struct {
+	Player, Opponent string
+	// colour of the player, 1 for X and 0 for O
+	Colour int
+	// whether the player moves from point 24 to 1 on the FIBS board
+	Down bool
+
+	// chequers in gnubg's {x, o} layout, and the match state, whose Move
+	// is 1 when X is on roll
+	Board gnubg.TanBoard
+	Match gnubg.MatchState
+}
+
+/* the fields of a board: string */
+const (
+	fieldPlayer = iota + 1
+	fieldOpponent
+	fieldMatchTo
+	fieldPlayerScore
+	fieldOpponentScore
+	fieldBoard /* 26 fields, 0 to 25 */
+)
+
+const (
+	fieldTurn = fieldBoard + 26 + iota
+	fieldPlayerDice
+	_
+	fieldOpponentDice
+	_
+	fieldCube
+	fieldPlayerMayDouble
+	fieldOpponentMayDouble
+	fieldWasDoubled
+	fieldColour
+	fieldDirection
+	fieldHome
+	fieldBar
+	fieldPlayerHome
+	fieldOpponentHome
+	fieldPlayerBar
+	fieldOpponentBar
+	fieldCanMove
+	fieldForcedMove
+	fieldDidCrawford
+	fieldRedoubles
+	cFields
+)
+
+// money play on FIBS is an unlimited match
+const unlimited = 9999
+
+// ParseBoard reads a board: string.
+func ParseBoard(s string) (Board, error) {
+	var ret Board
+
+	var fields = strings.Split(strings.TrimSpace(s), ":")
+	if len(fields) != cFields || fields[0] != "board" {
+		return ret, fmt.Errorf("a FIBS board has %v fields, not %v", cFields, len(fields))
+	}
+
+	var an [cFields]int
+	for i := fieldMatchTo; i < cFields; i++ {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return ret, fmt.Errorf("invalid FIBS board field %v: %q", i, fields[i])
+		}
+		an[i] = n
+	}
+
+	ret.Player, ret.Opponent = fields[fieldPlayer], fields[fieldOpponent]
+
+	switch an[fieldColour] {
+	case -1:
+		ret.Colour = 1
+	case 1:
+		ret.Colour = 0
+	default:
+		return ret, fmt.Errorf("invalid FIBS colour: %v", an[fieldColour])
+	}
+	switch an[fieldDirection] {
+	case -1:
+		ret.Down = true
+	case 1:
+	default:
+		return ret, fmt.Errorf("invalid FIBS direction: %v", an[fieldDirection])
+	}
+
+	var you, opp = ret.Colour, 1 - ret.Colour
+
+	/* the chequers on the points, from the side of their owner */
+	var cChequers [2]int
+	for i := 1; i <= 24; i++ {
+		n := an[fieldBoard+i]
+		if n == 0 {
+			continue
+		}
+		player := 0
+		if n < 0 {
+			player, n = 1, -n
+		}
+		ret.Board[boardSide(player)][ret.point(player, i)] = n
+		cChequers[player] += n
+	}
+	ret.Board[boardSide(you)][24] = an[fieldPlayerBar]
+	ret.Board[boardSide(opp)][24] = an[fieldOpponentBar]
+	cChequers[you] += an[fieldPlayerBar]
+	cChequers[opp] += an[fieldOpponentBar]
+	if cChequers[0] > 15 || cChequers[1] > 15 || an[fieldPlayerBar] < 0 || an[fieldOpponentBar] < 0 {
+		return ret, fmt.Errorf("invalid FIBS board: too many chequers")
+	}
+
+	var ms = &ret.Match
+	ms.GameState = 1
+	switch an[fieldTurn] {
+	case -1:
+		ms.Move = 1
+	case 1:
+		ms.Move = 0
+	case 0:
+		ms.GameState = 2
+	default:
+		return ret, fmt.Errorf("invalid FIBS turn: %v", an[fieldTurn])
+	}
+	ms.Turn = ms.Move
+
+	var dice = an[fieldPlayerDice : fieldPlayerDice+2]
+	if ms.Move == opp {
+		dice = an[fieldOpponentDice : fieldOpponentDice+2]
+	}
+	for i, n := range dice {
+		if n < 0 || n > 6 {
+			return ret, fmt.Errorf("invalid FIBS dice: %v", dice)
+		}
+		ms.Dice[i] = n
+	}
+	if (ms.Dice[0] == 0) != (ms.Dice[1] == 0) {
+		return ret, fmt.Errorf("invalid FIBS dice: %v", dice)
+	}
+
+	if an[fieldWasDoubled] != 0 {
+		/* the player to act answers the double */
+		ms.Doubled = true
+		ms.Turn = you
+		ms.Move = opp
+	}
+
+	var cube = &ms.Cube
+	if an[fieldMatchTo] != unlimited {
+		cube.MatchTo = an[fieldMatchTo]
+	}
+	cube.Score[you], cube.Score[opp] = an[fieldPlayerScore], an[fieldOpponentScore]
+	cube.Value = an[fieldCube]
+	if cube.Value < 1 || cube.Value&(cube.Value-1) != 0 {
+		return ret, fmt.Errorf("invalid FIBS cube: %v", cube.Value)
+	}
+	if cube.MatchTo > 0 && (cube.Score[0] >= cube.MatchTo || cube.Score[1] >= cube.MatchTo) {
+		return ret, fmt.Errorf("invalid FIBS score: %v-%v", cube.Score[you], cube.Score[opp])
+	}
+
+	var fMayDouble = [2]bool{}
+	fMayDouble[you] = an[fieldPlayerMayDouble] != 0
+	fMayDouble[opp] = an[fieldOpponentMayDouble] != 0
+	switch {
+	case cube.Value == 1:
+		cube.Owner = -1
+	case fMayDouble[opp] && !fMayDouble[you]:
+		cube.Owner = opp
+	default:
+		cube.Owner = you
+	}
+
+	/* neither may double before the Crawford game is played */
+	cube.Crawford = cube.MatchTo > 0 && an[fieldDidCrawford] == 0 && !fMayDouble[0] && !fMayDouble[1] &&
+		(cube.Score[0] == cube.MatchTo-1 || cube.Score[1] == cube.MatchTo-1)
+
+	return ret, nil
+}
+
+/* the side of the {x, o} board holding the chequers of a player */
+func boardSide(player int) int {
+	return 1 - player
+}
+
+/* a player's own point, counted from 0, of a point on the FIBS board */
+func (b Board) point(player int, i int) int {
+	if (player == b.Colour) == b.Down {
+		return i - 1
+	}
+	return 24 - i
+}
+
+// String writes the board: string.
+func (b Board) String() string {
+	var an [cFields]int
+	var you, opp = b.Colour, 1 - b.Colour
+	var ms = b.Match
+
+	an[fieldMatchTo] = ms.Cube.MatchTo
+	if an[fieldMatchTo] == 0 {
+		an[fieldMatchTo] = unlimited
+	}
+	an[fieldPlayerScore], an[fieldOpponentScore] = ms.Cube.Score[you], ms.Cube.Score[opp]
+
+	var cChequers [2]int
+	for player := 0; player < 2; player++ {
+		var sign = 1
+		if player == 1 {
+			sign = -1
+		}
+		for i := 1; i <= 24; i++ {
+			if n := b.Board[boardSide(player)][b.point(player, i)]; n > 0 {
+				an[fieldBoard+i] = sign * n
+				cChequers[player] += n
+			}
+		}
+		cChequers[player] += b.Board[boardSide(player)][24]
+	}
+
+	/* the bars are at either end of the FIBS board */
+	var home, bar = 25, 0
+	if b.Down {
+		home, bar = 0, 25
+	}
+	an[fieldBoard+bar] = colourSign(you) * b.Board[boardSide(you)][24]
+	an[fieldBoard+home] = colourSign(opp) * b.Board[boardSide(opp)][24]
+
+	var roller = ms.Move
+	if ms.GameState != 1 {
+		an[fieldTurn] = 0
+	} else {
+		an[fieldTurn] = colourSign(roller)
+	}
+	if roller == you {
+		an[fieldPlayerDice], an[fieldPlayerDice+1] = ms.Dice[0], ms.Dice[1]
+	} else {
+		an[fieldOpponentDice], an[fieldOpponentDice+1] = ms.Dice[0], ms.Dice[1]
+	}
+
+	an[fieldCube] = ms.Cube.Value
+	an[fieldPlayerMayDouble] = toInt(ms.Cube.CanDouble(you))
+	an[fieldOpponentMayDouble] = toInt(ms.Cube.CanDouble(opp))
+	if ms.Doubled {
+		/* only the player being doubled is told */
+		an[fieldWasDoubled] = toInt(ms.Turn == you)
+		an[fieldTurn] = colourSign(ms.Move)
+	}
+
+	an[fieldColour] = colourSign(you)
+	an[fieldDirection] = 1
+	if b.Down {
+		an[fieldDirection] = -1
+	}
+	an[fieldHome], an[fieldBar] = home, bar
+	an[fieldPlayerHome], an[fieldOpponentHome] = 15-cChequers[you], 15-cChequers[opp]
+	an[fieldPlayerBar], an[fieldOpponentBar] = b.Board[boardSide(you)][24], b.Board[boardSide(opp)][24]
+
+	if roller == you && ms.Dice[0] > 0 && !ms.Doubled {
+		var board = b.Board
+		if you == 1 {
+			board = gnubg.SwapSides(board)
+		}
+		if plays := gnubg.LegalPlays(board, ms.Dice); len(plays) > 0 {
+			an[fieldCanMove] = len(plays[0].Moves)
+		}
+	}
+
+	var cube = ms.Cube
+	an[fieldDidCrawford] = toInt(cube.MatchTo > 0 && !cube.Crawford &&
+		(cube.Score[0] == cube.MatchTo-1 || cube.Score[1] == cube.MatchTo-1))
+
+	var sb strings.Builder
+	sb.WriteString("board:" + b.Player + ":" + b.Opponent)
+	for i := fieldMatchTo; i < cFields; i++ {
+		sb.WriteString(":" + strconv.Itoa(an[i]))
+	}
+	return sb.String()
+}
+
+/* -1 for X, 1 for O */
+func colourSign(player int) int {
+	if player == 1 {
+		return -1
+	}
+	return 1
+}
+
+func toInt(f bool) int {
+	if f {
+		return 1
+	}
+	return 0
+}
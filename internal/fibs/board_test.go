@@ -0,0 +1,112 @@
+package fibs
+
+import (
+	"bgweb-api/internal/gnubg"
+	"reflect"
+	"testing"
+)
+
+/* the example of the FIBS client protocol: the player is O, moving down,
+ * with 6-2 to play at the start of a 3 point match */
+const fibsExample = "board:You:someplayer:3:0:0:0:-2:0:0:0:0:5:0:3:0:0:0:-5:5:0:0:0:-3:0:-5:0:0:0:0:2:0:1:6:2:0:0:1:1:1:0:1:-1:0:25:0:0:0:0:2:0:0:0"
+
+var startBoard = gnubg.TanBoard{
+	{5: 5, 7: 3, 12: 5, 23: 2},
+	{5: 5, 7: 3, 12: 5, 23: 2},
+}
+
+func TestParseBoard(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Board
+		wantErr bool
+	}{
+		{
+			name: "should parse the protocol example",
+			s:    fibsExample,
+			want: Board{
+				Player:   "You",
+				Opponent: "someplayer",
+				Colour:   0,
+				Down:     true,
+				Board:    startBoard,
+				Match: gnubg.MatchState{
+					Cube:      gnubg.Cube{Value: 1, Owner: -1, MatchTo: 3},
+					Move:      0,
+					Turn:      0,
+					Dice:      [2]int{6, 2},
+					GameState: 1,
+				},
+			},
+		},
+		{
+			name: "should parse X being doubled with a chequer on the bar",
+			s:    "board:bot:alice:9999:0:0:0:-2:0:0:0:0:5:0:3:0:0:0:-4:5:0:0:0:-3:0:-5:0:0:0:0:2:-1:-1:0:0:0:0:1:0:0:1:-1:1:25:0:0:0:1:0:0:0:0:0",
+			want: Board{
+				Player:   "bot",
+				Opponent: "alice",
+				Colour:   1,
+				Down:     false,
+				Board:    gnubg.TanBoard{{5: 5, 7: 3, 12: 4, 23: 2, 24: 1}, startBoard[1]},
+				Match: gnubg.MatchState{
+					Cube:      gnubg.Cube{Value: 1, Owner: -1},
+					Move:      0,
+					Turn:      1,
+					Doubled:   true,
+					GameState: 1,
+				},
+			},
+		},
+		{
+			name:    "should fail on missing fields",
+			s:       "board:You:someplayer:3:0:0",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on an invalid cube",
+			s:       "board:You:someplayer:3:0:0:0:-2:0:0:0:0:5:0:3:0:0:0:-5:5:0:0:0:-3:0:-5:0:0:0:0:2:0:1:6:2:0:0:3:1:1:0:1:-1:0:25:0:0:0:0:2:0:0:0",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on too many chequers",
+			s:       "board:You:someplayer:3:0:0:0:-2:0:0:0:0:9:0:3:0:0:0:-5:5:0:0:0:-3:0:-5:0:0:0:0:2:0:1:6:2:0:0:1:1:1:0:1:-1:0:25:0:0:0:0:2:0:0:0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBoard(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBoard() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBoard() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoard_String(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{"should round-trip the protocol example", fibsExample},
+		{"should round-trip X on roll in a money game", "board:bot:alice:9999:0:0:0:-2:0:0:0:0:5:0:3:0:0:0:-5:5:0:0:0:-3:0:-5:0:0:0:0:2:0:-1:3:1:0:0:1:1:1:0:-1:1:25:0:0:0:0:0:2:0:0:0"},
+		{"should round-trip a Crawford game", "board:You:someplayer:5:4:2:0:-2:0:0:0:0:5:0:3:0:0:0:-5:5:0:0:0:-3:0:-5:0:0:0:0:2:0:1:3:1:0:0:1:0:0:0:1:-1:0:25:0:0:0:0:2:0:0:0"},
+		{"should round-trip an owned cube after the Crawford game", "board:You:someplayer:5:4:2:0:-2:0:0:0:0:5:0:3:0:0:0:-5:5:0:0:0:-3:0:-5:0:0:0:0:2:0:-1:0:0:5:2:2:0:1:0:1:-1:0:25:0:0:0:0:0:0:1:0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseBoard(tt.s)
+			if err != nil {
+				t.Fatalf("ParseBoard() error = %v", err)
+			}
+			if got := b.String(); got != tt.s {
+				t.Errorf("Board.String() = %v, want %v", got, tt.s)
+			}
+		})
+	}
+}
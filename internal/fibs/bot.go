@@ -0,0 +1,423 @@
+package fibs
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BotConfig tells a bot where to play and how strongly.
+type BotConfig struct {
+	// host:port of the server, e.g. fibs.com:4321
+	Addr           string
+	User, Password string
+	// client name sent when logging in, "bgweb-api" when empty
+	Client string
+
+	// strength of the bot, mostly its plies and noise
+	Settings gnubg.Settings
+
+	// wait before reconnecting when the connection fails or is lost; the
+	// bot stops with the error instead when 0
+	Reconnect time.Duration
+
+	// where the bot reports its matches and errors, nowhere when nil
+	Log *log.Logger
+}
+
+// ErrLoginRefused is the error of a bot whose user or password the server
+// refuses. The bot doesn't reconnect after it.
+var ErrLoginRefused = errors.New("login refused")
+
+/* the CLIP version spoken */
+const clipVersion = 1008
+
+const loginPrompt = "login: "
+
+// RunBot logs a bot in to a FIBS server and plays whoever invites it,
+// until ctx is done. Each match is played in full: the bot rolls,
+// doubles, takes, moves and resigns, and answers resignations, deciding
+// with the engine.
+func RunBot(ctx context.Context, cfg BotConfig) error {
+	for {
+		err := runSession(ctx, cfg)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if cfg.Reconnect == 0 || errors.Is(err, ErrLoginRefused) {
+			return err
+		}
+
+		logf(cfg.Log, "%v; reconnecting in %v", err, cfg.Reconnect)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cfg.Reconnect):
+		}
+	}
+}
+
+func runSession(ctx context.Context, cfg BotConfig) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", cfg.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	/* a blocked read only ends when the connection is closed */
+	var done = make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	var b = bot{cfg: cfg, w: conn}
+	var r = bufio.NewReader(conn)
+	for {
+		line, err := readLine(r)
+		if err == io.EOF {
+			return fmt.Errorf("connection to %v closed", cfg.Addr)
+		}
+		if err != nil {
+			return err
+		}
+		if err := b.handle(line); err != nil {
+			return err
+		}
+	}
+}
+
+/* a line without its end; the login prompt comes without one */
+func readLine(r *bufio.Reader) (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return sb.String(), err
+		}
+		if c == '\n' {
+			return strings.TrimRight(sb.String(), "\r"), nil
+		}
+		sb.WriteByte(c)
+		if sb.String() == loginPrompt {
+			return loginPrompt, nil
+		}
+	}
+}
+
+func logf(l *log.Logger, format string, a ...interface{}) {
+	if l != nil {
+		l.Printf(format, a...)
+	}
+}
+
+/* what the bot waits for a board to decide */
+type botAction int
+
+const (
+	actNone botAction = iota
+	actMove
+	actRollOrDouble
+	actDouble
+	actResign
+)
+
+/* the toggles a bot needs set, by their field in the CLIP own info line:
+ * 2 name allowpip autoboard autodouble automove away bell crawford double
+ * experience greedy moreboards moves notify rating ratings ready report
+ * silent timezone */
+var botToggles = []struct {
+	name  string
+	field int
+}{
+	{"ready", 17},
+	{"double", 9},
+}
+
+type bot struct {
+	cfg BotConfig
+	w   io.Writer
+
+	fLoginSent bool
+	pending    botAction
+	// points the opponent resigns for
+	nResign int
+	// whether the opponent refused the bot's resignation this game
+	fResignRefused bool
+}
+
+func (b *bot) send(format string, a ...interface{}) error {
+	_, err := fmt.Fprintf(b.w, format+"\r\n", a...)
+	return err
+}
+
+/* asks for the board to act on; whatever board comes next is the current
+ * one, however the server orders its boards and prompts */
+func (b *bot) await(act botAction) error {
+	b.pending = act
+	return b.send("board")
+}
+
+func (b *bot) handle(line string) error {
+	switch {
+	case line == loginPrompt:
+		if b.fLoginSent {
+			return ErrLoginRefused
+		}
+		b.fLoginSent = true
+		var client = b.cfg.Client
+		if client == "" {
+			client = "bgweb-api"
+		}
+		return b.send("login %v %v %v %v", client, clipVersion, b.cfg.User, b.cfg.Password)
+
+	case strings.HasPrefix(line, "1 "):
+		logf(b.cfg.Log, "logged in to %v as %v", b.cfg.Addr, b.cfg.User)
+
+	case strings.HasPrefix(line, "2 "):
+		return b.setup(strings.Fields(line))
+
+	case strings.HasPrefix(line, "board:"):
+		board, err := ParseBoard(line)
+		if err != nil {
+			return err
+		}
+		return b.act(board)
+
+	case strings.Contains(line, " wants to play a") || strings.Contains(line, " wants to resume a saved match"):
+		var name = strings.Fields(line)[0]
+		logf(b.cfg.Log, "joining %v", name)
+		return b.send("join %v", name)
+
+	case strings.HasPrefix(line, "Starting a new game with"):
+		b.fResignRefused = false
+
+	case strings.HasPrefix(line, "Type 'join' if you want to play the next game"):
+		return b.send("join")
+
+	case strings.HasPrefix(line, "Please move"):
+		return b.await(actMove)
+
+	case strings.Contains(line, "roll or double"):
+		return b.await(actRollOrDouble)
+
+	case strings.Contains(line, " doubles. Type 'accept' or 'reject'"):
+		return b.await(actDouble)
+
+	case strings.Contains(line, " wants to resign."):
+		_, after, _ := strings.Cut(line, "You will win ")
+		n, err := strconv.Atoi(strings.Fields(after + " ")[0])
+		if err != nil {
+			return fmt.Errorf("unreadable resignation: %q", line)
+		}
+		b.nResign = n
+		return b.await(actResign)
+
+	case strings.HasSuffix(line, " rejects. The game continues."):
+		/* the bot resigned before rolling; now it rolls */
+		b.fResignRefused = true
+		return b.await(actRollOrDouble)
+
+	case strings.Contains(line, " point match ") && strings.Contains(line, " win"):
+		logf(b.cfg.Log, "%v", line)
+	}
+
+	return nil
+}
+
+/* asks for boards in FIBS's board: strings, and for the toggles the bot
+ * plays with */
+func (b *bot) setup(fields []string) error {
+	if err := b.send("set boardstyle 3"); err != nil {
+		return err
+	}
+	for _, t := range botToggles {
+		if t.field < len(fields) && fields[t.field] == "1" {
+			continue
+		}
+		if err := b.send("toggle %v", t.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *bot) act(board Board) error {
+	var act = b.pending
+	b.pending = actNone
+	var s = b.cfg.Settings
+
+	switch act {
+	case actMove:
+		moves, err := bestPlay(board, s)
+		if err != nil || len(moves) == 0 {
+			return err
+		}
+		return b.send("move %v", board.formatMoves(board.Colour, moves))
+
+	case actRollOrDouble:
+		if !b.fResignRefused {
+			n, err := resignation(board, s)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				return b.send("resign %v", resignLevels[n-1])
+			}
+		}
+		double, err := doubles(board, s)
+		if err != nil {
+			return err
+		}
+		if double {
+			return b.send("double")
+		}
+		return b.send("roll")
+
+	case actDouble:
+		take, err := takes(board, s)
+		if err != nil {
+			return err
+		}
+		return b.send(acceptOrReject(take))
+
+	case actResign:
+		accept, err := acceptsResignation(board, b.nResign, s)
+		if err != nil {
+			return err
+		}
+		return b.send(acceptOrReject(accept))
+	}
+
+	return nil
+}
+
+func acceptOrReject(f bool) string {
+	if f {
+		return "accept"
+	}
+	return "reject"
+}
+
+/* resign arguments for a single game, a gammon and a backgammon */
+var resignLevels = [3]string{"n", "g", "b"}
+
+/* chances below this are taken as none */
+const rImpossible = 1e-5
+
+/* whether the player, on roll, can't win at all and only loses a single
+ * game, a gammon or a backgammon: 1, 2 or 3; 0 to play on */
+func resignation(b Board, s gnubg.Settings) (int, error) {
+	var you = b.Colour
+	ar, err := gnubg.EvaluatePosition(moverBoard(b.Board, you), you, b.Match.Cube, s)
+	if err != nil || ar[0] > rImpossible {
+		return 0, err
+	}
+
+	switch {
+	case ar[4] > 1-rImpossible:
+		return 3, nil
+	case ar[4] > rImpossible:
+		return 0, nil
+	case ar[3] > 1-rImpossible:
+		return 2, nil
+	case ar[3] > rImpossible:
+		return 0, nil
+	}
+	return 1, nil
+}
+
+/* whether the player takes points for the game rather than playing on for
+ * the cubeful equity they expect at the score */
+func acceptsResignation(b Board, points int, s gnubg.Settings) (bool, error) {
+	var mover = b.Match.Move
+	cd, err := gnubg.EvaluateCube(moverBoard(b.Board, mover), mover, b.Match.Cube, s)
+	if err != nil {
+		return false, err
+	}
+	rResign, err := gnubg.ResignationEquity(b.Colour, b.Match.Cube, points)
+	if err != nil {
+		return false, err
+	}
+
+	/* the mover doubles when the opponent's best answer beats not doubling */
+	var rPlay = cd.NoDouble
+	if rDouble := cd.DoubleTake; cd.Available {
+		if cd.DoublePass < rDouble {
+			rDouble = cd.DoublePass
+		}
+		if rDouble > rPlay {
+			rPlay = rDouble
+		}
+	}
+	if mover != b.Colour {
+		rPlay = -rPlay
+	}
+
+	return rResign >= rPlay, nil
+}
+
+/* the FIBS point of a player's own point counted from 0, the inverse of
+ * point */
+func (b Board) fibsPoint(player int, n int) int {
+	if (player == b.Colour) == b.Down {
+		return n + 1
+	}
+	return 24 - n
+}
+
+/* chequer moves as FIBS's move command takes them, e.g. "13-11 24-18",
+ * with the points numbered as on the board */
+func (b Board) formatMoves(player int, moves [][2]int) string {
+	var asz []string
+	for _, m := range moves {
+		var from, to = strconv.Itoa(b.fibsPoint(player, m[0])), strconv.Itoa(b.fibsPoint(player, m[1]))
+		if m[0] == 24 {
+			from = "bar"
+		}
+		if m[1] == -1 {
+			to = "off"
+		}
+		asz = append(asz, from+"-"+to)
+	}
+	return strings.Join(asz, " ")
+}
+
+/* and back */
+func (b Board) parseMoves(player int, asz []string) ([][2]int, error) {
+	var moves [][2]int
+	for _, sz := range asz {
+		from, to, ok := strings.Cut(sz, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid move: %q", sz)
+		}
+		var m [2]int
+		for i, p := range []string{from, to} {
+			switch n, err := strconv.Atoi(p); {
+			case p == "bar" && i == 0:
+				m[i] = 24
+			case p == "off" && i == 1:
+				m[i] = -1
+			case err == nil && n >= 1 && n <= 24:
+				m[i] = b.point(player, n)
+			default:
+				return nil, fmt.Errorf("invalid move: %q", sz)
+			}
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
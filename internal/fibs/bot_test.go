@@ -0,0 +1,194 @@
+package fibs
+
+import (
+	"bgweb-api/internal/gnubg"
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/* X on roll, too far behind to win: it has 5 chequers left and O one */
+var lostBoard = gnubg.TanBoard{{5: 5}, {0: 1}}
+
+/* and with none borne off, out of their home board or in O's */
+var gammonBoard = gnubg.TanBoard{{12: 15}, {0: 1}}
+var backgammonBoard = gnubg.TanBoard{{20: 15}, {0: 1}}
+
+func TestResignation(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name  string
+		board gnubg.TanBoard
+		want  int
+	}{
+		{"should play on at the start", startBoard, 0},
+		{"should resign a single game", lostBoard, 1},
+		{"should resign a gammon", gammonBoard, 2},
+		{"should resign a backgammon", backgammonBoard, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b = Board{Colour: 1, Board: tt.board}
+			b.Match = gnubg.MatchState{Cube: gnubg.Cube{Value: 1, Owner: -1}, Move: 1, Turn: 1, GameState: 1}
+			got, err := resignation(b, gnubg.Settings{})
+			if err != nil {
+				t.Fatalf("resignation() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resignation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcceptsResignation(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name    string
+		board   gnubg.TanBoard
+		cube    int
+		matchTo int
+		score   [2]int
+		points  int
+		want    bool
+	}{
+		{"should accept a gammon for a gammon", gammonBoard, 1, 0, [2]int{}, 2, true},
+		{"should refuse a single game for a gammon", gammonBoard, 1, 0, [2]int{}, 1, false},
+		{"should count the cube", gammonBoard, 2, 0, [2]int{}, 2, false},
+		{"should accept a single game at the start", startBoard, 1, 0, [2]int{}, 1, true},
+		{"should accept a single game winning the match", gammonBoard, 1, 5, [2]int{4, 0}, 1, true},
+		{"should refuse a single game when a gammon wins the match", gammonBoard, 1, 5, [2]int{3, 0}, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* O, to whom X resigns before rolling */
+			var b = Board{Colour: 0, Board: tt.board}
+			var cube = gnubg.Cube{Value: tt.cube, Owner: -1, MatchTo: tt.matchTo, Score: tt.score}
+			b.Match = gnubg.MatchState{Cube: cube, Move: 1, Turn: 1, GameState: 1}
+			got, err := acceptsResignation(b, tt.points, gnubg.Settings{})
+			if err != nil {
+				t.Fatalf("acceptsResignation() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("acceptsResignation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoard_formatMoves(t *testing.T) {
+	tests := []struct {
+		name   string
+		b      Board
+		player int
+		moves  [][2]int
+		want   string
+	}{
+		{"should number down the board", Board{Colour: 0, Down: true}, 0, [][2]int{{7, 4}, {5, 4}}, "8-5 6-5"},
+		{"should number up the board", Board{Colour: 1}, 1, [][2]int{{7, 4}, {5, 4}}, "17-20 19-20"},
+		{"should number the opponent", Board{Colour: 1}, 0, [][2]int{{7, 4}}, "8-5"},
+		{"should enter and bear off", Board{Colour: 0, Down: true}, 0, [][2]int{{24, 21}, {3, -1}}, "bar-22 4-off"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.b.formatMoves(tt.player, tt.moves)
+			if got != tt.want {
+				t.Errorf("Board.formatMoves() = %q, want %q", got, tt.want)
+			}
+			back, err := tt.b.parseMoves(tt.player, strings.Fields(got))
+			if err != nil || !reflect.DeepEqual(back, tt.moves) {
+				t.Errorf("Board.parseMoves() = %v, %v, want %v", back, err, tt.moves)
+			}
+		})
+	}
+}
+
+/* a listener hanging up on its first connection */
+type droppingListener struct {
+	net.Listener
+	once sync.Once
+}
+
+func (l *droppingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	var fDrop bool
+	l.once.Do(func() { fDrop = true })
+	if err == nil && fDrop {
+		conn.Close()
+		return l.Listener.Accept()
+	}
+	return conn, err
+}
+
+func startFakeServer(t *testing.T, s *FakeServer, drop bool) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	var ls net.Listener = l
+	if drop {
+		ls = &droppingListener{Listener: l}
+	}
+	go s.Serve(ls)
+
+	return l.Addr().String()
+}
+
+func TestRunBot(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name    string
+		matchTo int
+		drop    bool
+	}{
+		{"should play a match", 3, false},
+		{"should reconnect", 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results = make(chan MatchResult, 1)
+			var addr = startFakeServer(t, &FakeServer{Opponent: "gnubg", MatchTo: tt.matchTo, Seed: 7, Settings: gnubg.Settings{Cubeful: true}, Results: results}, tt.drop)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var done = make(chan error, 1)
+			go func() {
+				done <- RunBot(ctx, BotConfig{Addr: addr, User: "bot", Settings: gnubg.Settings{Cubeful: true}, Reconnect: 10 * time.Millisecond})
+			}()
+
+			select {
+			case r := <-results:
+				if r.User != "bot" || (r.Score[0] < tt.matchTo) == (r.Score[1] < tt.matchTo) {
+					t.Errorf("RunBot() played %+v, want a %v point match", r, tt.matchTo)
+				}
+			case err := <-done:
+				t.Fatalf("RunBot() error = %v before the match ended", err)
+			case <-time.After(time.Minute):
+				t.Fatalf("RunBot() didn't finish the match")
+			}
+
+			cancel()
+			if err := <-done; !errors.Is(err, context.Canceled) {
+				t.Errorf("RunBot() error = %v, want %v", err, context.Canceled)
+			}
+		})
+	}
+}
+
+func TestRunBot_loginRefused(t *testing.T) {
+	var addr = startFakeServer(t, &FakeServer{Opponent: "gnubg", Password: "secret"}, false)
+
+	err := RunBot(context.Background(), BotConfig{Addr: addr, User: "bot", Password: "guess", Reconnect: time.Millisecond})
+	if !errors.Is(err, ErrLoginRefused) {
+		t.Errorf("RunBot() error = %v, want %v", err, ErrLoginRefused)
+	}
+}
@@ -0,0 +1,133 @@
+package fibs
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/match"
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Reply is what an external player answers to a board: string in gnubg's
+// external player protocol: "double" or "roll" before rolling, "take" or
+// "drop" when doubled, and otherwise the play of the dice in gnubg's
+// notation, e.g. "8/5 6/5", empty when they can't be played.
+func Reply(b Board, s gnubg.Settings) (string, error) {
+	var ms = b.Match
+
+	if ms.GameState != 1 {
+		return "", fmt.Errorf("the game is over")
+	}
+
+	if ms.Doubled {
+		take, err := takes(b, s)
+		if err != nil {
+			return "", err
+		}
+		if take {
+			return "take", nil
+		}
+		return "drop", nil
+	}
+
+	if ms.Move != b.Colour {
+		return "", fmt.Errorf("%v is not on roll", b.Player)
+	}
+
+	if ms.Dice[0] == 0 {
+		double, err := doubles(b, s)
+		if err != nil {
+			return "", err
+		}
+		if double {
+			return "double", nil
+		}
+		return "roll", nil
+	}
+
+	moves, err := bestPlay(b, s)
+	if err != nil {
+		return "", err
+	}
+	return match.FormatMoves(moves), nil
+}
+
+/* whether the player, on roll before rolling, doubles */
+func doubles(b Board, s gnubg.Settings) (bool, error) {
+	var you = b.Colour
+	if !b.Match.Cube.CanDouble(you) {
+		return false, nil
+	}
+	d, err := gnubg.EvaluateCube(moverBoard(b.Board, you), you, b.Match.Cube, s)
+	return d.Double(), err
+}
+
+/* whether the player takes the double they are offered */
+func takes(b Board, s gnubg.Settings) (bool, error) {
+	var you, opp = b.Colour, 1 - b.Colour
+	if b.Match.Turn != you {
+		return false, fmt.Errorf("%v is not the one doubled", b.Player)
+	}
+	/* the doubler's decision, from their side of the board */
+	d, err := gnubg.EvaluateCube(moverBoard(b.Board, opp), opp, b.Match.Cube, s)
+	return d.Take(), err
+}
+
+/* the chequer moves of the player's best play of the dice, none when they
+ * can't be played */
+func bestPlay(b Board, s gnubg.Settings) ([][2]int, error) {
+	var you = b.Colour
+	var board = moverBoard(b.Board, you)
+
+	after, err := gnubg.BestPlay(board, b.Match.Dice, you, b.Match.Cube, s)
+	if err != nil {
+		return nil, err
+	}
+	for _, play := range gnubg.LegalPlays(board, b.Match.Dice) {
+		if play.Board == after {
+			return play.Moves, nil
+		}
+	}
+
+	return nil, nil
+}
+
+/* the {x, o} board seen from a player, as the engine takes it */
+func moverBoard(board gnubg.TanBoard, player int) gnubg.TanBoard {
+	if player == 1 {
+		return gnubg.SwapSides(board)
+	}
+	return board
+}
+
+// ServeExternal plays as gnubg's external player over rw: it reads a
+// board: string per line and writes back the Reply to each, until rw is
+// closed. Blank lines are skipped; a line that isn't a board, or one the
+// player can't act on, ends the session with an error.
+func ServeExternal(rw io.ReadWriter, s gnubg.Settings) error {
+	var scanner = bufio.NewScanner(rw)
+	for scanner.Scan() {
+		/* gnubg may send the string's terminating NUL too */
+		var line = strings.TrimSpace(strings.Trim(scanner.Text(), "\x00"))
+		if line == "" {
+			continue
+		}
+
+		b, err := ParseBoard(line)
+		if err != nil {
+			return err
+		}
+
+		reply, err := Reply(b, s)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(rw, reply+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,113 @@
+package fibs
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bufio"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var once sync.Once
+
+func setup() {
+	if err := gnubg.Init(os.DirFS("../../cmd/bgweb-api/data")); err != nil {
+		panic(err)
+	}
+}
+
+/* X, the player, in a race far ahead of O */
+var raceBoard = gnubg.TanBoard{
+	{0: 3, 1: 3, 2: 3, 3: 3, 4: 3},
+	{2: 3, 3: 3, 4: 3, 5: 3, 6: 3},
+}
+
+func TestReply(t *testing.T) {
+	once.Do(setup)
+
+	var race = func(doubled bool) Board {
+		var b = Board{Player: "bot", Opponent: "alice", Colour: 1, Board: raceBoard}
+		b.Match = gnubg.MatchState{Cube: gnubg.Cube{Value: 1, Owner: -1}, Move: 1, Turn: 1, GameState: 1}
+		if doubled {
+			/* alice doubles, seeing the board from the other side */
+			b.Board = gnubg.SwapSides(raceBoard)
+			b.Match.Move, b.Match.Doubled = 0, true
+		}
+		return b
+	}
+	var opening = func(dice [2]int) Board {
+		var b = Board{Player: "bot", Opponent: "alice", Colour: 0, Down: true, Board: startBoard}
+		b.Match = gnubg.MatchState{Cube: gnubg.Cube{Value: 1, Owner: -1, MatchTo: 3}, Dice: dice, GameState: 1}
+		return b
+	}
+	var takeable = opening([2]int{})
+	takeable.Match.Doubled, takeable.Match.Move = true, 1
+	var theirs = opening([2]int{})
+	theirs.Match.Move, theirs.Match.Turn = 1, 1
+	var crawford = race(false)
+	crawford.Match.Cube = gnubg.Cube{Value: 1, Owner: -1, MatchTo: 5, Score: [2]int{4, 0}, Crawford: true}
+
+	tests := []struct {
+		name    string
+		b       Board
+		want    string
+		wantErr bool
+	}{
+		{"should play the dice", opening([2]int{3, 1}), "8/5 6/5", false},
+		{"should roll in the opening", opening([2]int{}), "roll", false},
+		{"should double far ahead", race(false), "double", false},
+		{"should roll in the Crawford game", crawford, "roll", false},
+		{"should take in the opening", takeable, "take", false},
+		{"should drop far behind", race(true), "drop", false},
+		{"should refuse the opponent's turn", theirs, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Reply(tt.b, gnubg.Settings{Cubeful: true})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Reply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeExternal(t *testing.T) {
+	once.Do(setup)
+
+	server, client := net.Pipe()
+	var done = make(chan error, 1)
+	go func() {
+		done <- ServeExternal(server, gnubg.Settings{Cubeful: true})
+		server.Close()
+	}()
+
+	var r = bufio.NewReader(client)
+	for _, tt := range []struct{ send, want string }{
+		{fibsExample + "\n", "24/18 13/11"},
+		{"\n" + fibsExample + "\x00\n", "24/18 13/11"},
+	} {
+		if _, err := client.Write([]byte(tt.send)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		got, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("ReadString() error = %v", err)
+		}
+		if got = strings.TrimSpace(got); got != tt.want {
+			t.Errorf("ServeExternal() replied %q, want %q", got, tt.want)
+		}
+	}
+
+	if _, err := client.Write([]byte("board:nonsense\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := <-done; err == nil {
+		t.Errorf("ServeExternal() error = nil, want one for an invalid board")
+	}
+	client.Close()
+}
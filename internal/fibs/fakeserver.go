@@ -0,0 +1,519 @@
+package fibs
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/match"
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+)
+
+// FakeServer is a tiny stand-in for a FIBS server to try bots against. It
+// logs in anyone, has them join a match against an engine player of its
+// own and plays it out with the CLIP messages and board: strings of FIBS.
+// Each connection plays one match.
+type FakeServer struct {
+	// name and strength of the server's player
+	Opponent string
+	Settings gnubg.Settings
+	// length of the match, 1 when 0
+	MatchTo int
+	// password users log in with, any when empty
+	Password string
+	// seeds the dice of each connection
+	Seed int64
+
+	// receives the result of every match played to the end, if not nil
+	Results chan<- MatchResult
+}
+
+// MatchResult is a match a FakeServer saw to the end.
+type MatchResult struct {
+	User string
+	// the user's score and their opponent's
+	Score [2]int
+}
+
+// Serve plays the connections of l until it is closed.
+func (s *FakeServer) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			var fs = fakeSession{s: s, conn: conn, r: bufio.NewReader(conn), rnd: rand.New(rand.NewSource(s.Seed))}
+			fs.serve()
+		}()
+	}
+}
+
+/* the user plays x, moving up the FIBS board, and the server's player o */
+const (
+	fakeOpp  = 0
+	fakeUser = 1
+)
+
+/* the game ended */
+var errGameOver = errors.New("game over")
+
+type fakeSession struct {
+	s    *FakeServer
+	conn net.Conn
+	r    *bufio.Reader
+	rnd  *rand.Rand
+
+	user                      string
+	nBoardStyle               int
+	fReady, fDouble, fInMatch bool
+
+	board gnubg.TanBoard
+	ms    gnubg.MatchState
+	// set when a game ends
+	nWinner, nPoints int
+}
+
+func (fs *fakeSession) send(format string, a ...interface{}) error {
+	_, err := fmt.Fprintf(fs.conn, format+"\r\n", a...)
+	return err
+}
+
+func (fs *fakeSession) serve() {
+	if err := fs.login(); err != nil {
+		return
+	}
+
+	/* the bot readies itself */
+	for !fs.fReady || fs.nBoardStyle != 3 {
+		fields, err := fs.command()
+		if err != nil {
+			return
+		}
+		fs.answer(fields)
+	}
+
+	var matchTo = fs.s.MatchTo
+	if matchTo == 0 {
+		matchTo = 1
+	}
+	fs.send("%v wants to play a %v point match with you.", fs.s.Opponent, matchTo)
+	if _, _, err := fs.expect("join"); err != nil {
+		return
+	}
+	fs.send("** You are now playing a %v point match with %v", matchTo, fs.s.Opponent)
+	fs.fInMatch = true
+
+	if err := fs.playMatch(matchTo); err != nil {
+		return
+	}
+
+	/* idle until the bot goes */
+	for {
+		fields, err := fs.command()
+		if err != nil {
+			return
+		}
+		fs.answer(fields)
+	}
+}
+
+func (fs *fakeSession) login() error {
+	for {
+		if _, err := fs.conn.Write([]byte(loginPrompt)); err != nil {
+			return err
+		}
+		line, err := fs.r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		var fields = strings.Fields(line)
+		if len(fields) >= 4 && fields[0] == "login" && (fs.s.Password == "" || len(fields) == 5 && fields[4] == fs.s.Password) {
+			fs.user = fields[3]
+			break
+		}
+		fs.send("")
+	}
+
+	fs.send("1 %v 0 localhost", fs.user)
+	/* not ready, and not asked to double */
+	fs.send("2 %v 1 1 0 0 0 0 1 0 0 0 0 0 1 1500.00 0 0 0 0 UTC", fs.user)
+	fs.send("3")
+	fs.send("Welcome to the fake FIBS.")
+	fs.send("4")
+	return fs.send("6")
+}
+
+/* the next command of the bot */
+func (fs *fakeSession) command() ([]string, error) {
+	for {
+		line, err := fs.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if fields := strings.Fields(line); len(fields) > 0 {
+			return fields, nil
+		}
+	}
+}
+
+/* the next command of the bot among those expected, answering the others
+ * it may send any time */
+func (fs *fakeSession) expect(cmds ...string) (string, []string, error) {
+	for {
+		fields, err := fs.command()
+		if err != nil {
+			return "", nil, err
+		}
+		for _, cmd := range cmds {
+			if fields[0] == cmd {
+				return cmd, fields[1:], nil
+			}
+		}
+		fs.answer(fields)
+	}
+}
+
+func (fs *fakeSession) answer(fields []string) {
+	switch strings.Join(fields, " ") {
+	case "board":
+		if fs.fInMatch {
+			fs.send("%v", fs.view(fakeUser))
+		}
+	case "set boardstyle 3":
+		fs.nBoardStyle = 3
+		fs.send("Value of 'boardstyle' set to 3.")
+	case "toggle ready":
+		fs.fReady = !fs.fReady
+		fs.send("** You're now %v.", map[bool]string{true: "ready to invite or join someone", false: "refusing to play with someone"}[fs.fReady])
+	case "toggle double":
+		fs.fDouble = !fs.fDouble
+		fs.send("** You will %vbe asked if you want to double.", map[bool]string{true: "", false: "not "}[fs.fDouble])
+	default:
+		fs.send("** Unknown or unexpected command: %v", strings.Join(fields, " "))
+	}
+}
+
+/* the board from a player's side */
+func (fs *fakeSession) view(player int) Board {
+	if player == fakeUser {
+		return Board{Player: "You", Opponent: fs.s.Opponent, Colour: fakeUser, Board: fs.board, Match: fs.ms}
+	}
+	return Board{Player: fs.s.Opponent, Opponent: fs.user, Colour: fakeOpp, Down: true, Board: fs.board, Match: fs.ms}
+}
+
+func (fs *fakeSession) playMatch(matchTo int) error {
+	var score [2]int
+	var fCrawfordPlayed bool
+
+	for score[0] < matchTo && score[1] < matchTo {
+		var fCrawford = !fCrawfordPlayed && (score[0] == matchTo-1 || score[1] == matchTo-1)
+		fCrawfordPlayed = fCrawfordPlayed || fCrawford
+
+		fs.board = match.StartBoard
+		fs.ms = gnubg.MatchState{
+			Cube:      gnubg.Cube{Value: 1, Owner: -1, MatchTo: matchTo, Score: score, Crawford: fCrawford},
+			GameState: 1,
+		}
+		fs.send("Starting a new game with %v.", fs.s.Opponent)
+
+		if err := fs.playGame(); err != errGameOver {
+			return err
+		}
+		score[fs.nWinner] += fs.nPoints
+
+		if fs.nWinner == fakeUser {
+			fs.send("You win the game and get %v %v. Congratulations!", fs.nPoints, pointsWord(fs.nPoints))
+		} else {
+			fs.send("%v wins the game and gets %v %v. Sorry.", fs.s.Opponent, fs.nPoints, pointsWord(fs.nPoints))
+		}
+
+		if score[0] >= matchTo || score[1] >= matchTo {
+			break
+		}
+		fs.send("score in %v point match: %v-%v %v-%v", matchTo, fs.user, score[fakeUser], fs.s.Opponent, score[fakeOpp])
+		fs.send("Type 'join' if you want to play the next game, type 'leave' if you don't.")
+		if _, _, err := fs.expect("join"); err != nil {
+			return err
+		}
+	}
+
+	if score[fakeUser] >= matchTo {
+		fs.send("You win the %v point match %v-%v .", matchTo, score[fakeUser], score[fakeOpp])
+	} else {
+		fs.send("%v wins the %v point match %v-%v .", fs.s.Opponent, matchTo, score[fakeOpp], score[fakeUser])
+	}
+	fs.fInMatch = false
+
+	if fs.s.Results != nil {
+		fs.s.Results <- MatchResult{User: fs.user, Score: [2]int{score[fakeUser], score[fakeOpp]}}
+	}
+	return nil
+}
+
+func pointsWord(n int) string {
+	if n == 1 {
+		return "point"
+	}
+	return "points"
+}
+
+func (fs *fakeSession) roll() [2]int {
+	return [2]int{fs.rnd.Intn(6) + 1, fs.rnd.Intn(6) + 1}
+}
+
+/* plays a game to its end, returning errGameOver with the winner and
+ * points set */
+func (fs *fakeSession) playGame() error {
+	var dice = fs.roll()
+	for dice[0] == dice[1] {
+		dice = fs.roll()
+	}
+	fs.send("You rolled %v, %v rolled %v", dice[fakeUser], fs.s.Opponent, dice[fakeOpp])
+	var mover = fakeOpp
+	if dice[fakeUser] > dice[fakeOpp] {
+		mover = fakeUser
+	}
+
+	var fOppResignRefused bool
+	for turn := 0; ; turn++ {
+		fs.ms.Move, fs.ms.Turn = mover, mover
+		fs.ms.Dice = [2]int{}
+
+		if turn > 0 {
+			var err error
+			if mover == fakeUser {
+				err = fs.userCube()
+			} else {
+				err = fs.oppCube(&fOppResignRefused)
+			}
+			if err != nil {
+				return err
+			}
+			dice = fs.roll()
+		}
+
+		fs.ms.Dice = dice
+		var err error
+		if mover == fakeUser {
+			err = fs.userMove()
+		} else {
+			err = fs.oppMove()
+		}
+		if err != nil {
+			return err
+		}
+
+		mover = 1 - mover
+	}
+}
+
+func (fs *fakeSession) userCube() error {
+	if !fs.fDouble || !fs.ms.Cube.CanDouble(fakeUser) {
+		return nil
+	}
+
+	fs.send("%v", fs.view(fakeUser))
+	fs.send("It's your turn. Please roll or double")
+	for {
+		cmd, args, err := fs.expect("roll", "double", "resign")
+		if err != nil {
+			return err
+		}
+
+		switch cmd {
+		case "roll":
+			return nil
+
+		case "double":
+			fs.send("You double. Please wait for %v to accept or reject.", fs.s.Opponent)
+			fs.ms.Doubled, fs.ms.Turn = true, fakeOpp
+			take, err := takes(fs.view(fakeOpp), fs.s.Settings)
+			fs.ms.Doubled, fs.ms.Turn = false, fakeUser
+			if err != nil {
+				return err
+			}
+			if !take {
+				fs.send("%v refuses the double.", fs.s.Opponent)
+				return fs.gameOver(fakeUser, fs.ms.Cube.Value)
+			}
+			fs.ms.Cube.Value *= 2
+			fs.ms.Cube.Owner = fakeOpp
+			fs.send("%v accepts the double. The cube shows %v.", fs.s.Opponent, fs.ms.Cube.Value)
+			return nil
+
+		case "resign":
+			var n = resignValue(args)
+			if n == 0 {
+				fs.send("** Resign n, g or b.")
+				continue
+			}
+			n *= fs.ms.Cube.Value
+			accept, err := acceptsResignation(fs.view(fakeOpp), n, fs.s.Settings)
+			if err != nil {
+				return err
+			}
+			if accept {
+				fs.send("%v accepts and wins %v %v.", fs.s.Opponent, n, pointsWord(n))
+				return fs.gameOver(fakeOpp, n)
+			}
+			fs.send("%v rejects. The game continues.", fs.s.Opponent)
+		}
+	}
+}
+
+func resignValue(args []string) int {
+	if len(args) == 1 {
+		for i, sz := range resignLevels {
+			if args[0] == sz {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+func (fs *fakeSession) oppCube(fResignRefused *bool) error {
+	var view = fs.view(fakeOpp)
+
+	if !*fResignRefused {
+		n, err := resignation(view, fs.s.Settings)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			n *= fs.ms.Cube.Value
+			fs.send("%v wants to resign. You will win %v %v. Type 'accept' or 'reject'.", fs.s.Opponent, n, pointsWord(n))
+			cmd, _, err := fs.expect("accept", "reject")
+			if err != nil {
+				return err
+			}
+			if cmd == "accept" {
+				fs.send("You accept and win %v %v.", n, pointsWord(n))
+				return fs.gameOver(fakeUser, n)
+			}
+			fs.send("You reject. The game continues.")
+			*fResignRefused = true
+		}
+	}
+
+	double, err := doubles(view, fs.s.Settings)
+	if err != nil || !double {
+		return err
+	}
+
+	fs.ms.Doubled, fs.ms.Turn = true, fakeUser
+	fs.send("%v doubles. Type 'accept' or 'reject'.", fs.s.Opponent)
+	cmd, _, err := fs.expect("accept", "reject")
+	fs.ms.Doubled, fs.ms.Turn = false, fakeOpp
+	if err != nil {
+		return err
+	}
+	if cmd == "reject" {
+		fs.send("You give up. %v wins %v %v.", fs.s.Opponent, fs.ms.Cube.Value, pointsWord(fs.ms.Cube.Value))
+		return fs.gameOver(fakeOpp, fs.ms.Cube.Value)
+	}
+	fs.ms.Cube.Value *= 2
+	fs.ms.Cube.Owner = fakeUser
+	fs.send("You accept the double. The cube shows %v.", fs.ms.Cube.Value)
+	return nil
+}
+
+func (fs *fakeSession) userMove() error {
+	var view = fs.view(fakeUser)
+	var board = moverBoard(fs.board, fakeUser)
+	var plays = gnubg.LegalPlays(board, fs.ms.Dice)
+
+	fs.send("You roll %v and %v.", fs.ms.Dice[0], fs.ms.Dice[1])
+	fs.send("%v", view)
+	if len(plays) == 0 {
+		fs.send("You can't move.")
+		return nil
+	}
+	var n = len(plays[0].Moves)
+	fs.send("Please move %v %v.", n, map[bool]string{true: "piece", false: "pieces"}[n == 1])
+
+	for {
+		_, args, err := fs.expect("move")
+		if err != nil {
+			return err
+		}
+		moves, err := view.parseMoves(fakeUser, args)
+		if err != nil {
+			fs.send("** %v", err)
+			continue
+		}
+		after, err := gnubg.ApplyMoves(board, moves)
+		if err != nil {
+			fs.send("** %v", err)
+			continue
+		}
+		for _, play := range plays {
+			if play.Board == after {
+				return fs.moved(fakeUser, after)
+			}
+		}
+		fs.send("** You can't move that way.")
+	}
+}
+
+func (fs *fakeSession) oppMove() error {
+	var view = fs.view(fakeOpp)
+	moves, err := bestPlay(view, fs.s.Settings)
+	if err != nil {
+		return err
+	}
+
+	fs.send("%v rolls %v and %v.", fs.s.Opponent, fs.ms.Dice[0], fs.ms.Dice[1])
+	if len(moves) == 0 {
+		fs.send("%v can't move.", fs.s.Opponent)
+		return nil
+	}
+	after, err := gnubg.ApplyMoves(moverBoard(fs.board, fakeOpp), moves)
+	if err != nil {
+		return err
+	}
+	fs.send("%v moves %v .", fs.s.Opponent, view.formatMoves(fakeOpp, moves))
+	return fs.moved(fakeOpp, after)
+}
+
+/* takes the board after a play, seen from the player who moved, and ends
+ * the game when they bore off their last chequer */
+func (fs *fakeSession) moved(player int, after gnubg.TanBoard) error {
+	fs.board = moverBoard(after, player)
+	fs.ms.Dice = [2]int{}
+	fs.send("%v", fs.view(fakeUser))
+
+	if chequers(after[1]) > 0 {
+		return nil
+	}
+
+	/* a gammon when the loser bore off none, a backgammon when one is
+	 * still in the winner's home or on the bar */
+	var n = 1
+	if chequers(after[0]) == 15 {
+		n = 2
+		for i := 18; i < 25; i++ {
+			if after[0][i] > 0 {
+				n = 3
+			}
+		}
+	}
+	return fs.gameOver(player, n*fs.ms.Cube.Value)
+}
+
+func (fs *fakeSession) gameOver(winner int, points int) error {
+	fs.nWinner, fs.nPoints = winner, points
+	return errGameOver
+}
+
+func chequers(side [25]int) int {
+	var n int
+	for _, c := range side {
+		n += c
+	}
+	return n
+}
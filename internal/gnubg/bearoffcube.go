@@ -0,0 +1,78 @@
+package gnubg
+
+import (
+	"errors"
+)
+
+// ErrNoCubefulBearoff is returned for positions beyond the cubeful
+// two-sided bearoff databases, or when none is loaded.
+var ErrNoCubefulBearoff = errors.New("position is not in a cubeful two-sided bearoff database")
+
+// BearoffCube holds the exact money game equities of a two-sided bearoff,
+// for the player on roll before rolling, normalised to the cube: with the
+// cube unavailable, as in a cubeless game, owned by them, centred, and
+// owned by their opponent.
+type BearoffCube struct {
+	Cubeless float32
+	Owned    float32
+	Centred  float32
+	OppOwned float32
+}
+
+// Double is the decision of the player on roll with the cube centred.
+func (b BearoffCube) Double() CubeDecision {
+	return CubeDecision{Available: true, NoDouble: b.Centred, DoubleTake: 2 * b.OppOwned, DoublePass: 1}
+}
+
+// Redouble is the decision of the player on roll when they own the cube.
+func (b BearoffCube) Redouble() CubeDecision {
+	return CubeDecision{Available: true, NoDouble: b.Owned, DoubleTake: 2 * b.OppOwned, DoublePass: 1}
+}
+
+// PerfectCube reads the exact cubeful equities of a board seen from the
+// player on roll, as for PositionID, from the two-sided bearoff databases
+// gnubg_ts0.bd and gnubg_ts.bd when they are loaded and hold cubeful
+// equities.
+func PerfectCube(board TanBoard) (BearoffCube, error) {
+	var arEquity [4]float32
+
+	switch classifyPosition(_TanBoard(board), _VARIATION_STANDARD) {
+	case _CLASS_BEAROFF2, _CLASS_BEAROFF_TS:
+	default:
+		return BearoffCube{}, ErrNoCubefulBearoff
+	}
+
+	if err := evaluatePerfectCubeful(_TanBoard(board), &arEquity, _VARIATION_STANDARD); err != nil {
+		return BearoffCube{}, ErrNoCubefulBearoff
+	}
+
+	return BearoffCube{Cubeless: arEquity[0], Owned: arEquity[1], Centred: arEquity[2], OppOwned: arEquity[3]}, nil
+}
+
+// EstimateBearoffCube works out the equities of BearoffCube with the
+// neural nets at the plies of the settings instead, for positions
+// PerfectCube can't read.
+func EstimateBearoffCube(board TanBoard, player int, s Settings) (BearoffCube, error) {
+	var ret BearoffCube
+
+	ar, err := EvaluatePosition(board, player, Cube{Value: 1, Owner: -1}, s)
+	if err != nil {
+		return ret, err
+	}
+	ret.Cubeless = 2*ar[0] - 1 + ar[1] - ar[3] + ar[2] - ar[4]
+
+	centred, err := EvaluateCube(board, player, Cube{Value: 1, Owner: -1}, s)
+	if err != nil {
+		return ret, err
+	}
+	ret.Centred = centred.NoDouble
+	ret.OppOwned = centred.DoubleTake / 2
+
+	owned, err := EvaluateCube(board, player, Cube{Value: 1, Owner: player}, s)
+	if err != nil {
+		return ret, err
+	}
+	ret.Owned = owned.NoDouble
+
+	return ret, nil
+}
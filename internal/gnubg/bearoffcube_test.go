@@ -0,0 +1,140 @@
+package gnubg
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+)
+
+/* a two-sided database of chequers on the 2 lowest points, every position
+ * holding the equities of eq, as stored in gnubg_ts0.bd */
+func twoSidedDatabase(t *testing.T, fCubeful bool, eq [4]float32) *_BearOffContext {
+	const nPoints, nChequers = 2, 2
+	var n = combination(nPoints+nChequers, nPoints)
+	var k = 1
+	if fCubeful {
+		k = 4
+	}
+
+	var p = []byte(fmt.Sprintf("%-40s", fmt.Sprintf("gnubg-TS-%02d-%02d-%v", nPoints, nChequers, btoi(fCubeful))))
+	for iPos := 0; iPos < n*n; iPos++ {
+		for i := 0; i < k; i++ {
+			var us = int((eq[i] + 1) * 32767.5)
+			p = append(p, byte(us), byte(us>>8))
+		}
+	}
+
+	pbc, err := bearoffInit(fstest.MapFS{"ts.bd": {Data: p}}, "ts.bd", _BO_MUST_BE_TWO_SIDED)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pbc
+}
+
+func TestPerfectCube(t *testing.T) {
+	once.Do(setup)
+
+	var bearoff = TanBoard{{0: 1, 1: 1}, {1: 2}}
+
+	tests := []struct {
+		name         string
+		pbc          *_BearOffContext
+		board        TanBoard
+		wantDouble   bool
+		wantRedouble bool
+		wantTake     bool
+		wantErr      error
+	}{
+		{
+			name:         "should double and take",
+			pbc:          twoSidedDatabase(t, true, [4]float32{0.5, 0.6, 0.55, 0.45}),
+			board:        bearoff,
+			wantDouble:   true,
+			wantRedouble: true,
+			wantTake:     true,
+		},
+		{
+			name:         "should double and pass",
+			pbc:          twoSidedDatabase(t, true, [4]float32{0.8, 0.9, 0.85, 0.55}),
+			board:        bearoff,
+			wantDouble:   true,
+			wantRedouble: true,
+		},
+		{
+			name:     "should hold with the cube centred",
+			pbc:      twoSidedDatabase(t, true, [4]float32{0.2, 0.4, 0.35, 0.1}),
+			board:    bearoff,
+			wantTake: true,
+		},
+		{
+			name:    "should refuse a cubeless database",
+			pbc:     twoSidedDatabase(t, false, [4]float32{0.5}),
+			board:   bearoff,
+			wantErr: ErrNoCubefulBearoff,
+		},
+		{
+			name:    "should refuse a position beyond the database",
+			pbc:     twoSidedDatabase(t, true, [4]float32{0.5, 0.6, 0.55, 0.45}),
+			board:   TanBoard{{5: 5, 7: 3, 12: 5, 23: 2}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			wantErr: ErrNoCubefulBearoff,
+		},
+		{
+			name:    "should refuse without a database",
+			board:   bearoff,
+			wantErr: ErrNoCubefulBearoff,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved = pbc2
+			pbc2 = tt.pbc
+			defer func() { pbc2 = saved }()
+
+			got, err := PerfectCube(tt.board)
+			if err != tt.wantErr {
+				t.Fatalf("PerfectCube() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Double().Double() != tt.wantDouble || got.Redouble().Double() != tt.wantRedouble || got.Double().Take() != tt.wantTake {
+				t.Errorf("PerfectCube() = %+v: double %v, redouble %v, take %v, want %v, %v, %v", got,
+					got.Double().Double(), got.Redouble().Double(), got.Double().Take(), tt.wantDouble, tt.wantRedouble, tt.wantTake)
+			}
+		})
+	}
+}
+
+func TestEstimateBearoffCube(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name       string
+		board      TanBoard
+		wantDouble bool
+		wantTake   bool
+	}{
+		{
+			name:       "should double out a last chequer on the ace point",
+			board:      TanBoard{{0: 1}, {0: 1}},
+			wantDouble: true,
+		},
+		{
+			name:     "should hold at the start of a bearoff",
+			board:    TanBoard{{0: 2, 1: 2, 2: 2, 3: 3, 4: 3, 5: 3}, {0: 2, 1: 2, 2: 2, 3: 3, 4: 3, 5: 3}},
+			wantTake: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EstimateBearoffCube(tt.board, 1, Settings{})
+			if err != nil {
+				t.Fatalf("EstimateBearoffCube() error = %v", err)
+			}
+			if got.Double().Double() != tt.wantDouble || got.Double().Take() != tt.wantTake {
+				t.Errorf("EstimateBearoffCube() = %+v: double %v, take %v, want %v, %v", got, got.Double().Double(), got.Double().Take(), tt.wantDouble, tt.wantTake)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package gnubg
+
+import (
+	"errors"
+)
+
+// ErrNotBearoff is returned for positions beyond the one-sided bearoff
+// database.
+var ErrNotBearoff = errors.New("position is not in the one-sided bearoff database")
+
+// BearoffDist is the one-sided bearoff distribution of a side: at index n,
+// the chance of bearing off all of its chequers, and of bearing off the
+// first, in exactly n rolls.
+type BearoffDist struct {
+	Off    [32]float32
+	Gammon [32]float32
+	// average rolls to bear off all chequers, and the first, with their
+	// standard deviations
+	Mean, SD             float32
+	GammonMean, GammonSD float32
+}
+
+// Bearoff holds the distributions of both sides of a bearoff, indexed as
+// the board, and the chances of the race they make.
+type Bearoff struct {
+	Dists [2]BearoffDist
+	// win, win gammon and lose gammon chances of the player on roll
+	Win, WinGammon, LoseGammon float32
+}
+
+// BearoffDistribution reads the one-sided bearoff distributions of a
+// board seen from the player on roll, as for PositionID, and works out
+// the race from them as gnubg evaluates one-sided bearoffs. Both sides
+// must be in the database.
+func BearoffDistribution(board TanBoard) (Bearoff, error) {
+	var ret Bearoff
+	var anBoard = _TanBoard(board)
+
+	for i := 0; i < 2; i++ {
+		if !isOneSidedBearoff(pbc1, anBoard[i]) {
+			return ret, ErrNotBearoff
+		}
+
+		var d = &ret.Dists[i]
+		var ar [4]float32
+		if err := bearoffDist(pbc1, positionBearoff(anBoard.getHomeBoard(i), pbc1.nPoints, pbc1.nChequers), &d.Off, &d.Gammon, &ar, nil, nil); err != nil {
+			return ret, err
+		}
+		d.Mean, d.SD, d.GammonMean, d.GammonSD = ar[0], ar[1], ar[2], ar[3]
+	}
+
+	var arOutput [_NUM_OUTPUTS]float32
+	if err := bearoffEvalOneSided(pbc1, anBoard, &arOutput); err != nil {
+		return ret, err
+	}
+	ret.Win = arOutput[_OUTPUT_WIN]
+	ret.WinGammon = arOutput[_OUTPUT_WINGAMMON]
+	ret.LoseGammon = arOutput[_OUTPUT_LOSEGAMMON]
+
+	return ret, nil
+}
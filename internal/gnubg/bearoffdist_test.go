@@ -0,0 +1,75 @@
+package gnubg
+
+import (
+	"testing"
+)
+
+func TestBearoffDistribution(t *testing.T) {
+	once.Do(setup)
+
+	var near = func(a float32, b float32) bool {
+		return a-b < 0.0001 && b-a < 0.0001
+	}
+
+	tests := []struct {
+		name           string
+		board          TanBoard
+		wantMean       [2]float32
+		wantWin        float32
+		wantWinGammon  float32
+		wantLoseGammon float32
+		wantErr        error
+	}{
+		{
+			name:     "should bear off a last chequer on the ace point",
+			board:    TanBoard{{0: 1}, {0: 1}},
+			wantMean: [2]float32{1, 1},
+			wantWin:  1,
+		},
+		{
+			name:     "should bear off a chequer on the 6 point with 27 rolls of 36",
+			board:    TanBoard{{0: 1}, {5: 1}},
+			wantMean: [2]float32{1, 1.25},
+			wantWin:  0.75,
+		},
+		{
+			name:          "should win a gammon against 15 chequers on the 6 point",
+			board:         TanBoard{{5: 15}, {0: 2}},
+			wantMean:      [2]float32{0, 1},
+			wantWin:       1,
+			wantWinGammon: 1,
+		},
+		{
+			name:    "should refuse a position beyond the database",
+			board:   TanBoard{{5: 5, 7: 3, 12: 5, 23: 2}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			wantErr: ErrNotBearoff,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BearoffDistribution(tt.board)
+			if err != tt.wantErr {
+				t.Fatalf("BearoffDistribution() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			for i, d := range got.Dists {
+				var sum float32
+				for _, p := range d.Off {
+					sum += p
+				}
+				if !near(sum, 1) {
+					t.Errorf("BearoffDistribution() side %v adds up to %v", i, sum)
+				}
+				if tt.wantMean[i] != 0 && !near(d.Mean, tt.wantMean[i]) {
+					t.Errorf("BearoffDistribution() side %v mean = %v, want %v", i, d.Mean, tt.wantMean[i])
+				}
+			}
+			if !near(got.Win, tt.wantWin) || !near(got.WinGammon, tt.wantWinGammon) || !near(got.LoseGammon, tt.wantLoseGammon) {
+				t.Errorf("BearoffDistribution() = %v, %v, %v, want %v, %v, %v", got.Win, got.WinGammon, got.LoseGammon, tt.wantWin, tt.wantWinGammon, tt.wantLoseGammon)
+			}
+		})
+	}
+}
@@ -92,6 +92,15 @@ func cacheLookup(pc *_EvalCache, e *_CacheNodeDetail, arOut *[_NUM_OUTPUTS]float
 	return
 }
 
+/* whether e is in the cache, without promoting it or copying it out as
+ * cacheLookup does */
+func cacheContains(pc *_EvalCache, e *_CacheNodeDetail) bool {
+	l := getHashKey(pc.hashMask, e)
+
+	return (pc.entries[l].nd_primary.key.equals(e.key) && pc.entries[l].nd_primary.nEvalContext == e.nEvalContext) ||
+		(pc.entries[l].nd_secondary.key.equals(e.key) && pc.entries[l].nd_secondary.nEvalContext == e.nEvalContext)
+}
+
 func getHashKey(hashMask _HashKey, e *_CacheNodeDetail) _HashKey {
 	hash := _HashKey(e.nEvalContext)
 
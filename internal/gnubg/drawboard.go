@@ -0,0 +1,245 @@
+package gnubg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DrawBoard draws a position in the ASCII layout of gnubg's "show board":
+// the board, seen from the player on roll as in PositionID, with the
+// names, score, dice and cube beside it and the pip counts below.
+//
+// Players are 0 for O and 1 for X, as in MatchState, and names are theirs.
+// bottom is the player drawn at the bottom with their home board on the
+// right, as gnubg always draws X; points are numbered from the player on
+// roll.
+func DrawBoard(board TanBoard, ms MatchState, names [2]string, bottom int) string {
+	var top = 1 - bottom
+
+	/* the chequers of each player, from their own side */
+	var aSide [2][25]int
+	aSide[ms.Move], aSide[1-ms.Move] = board[1], board[0]
+
+	/* as gnubg's DrawBoard, with O on top and X at the bottom */
+	var anBoard = [2][25]int{aSide[top], aSide[bottom]}
+	var achTop, achBottom = chequerChars(top), chequerChars(bottom)
+	var fRoll = ms.Move == bottom
+
+	var cOffTop, cOffBottom = 15, 15
+	for i := 0; i < 25; i++ {
+		cOffTop -= anBoard[0][i]
+		cOffBottom -= anBoard[1][i]
+	}
+
+	var asz = boardAnnotations(ms, names, top, bottom)
+
+	var lines []string
+	var sb strings.Builder
+	var endLine = func() {
+		lines = append(lines, strings.TrimRight(sb.String(), " "))
+		sb.Reset()
+	}
+	var point = func(x int, y int) byte {
+		switch {
+		case anBoard[1][x] > y:
+			return achBottom[0]
+		case anBoard[0][23-x] > y:
+			return achTop[0]
+		}
+		return ' '
+	}
+	var count = func(x int) byte {
+		if anBoard[1][x] > 0 {
+			return countChar(achBottom, anBoard[1][x])
+		}
+		return countChar(achTop, anBoard[0][23-x])
+	}
+	var off = func(c int, y int, ch byte) {
+		for x := 0; x < 3; x++ {
+			if c > 5*x+y {
+				sb.WriteByte(ch)
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+	}
+
+	fmt.Fprintf(&sb, " GNU Backgammon  Position ID: %v", PositionID(board))
+	endLine()
+	fmt.Fprintf(&sb, "                 Match ID   : %v", MatchID(ms))
+	endLine()
+
+	sb.WriteString(borderLine(fRoll) + asz[0])
+	endLine()
+
+	for y := 0; y < 4; y++ {
+		sb.WriteString(" |")
+		for x := 12; x < 18; x++ {
+			sb.WriteString(" " + string(point(x, y)) + " ")
+		}
+		sb.WriteString("| " + string(charIf(anBoard[0][24] > y, achTop[0])) + " |")
+		for x := 18; x < 24; x++ {
+			sb.WriteString(" " + string(point(x, y)) + " ")
+		}
+		sb.WriteString("| ")
+		off(cOffTop, y, achTop[0])
+		if y < 2 {
+			sb.WriteString(" " + asz[y+1])
+		}
+		endLine()
+	}
+
+	sb.WriteString(" |")
+	for x := 12; x < 18; x++ {
+		sb.WriteString(" " + string(count(x)) + " ")
+	}
+	sb.WriteString("| " + string(countChar(achTop, anBoard[0][24])) + " |")
+	for x := 18; x < 24; x++ {
+		sb.WriteString(" " + string(count(x)) + " ")
+	}
+	sb.WriteString("| ")
+	off(cOffTop, 4, achTop[0])
+	endLine()
+
+	if fRoll {
+		sb.WriteByte('v')
+	} else {
+		sb.WriteByte('^')
+	}
+	sb.WriteString("|                  |BAR|                  |     " + asz[3])
+	endLine()
+
+	sb.WriteString(" |")
+	for x := 11; x > 5; x-- {
+		sb.WriteString(" " + string(count(x)) + " ")
+	}
+	sb.WriteString("| " + string(countChar(achBottom, anBoard[1][24])) + " |")
+	for x := 5; x >= 0; x-- {
+		sb.WriteString(" " + string(count(x)) + " ")
+	}
+	sb.WriteString("| ")
+	off(cOffBottom, 4, achBottom[0])
+	endLine()
+
+	for y := 3; y >= 0; y-- {
+		sb.WriteString(" |")
+		for x := 11; x > 5; x-- {
+			sb.WriteString(" " + string(point(x, y)) + " ")
+		}
+		sb.WriteString("| " + string(charIf(anBoard[1][24] > y, achBottom[0])) + " |")
+		for x := 5; x >= 0; x-- {
+			sb.WriteString(" " + string(point(x, y)) + " ")
+		}
+		sb.WriteString("| ")
+		off(cOffBottom, y, achBottom[0])
+		if y < 2 {
+			sb.WriteString(" " + asz[5-y])
+		}
+		endLine()
+	}
+
+	sb.WriteString(borderLine(!fRoll) + asz[6])
+	endLine()
+
+	fmt.Fprintf(&sb, " Pip counts: O %v, X %v", pips(aSide[0]), pips(aSide[1]))
+	endLine()
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func borderLine(fHigh bool) string {
+	if fHigh {
+		return " +13-14-15-16-17-18------19-20-21-22-23-24-+     "
+	}
+	return " +12-11-10--9--8--7-------6--5--4--3--2--1-+     "
+}
+
+/* the chequer of a player first, then what the fifth row shows for a
+ * point holding that many */
+func chequerChars(player int) string {
+	if player == 1 {
+		return "X    X6789ABCDEF"
+	}
+	return "O    O6789ABCDEF"
+}
+
+func countChar(ach string, n int) byte {
+	switch {
+	case n < 5:
+		return ' '
+	case n > 15:
+		return '+'
+	}
+	return ach[n]
+}
+
+func charIf(f bool, ch byte) byte {
+	if f {
+		return ch
+	}
+	return ' '
+}
+
+/* the text beside the board: the top player's name, score and state,
+ * the cube in the middle, and the bottom player's state, score and name */
+func boardAnnotations(ms MatchState, names [2]string, top int, bottom int) [7]string {
+	var asz [7]string
+	var letters = [2]string{"O", "X"}
+
+	for _, p := range []struct{ player, name, score, state int }{{top, 0, 1, 2}, {bottom, 6, 5, 4}} {
+		asz[p.name] = letters[p.player]
+		if names[p.player] != "" {
+			asz[p.name] += ": " + names[p.player]
+		}
+		asz[p.score] = fmt.Sprintf("%v %v", ms.Cube.Score[p.player], pluralPoints(ms.Cube.Score[p.player]))
+
+		var state []string
+		switch {
+		case ms.GameState != 1:
+		case ms.Resigned > 0 && p.player == ms.Move:
+			state = append(state, "Resigns "+[]string{"a single game", "a gammon", "a backgammon"}[(ms.Resigned-1)%3])
+		case ms.Doubled && p.player == ms.Turn:
+			state = append(state, fmt.Sprintf("Cube offered at %v", 2*ms.Cube.Value))
+		case !ms.Doubled && ms.Resigned == 0 && p.player == ms.Move:
+			if ms.Dice[0] > 0 {
+				state = append(state, fmt.Sprintf("Rolled %v%v", ms.Dice[0], ms.Dice[1]))
+			} else {
+				state = append(state, "On roll")
+			}
+		}
+		if ms.Cube.Owner == p.player {
+			state = append(state, fmt.Sprintf("(Cube: %v)", ms.Cube.Value))
+		}
+		asz[p.state] = strings.Join(state, " ")
+	}
+
+	var centre []string
+	if ms.Cube.MatchTo > 0 {
+		centre = append(centre, fmt.Sprintf("%v point match", ms.Cube.MatchTo))
+		if ms.Cube.Crawford {
+			centre = append(centre, "Crawford game")
+		}
+	}
+	asz[3] = strings.Join(centre, ", ")
+	if ms.Cube.Owner < 0 {
+		asz[3] = strings.TrimSpace(asz[3] + fmt.Sprintf(" (Cube: %v)", ms.Cube.Value))
+	}
+
+	return asz
+}
+
+func pluralPoints(n int) string {
+	if n == 1 {
+		return "point"
+	}
+	return "points"
+}
+
+/* pip count of a player's chequers, from their side */
+func pips(side [25]int) int {
+	var n int
+	for i, c := range side {
+		n += (i + 1) * c
+	}
+	return n
+}
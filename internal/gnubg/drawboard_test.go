@@ -0,0 +1,95 @@
+package gnubg
+
+import (
+	"strings"
+	"testing"
+)
+
+/* the starting position, x to play 3-1 in a money game */
+const startDrawn = ` GNU Backgammon  Position ID: 4HPwATDgc/ABMA
+                 Match ID   : cIkFAAAAAAAA
+ +13-14-15-16-17-18------19-20-21-22-23-24-+     O: gnubg
+ | X           O    |   | O              X |     0 points
+ | X           O    |   | O              X |
+ | X           O    |   | O                |
+ | X                |   | O                |
+ | X                |   | O                |
+v|                  |BAR|                  |     (Cube: 1)
+ | O                |   | X                |
+ | O                |   | X                |
+ | O           X    |   | X                |
+ | O           X    |   | X              O |     Rolled 31
+ | O           X    |   | X              O |     0 points
+ +12-11-10--9--8--7-------6--5--4--3--2--1-+     X: alice
+ Pip counts: O 167, X 167
+`
+
+func TestDrawBoard(t *testing.T) {
+	var start = TanBoard{
+		{5: 5, 7: 3, 12: 5, 23: 2},
+		{5: 5, 7: 3, 12: 5, 23: 2},
+	}
+	var money = MatchState{Cube: Cube{Value: 1, Owner: -1, Jacoby: true}, Move: 1, Turn: 1, Dice: [2]int{3, 1}, GameState: 1}
+
+	/* o on roll in a 5 point match, owning the cube at 2, with a chequer
+	 * on the bar and one off; the board is seen from o */
+	var match = MatchState{Cube: Cube{Value: 2, Owner: 0, MatchTo: 5, Score: [2]int{1, 3}}, Move: 0, Turn: 0, GameState: 1}
+	var board = TanBoard{
+		{5: 5, 7: 3, 12: 5, 23: 2},
+		{0: 1, 5: 4, 7: 3, 12: 5, 24: 1},
+	}
+
+	tests := []struct {
+		name   string
+		board  TanBoard
+		ms     MatchState
+		bottom int
+		want   []string
+	}{
+		{
+			name:   "should draw the starting position as gnubg",
+			board:  start,
+			ms:     money,
+			bottom: 1,
+			want:   strings.Split(startDrawn, "\n"),
+		},
+		{
+			name:   "should number points from the player on roll",
+			board:  start,
+			ms:     money,
+			bottom: 0,
+			want: []string{
+				" +12-11-10--9--8--7-------6--5--4--3--2--1-+     X: alice",
+				"^|                  |BAR|                  |     (Cube: 1)",
+				" +13-14-15-16-17-18------19-20-21-22-23-24-+     O: gnubg",
+			},
+		},
+		{
+			name:   "should draw the bar, borne off chequers, cube and score",
+			board:  board,
+			ms:     match,
+			bottom: 0,
+			want: []string{
+				" +13-14-15-16-17-18------19-20-21-22-23-24-+     X: alice",
+				" | O           X    |   | X                |     3 points",
+				"v|                  |BAR|                  |     5 point match",
+				" | X           O    |   | O              X |     On roll (Cube: 2)",
+				" | X           O    | O | O              O | O   1 point",
+				" Pip counts: O 139, X 167",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DrawBoard(tt.board, tt.ms, [2]string{"gnubg", "alice"}, tt.bottom)
+			for _, line := range tt.want {
+				if !strings.Contains(got, line+"\n") && line != "" {
+					t.Errorf("DrawBoard() = \n%v\nwant the line %q", got, line)
+				}
+			}
+			if tt.bottom == 1 && tt.ms == money && got != startDrawn {
+				t.Errorf("DrawBoard() = \n%v\nwant\n%v", got, startDrawn)
+			}
+		})
+	}
+}
@@ -100,7 +100,9 @@ type _EvalContext struct {
 	fUsePrune      bool
 	fDeterministic bool
 	// unsigned int :25;		/* padding */
-	rNoise float32 /* standard deviation */
+	rNoise     float32  /* standard deviation */
+	fQuantized bool     /* use the int8 quantized nets */
+	pWeights   *Weights /* nets to use instead of the engine's own, if set */
 }
 
 type _Move struct {
@@ -162,6 +164,18 @@ func (t _Move) GetProbLoseBG() float32 {
 	return t.arEvalMove[_OUTPUT_LOSEBACKGAMMON]
 }
 
+/* board after the move, with the opponent on roll */
+func (t _Move) GetBoard() TanBoard {
+	var anBoard _TanBoard
+	t.key.toBoard(&anBoard)
+	return TanBoard{anBoard[1], anBoard[0]}
+}
+
+/* position ID after the move, with the opponent on roll */
+func (t _Move) GetPositionID() string {
+	return positionID(_TanBoard(t.GetBoard()))
+}
+
 type _MoveList struct {
 	cMoves              int /* and current move when building list */
 	cMaxMoves, cMaxPips int
@@ -363,6 +377,10 @@ var anPoint = [16]int{0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
 var nnContact, nnRace, nnCrashed _NeuralNet
 var nnpContact, nnpRace, nnpCrashed _NeuralNet
 
+/* quantized copies of the nets above, see neuralnetq.go */
+var nnqContact, nnqRace, nnqCrashed _NeuralNetQ
+var nnqpContact, nnqpRace, nnqpCrashed _NeuralNetQ
+
 var pbcOS *_BearOffContext
 var pbcTS *_BearOffContext
 var pbc1 *_BearOffContext
@@ -396,7 +414,7 @@ type _MoveFilter struct {
 
 const _MAX_FILTER_PLIES = 4
 
-var ecBasic = _EvalContext{false, 0, false, false, 0.0}
+var ecBasic = _EvalContext{false, 0, false, false, 0.0, false, nil}
 
 var defaultFilters [_MAX_FILTER_PLIES][_MAX_FILTER_PLIES]_MoveFilter = _MOVEFILTER_NORMAL
 
@@ -505,26 +523,27 @@ func evalInitialise(dataDir fs.FS) error {
 	}
 	defer pfWeights.Close()
 
-	if err = verifyWeights(pfWeights, weightsFile); err != nil {
+	szVersion, err := readWeightsVersion(pfWeights, weightsFile)
+	if err != nil {
 		return fmt.Errorf("error while verifying weights file: %v", err)
 	}
 
-	if err := neuralNetLoad(&nnContact, pfWeights); err != nil {
+	if err := neuralNetLoadVersion(&nnContact, pfWeights, szVersion); err != nil {
 		return fmt.Errorf("error while loading nnContact: %v", err)
 	}
-	if err := neuralNetLoad(&nnRace, pfWeights); err != nil {
+	if err := neuralNetLoadVersion(&nnRace, pfWeights, szVersion); err != nil {
 		return fmt.Errorf("error while loading nnRace: %v", err)
 	}
-	if err := neuralNetLoad(&nnCrashed, pfWeights); err != nil {
+	if err := neuralNetLoadVersion(&nnCrashed, pfWeights, szVersion); err != nil {
 		return fmt.Errorf("error while loading nnCrashed: %v", err)
 	}
-	if err := neuralNetLoad(&nnpContact, pfWeights); err != nil {
+	if err := neuralNetLoadVersion(&nnpContact, pfWeights, szVersion); err != nil {
 		return fmt.Errorf("error while loading nnpContact: %v", err)
 	}
-	if err := neuralNetLoad(&nnpCrashed, pfWeights); err != nil {
+	if err := neuralNetLoadVersion(&nnpCrashed, pfWeights, szVersion); err != nil {
 		return fmt.Errorf("error while loading nnpCrashed: %v", err)
 	}
-	if err := neuralNetLoad(&nnpRace, pfWeights); err != nil {
+	if err := neuralNetLoadVersion(&nnpRace, pfWeights, szVersion); err != nil {
 		return fmt.Errorf("error while loading nnpRace: %v", err)
 	}
 
@@ -547,6 +566,8 @@ func evalInitialise(dataDir fs.FS) error {
 		return fmt.Errorf("invalid nnpRace")
 	}
 
+	quantizeWeights()
+
 	return nil
 }
 
@@ -576,6 +597,24 @@ func destroyWeights() {
 	neuralNetDestroy(&nnpContact)
 	neuralNetDestroy(&nnpCrashed)
 	neuralNetDestroy(&nnpRace)
+
+	nnqContact = _NeuralNetQ{}
+	nnqCrashed = _NeuralNetQ{}
+	nnqRace = _NeuralNetQ{}
+
+	nnqpContact = _NeuralNetQ{}
+	nnqpCrashed = _NeuralNetQ{}
+	nnqpRace = _NeuralNetQ{}
+}
+
+func quantizeWeights() {
+	neuralNetQuantize(&nnqContact, &nnContact)
+	neuralNetQuantize(&nnqCrashed, &nnCrashed)
+	neuralNetQuantize(&nnqRace, &nnRace)
+
+	neuralNetQuantize(&nnqpContact, &nnpContact)
+	neuralNetQuantize(&nnqpCrashed, &nnpCrashed)
+	neuralNetQuantize(&nnqpRace, &nnpRace)
 }
 
 func generateMoves(tld *_ThreadLocalData, pml *_MoveList, anBoard _TanBoard, n0 int, n1 int, fPartial bool) int {
@@ -1222,7 +1261,11 @@ func evaluatePositionCubeful4(tld *_ThreadLocalData, nnStates *[3]_NNState, anBo
 			arOutput[_OUTPUT_LOSEBACKGAMMON] = 0.0
 		} else {
 			/* evaluate with neural net */
-			if err := evaluatePosition(tld, nnStates, anBoard, arOutput, pciMove, nil); err != nil {
+			ec := ecBasic
+			ec.fQuantized = pec.fQuantized
+			ec.pWeights = pec.pWeights
+
+			if err := evaluatePosition(tld, nnStates, anBoard, arOutput, pciMove, &ec); err != nil {
 				return fmt.Errorf("error in evaluatePosition: %v", err)
 			}
 
@@ -1486,8 +1529,14 @@ func findBestMoveInEval(tld *_ThreadLocalData, nnStates *[3]_NNState, nDice0 int
 		}
 
 		ec.key.copyFrom(pm.key)
-		ec.nEvalContext = 0
-		if hit, l := cacheLookup(&cpEval, &ec, &arOutput, nil); !hit {
+		ec.nEvalContext = btoi(pec.fQuantized)
+		/* the cache only holds results of the engine's own nets */
+		var hit bool
+		var l _HashKey
+		if pec.pWeights == nil {
+			hit, l = cacheLookup(&cpEval, &ec, &arOutput, nil)
+		}
+		if !hit {
 			var arInput []float32 = make([]float32, _NUM_PRUNING_INPUTS)
 
 			baseInputs(*anBoardOut, arInput)
@@ -1502,20 +1551,28 @@ func findBestMoveInEval(tld *_ThreadLocalData, nnStates *[3]_NNState, nDice0 int
 			// 	os.Exit(0)
 			// }
 
-			nets := []*_NeuralNet{&nnpRace, &nnpCrashed, &nnpContact}
-			n := nets[pc-_CLASS_RACE]
+			if pec.pWeights != nil {
+				neuralNetEvaluateSSE(pec.pWeights.pruningNet(pc), arInput, &arOutput, nil)
+			} else if pec.fQuantized {
+				qnets := []*_NeuralNetQ{&nnqpRace, &nnqpCrashed, &nnqpContact}
 
-			var nnState *_NNState
-			if nnStates != nil {
-				nnState = &(*nnStates)[pc-_CLASS_RACE]
-				if i == 0 {
-					nnState.state = _NNSTATE_INCREMENTAL
-				} else {
-					nnState.state = _NNSTATE_DONE
+				neuralNetEvaluateQ(qnets[pc-_CLASS_RACE], arInput, &arOutput)
+			} else {
+				nets := []*_NeuralNet{&nnpRace, &nnpCrashed, &nnpContact}
+				n := nets[pc-_CLASS_RACE]
+
+				var nnState *_NNState
+				if nnStates != nil {
+					nnState = &(*nnStates)[pc-_CLASS_RACE]
+					if i == 0 {
+						nnState.state = _NNSTATE_INCREMENTAL
+					} else {
+						nnState.state = _NNSTATE_DONE
+					}
 				}
-			}
 
-			neuralNetEvaluateSSE(n, arInput, &arOutput, nnState)
+				neuralNetEvaluateSSE(n, arInput, &arOutput, nnState)
+			}
 
 			if pc == _CLASS_RACE {
 				/* special evaluation of backgammons
@@ -1524,9 +1581,11 @@ func findBestMoveInEval(tld *_ThreadLocalData, nnStates *[3]_NNState, nDice0 int
 			}
 			sanityCheck(*anBoardOut, &arOutput)
 
-			copy(ec.ar[:], arOutput[:])
-			ec.ar[5] = 0.0
-			cacheAdd(&cpEval, &ec, l)
+			if pec.pWeights == nil {
+				copy(ec.ar[:], arOutput[:])
+				ec.ar[5] = 0.0
+				cacheAdd(&cpEval, &ec, l)
+			}
 		}
 
 		pm.rScore = utilityME(&arOutput, pci)
@@ -1754,17 +1813,13 @@ finished:
 
 				/* move it up to the other moves evaluated on nMaxPly */
 
-				if fResort && pec.nPlies > 0 {
-					var m []_Move = make([]_Move, cOldMoves-i)
-					copy(m, pml.amMoves[i:])
-
-					for j := i - 1; j >= cOldMoves; j-- {
-						copy(pml.amMoves[j+1:], pml.amMoves[j:])
-					}
-					copy(pml.amMoves[cOldMoves:], m)
+				if fResort && pec.nPlies > 0 && i >= cOldMoves {
+					var m = pml.amMoves[i]
+					copy(pml.amMoves[cOldMoves+1:i+1], pml.amMoves[cOldMoves:i])
+					pml.amMoves[cOldMoves] = m
 
 					/* reorder moves evaluated on nMaxPly */
-					sortMoves(pml.amMoves)
+					sortMoves(pml.amMoves[:cOldMoves+1])
 				}
 				break
 			}
@@ -2507,7 +2562,7 @@ func evaluatePositionCache(tld *_ThreadLocalData, nnStates *[3]_NNState, anBoard
 	/* This should be a part of the code that is called in all
 	 * time-consuming operations at a relatively steady rate, so is a
 	 * good choice for a callback function. */
-	if cCache == 0 || pecx.rNoise != 0.0 { /* non-deterministic noisy evaluations; cannot cache */
+	if cCache == 0 || pecx.rNoise != 0.0 || pecx.pWeights != nil { /* non-deterministic noisy evaluations, or other nets; cannot cache */
 		return evaluatePositionFull(tld, nnStates, anBoard, arOutput, pci, pecx, nPlies, pc)
 	}
 
@@ -2545,9 +2600,10 @@ func evalKey(pec *_EvalContext, nPlies int, pci *_CubeInfo, fCubefulEquity bool)
 	 * Bit 25   : fCrawford
 	 * Bit 26   : fJacoby
 	 * Bit 27   : fBeavers
+	 * Bit 28   : fQuantized
 	 */
 
-	iKey = (nPlies | (btoi(pec.fCubeful) << 4) | (pci.fMove << 5))
+	iKey = (nPlies | (btoi(pec.fCubeful) << 4) | (pci.fMove << 5) | (btoi(pec.fQuantized) << 28))
 
 	if nPlies > 0 {
 		iKey ^= (btoi(pec.fUsePrune) << 6)
@@ -2657,28 +2713,48 @@ func evaluatePositionFull(tld *_ThreadLocalData, nnStates *[3]_NNState, anBoard
 
 	} else {
 		/* at leaf node; use static evaluation */
-
-		if err := acef[pc](anBoard, arOutput, pci.bgv, nnStates); err != nil {
-			return fmt.Errorf("error in acef: %v", err)
+		if err := staticEvaluation(nnStates, anBoard, arOutput, pci, pec, pc); err != nil {
+			return err
 		}
 
-		if pec.rNoise > 0.0 && pc != _CLASS_OVER {
-			for i := 0; i < _NUM_OUTPUTS; i++ {
-				arOutput[i] += noise(pec, anBoard, i)
-				arOutput[i] = math32.Max(arOutput[i], 0.0)
-				arOutput[i] = math32.Min(arOutput[i], 1.0)
-			}
+		if needsSanityCheck(pc, pec) {
+			sanityCheck(anBoard, arOutput)
 		}
+	}
 
-		if pc > _CLASS_GOOD || pec.rNoise > 0.0 {
-			/* no sanity check needed for accurate evaluations */
-			sanityCheck(anBoard, arOutput)
+	return nil
+}
+
+/* the evaluation of a leaf node by the database, net or rule of its class,
+ * with noise added but before the sanity check */
+func staticEvaluation(nnStates *[3]_NNState, anBoard _TanBoard, arOutput *[_NUM_OUTPUTS]float32, pci *_CubeInfo, pec *_EvalContext, pc _PositionClass) error {
+	pef := &acef
+	if pec.fQuantized {
+		pef = &acefQ
+	}
+
+	if pec.pWeights != nil && pec.pWeights.evaluateClass(anBoard, pc, arOutput, pci.bgv) {
+		/* evaluated with the context's own nets */
+	} else if err := pef[pc](anBoard, arOutput, pci.bgv, nnStates); err != nil {
+		return fmt.Errorf("error in acef: %v", err)
+	}
+
+	if pec.rNoise > 0.0 && pc != _CLASS_OVER {
+		for i := 0; i < _NUM_OUTPUTS; i++ {
+			arOutput[i] += noise(pec, anBoard, i)
+			arOutput[i] = math32.Max(arOutput[i], 0.0)
+			arOutput[i] = math32.Min(arOutput[i], 1.0)
 		}
 	}
 
 	return nil
 }
 
+/* no sanity check needed for accurate evaluations */
+func needsSanityCheck(pc _PositionClass, pec *_EvalContext) bool {
+	return pc > _CLASS_GOOD || pec.rNoise > 0.0
+}
+
 var acef = [_N_CLASSES]classEvalFunc{
 	evalOver,
 	evalHypergammon1,
@@ -2689,6 +2765,17 @@ var acef = [_N_CLASSES]classEvalFunc{
 	evalRace, evalCrashed, evalContact,
 }
 
+/* same as acef, but using the quantized nets */
+var acefQ = [_N_CLASSES]classEvalFunc{
+	evalOver,
+	evalHypergammon1,
+	evalHypergammon2,
+	evalHypergammon3,
+	evalBearoff2, evalBearoffTS,
+	evalBearoff1, evalBearoffOS,
+	evalRaceQ, evalCrashedQ, evalContactQ,
+}
+
 func evalRace(anBoard _TanBoard, arOutput *[_NUM_OUTPUTS]float32, bgv _BGVariation, nnStates *[3]_NNState) error {
 	var arInput []float32 = make([]float32, _NUM_RACE_INPUTS)
 
@@ -2738,6 +2825,36 @@ func evalCrashed(anBoard _TanBoard, arOutput *[_NUM_OUTPUTS]float32, bgv _BGVari
 
 }
 
+func evalRaceQ(anBoard _TanBoard, arOutput *[_NUM_OUTPUTS]float32, bgv _BGVariation, nnStates *[3]_NNState) error {
+	var arInput []float32 = make([]float32, _NUM_RACE_INPUTS)
+
+	calculateRaceInputs(anBoard, arInput)
+
+	if err := neuralNetEvaluateQ(&nnqRace, arInput, arOutput); err != nil {
+		return fmt.Errorf("error in %v", err)
+	}
+
+	evalRaceBG(anBoard, arOutput, bgv)
+
+	return nil
+}
+
+func evalContactQ(anBoard _TanBoard, arOutput *[_NUM_OUTPUTS]float32, bgv _BGVariation, nnStates *[3]_NNState) error {
+	var arInput []float32 = make([]float32, _NUM_INPUTS)
+
+	calculateContactInputs(anBoard, arInput)
+
+	return neuralNetEvaluateQ(&nnqContact, arInput, arOutput)
+}
+
+func evalCrashedQ(anBoard _TanBoard, arOutput *[_NUM_OUTPUTS]float32, bgv _BGVariation, nnStates *[3]_NNState) error {
+	var arInput []float32 = make([]float32, _NUM_INPUTS)
+
+	calculateCrashedInputs(anBoard, arInput)
+
+	return neuralNetEvaluateQ(&nnqCrashed, arInput, arOutput)
+}
+
 func evalOver(anBoard _TanBoard, arOutput *[_NUM_OUTPUTS]float32, bgv _BGVariation, nnStates *[3]_NNState) error {
 	var i, c int
 	var n int = anChequers[bgv]
@@ -4242,7 +4359,7 @@ func _Cl2CfMatchCentered(arOutput *[_NUM_OUTPUTS]float32, pci *_CubeInfo, rCubeX
 
 	/* Get live cube cash points */
 
-	getPoints(arOutput, pci, arCP)
+	getPoints(arOutput, pci, &arCP)
 
 	getMEMultiple(pci.anScore[0], pci.anScore[1], pci.nMatchTo,
 		pci.nCube, -1, -1, pci.fCrawford, &aafMET, &aafMETPostCrawford, aarMETResult[0][:], aarMETResult[1][:])
@@ -4341,7 +4458,7 @@ func _Cl2CfMatchOwned(arOutput *[_NUM_OUTPUTS]float32, pci *_CubeInfo, rCubeX fl
 
 	/* Get live cube cash points */
 
-	getPoints(arOutput, pci, arCP)
+	getPoints(arOutput, pci, &arCP)
 
 	getMEMultiple(pci.anScore[0], pci.anScore[1], pci.nMatchTo,
 		pci.nCube, -1, -1, pci.fCrawford, &aafMET, &aafMETPostCrawford, aarMETResult[0][:], aarMETResult[1][:])
@@ -4435,7 +4552,7 @@ func _Cl2CfMatchUnavailable(arOutput *[_NUM_OUTPUTS]float32, pci *_CubeInfo, rCu
 
 	/* Get live cube cash points */
 
-	getPoints(arOutput, pci, arCP)
+	getPoints(arOutput, pci, &arCP)
 
 	getMEMultiple(pci.anScore[0], pci.anScore[1], pci.nMatchTo,
 		pci.nCube, -1, -1, pci.fCrawford, &aafMET, &aafMETPostCrawford, aarMETResult[0][:], aarMETResult[1][:])
@@ -0,0 +1,90 @@
+package gnubg
+
+import (
+	"fmt"
+)
+
+/* names of the contact and crashed inputs calculateHalfInputs and the men
+ * off functions add after the point by point ones */
+var aszInputName = [_MORE_INPUTS]string{
+	_I_OFF1:           "off1",
+	_I_OFF2:           "off2",
+	_I_OFF3:           "off3",
+	_I_BREAK_CONTACT:  "breakContact",
+	_I_BACK_CHEQUER:   "backChequer",
+	_I_BACK_ANCHOR:    "backAnchor",
+	_I_FORWARD_ANCHOR: "forwardAnchor",
+	_I_PIPLOSS:        "pipLoss",
+	_I_P1:             "p1",
+	_I_P2:             "p2",
+	_I_BACKESCAPES:    "backEscapes",
+	_I_ACONTAIN:       "aContain",
+	_I_ACONTAIN2:      "aContain2",
+	_I_CONTAIN:        "contain",
+	_I_CONTAIN2:       "contain2",
+	_I_MOBILITY:       "mobility",
+	_I_MOMENT2:        "moment2",
+	_I_ENTER:          "enter",
+	_I_ENTER2:         "enter2",
+	_I_TIMING:         "timing",
+	_I_BACKBONE:       "backbone",
+	_I_BACKG:          "backG",
+	_I_BACKG1:         "backG1",
+	_I_FREEPIP:        "freePip",
+	_I_BACKRESCAPES:   "backRescapes",
+}
+
+// NetFeatures returns the inputs of the net evaluating the position by
+// name, for each side of the board. The inputs encoding the chequers on
+// each point are left out; see NetInputs for all of them as the net
+// takes them. The race net's are menOff1 to menOff14, one of which is 1
+// for the chequers borne off, and crossovers. The contact and crashed
+// nets' are Berliner's features, such as breakContact, pipLoss, p1, p2,
+// aContain, mobility, timing and backbone, with off1 to off3 for the
+// chequers borne off. It returns false if no net covers the class of the
+// position.
+func NetFeatures(board TanBoard) ([2]map[string]float32, bool) {
+	var ret [2]map[string]float32
+	var anBoard = _TanBoard(board)
+
+	pc := classifyPosition(anBoard, _VARIATION_STANDARD)
+	if _, _, ok := classNets(pc); !ok {
+		return ret, false
+	}
+
+	var arInput = classInputs(anBoard, pc)
+
+	if pc == _CLASS_RACE {
+		for side := 0; side < 2; side++ {
+			afInput := arInput[side*_HALF_RACE_INPUTS:]
+
+			ret[side] = map[string]float32{"crossovers": afInput[_RI_NCROSS]}
+			for k := 0; k < 14; k++ {
+				ret[side][fmt.Sprintf("menOff%d", k+1)] = afInput[_RI_OFF+k]
+			}
+		}
+		return ret, true
+	}
+
+	/* the half inputs of the player on roll come first */
+	var aiHalf = [2]int{_MINPPERPOINT*25*2 + _MORE_INPUTS, _MINPPERPOINT * 25 * 2}
+	var aiOff = aiHalf
+	if pc == _CLASS_CONTACT {
+		/* the contact net takes each side's chequers off with the other's
+		 * half inputs; see calculateContactInputs */
+		aiOff[0], aiOff[1] = aiHalf[1], aiHalf[0]
+	}
+
+	for side := 0; side < 2; side++ {
+		ret[side] = map[string]float32{}
+		for i, name := range aszInputName {
+			var iBlock = aiHalf[side]
+			if i <= _I_OFF3 {
+				iBlock = aiOff[side]
+			}
+			ret[side][name] = arInput[iBlock+i]
+		}
+	}
+
+	return ret, true
+}
@@ -0,0 +1,80 @@
+package gnubg
+
+import (
+	"testing"
+)
+
+func TestNetFeatures(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name   string
+		board  TanBoard
+		want   [2]map[string]float32 // features to check
+		wantN  int                   // features of each side
+		wantOk bool
+	}{
+		{
+			name:  "should name the starting position's features",
+			board: TanBoard{{5: 5, 7: 3, 12: 5, 23: 2}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			want: [2]map[string]float32{
+				{"breakContact": 1, "pipLoss": 0, "p1": 0, "timing": 0.52, "off1": 0},
+				{"breakContact": 1, "pipLoss": 0, "p1": 0, "timing": 0.52, "off1": 0},
+			},
+			wantN:  _MORE_INPUTS,
+			wantOk: true,
+		},
+		{
+			name:  "should put the chequers off with their own side in contact",
+			board: TanBoard{{5: 4, 7: 3, 12: 3, 23: 2}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			want: [2]map[string]float32{
+				{"off1": 1, "off2": 0, "timing": 0.25},
+				{"off1": 0, "off2": 0, "timing": 0.52},
+			},
+			wantN:  _MORE_INPUTS,
+			wantOk: true,
+		},
+		{
+			name:  "should name the crashed features",
+			board: TanBoard{{0: 2, 1: 2, 23: 1}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			want: [2]map[string]float32{
+				{"off1": 1, "off2": 1, "off3": 0, "forwardAnchor": 2},
+				{"off1": 0, "forwardAnchor": 1.0 / 6},
+			},
+			wantN:  _MORE_INPUTS,
+			wantOk: true,
+		},
+		{
+			name:  "should name the race features",
+			board: TanBoard{{5: 3}, {5: 5, 10: 5, 14: 5}},
+			want: [2]map[string]float32{
+				{"menOff12": 1, "menOff11": 0, "crossovers": 0},
+				{"menOff12": 0, "crossovers": 1.5},
+			},
+			wantN:  15,
+			wantOk: true,
+		},
+		{
+			name:  "should have no net for a bearoff",
+			board: TanBoard{{0: 1}, {0: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NetFeatures(tt.board)
+			if ok != tt.wantOk {
+				t.Fatalf("NetFeatures() ok = %v, want %v", ok, tt.wantOk)
+			}
+			for side := 0; side < 2; side++ {
+				if len(got[side]) != tt.wantN {
+					t.Errorf("NetFeatures()[%v] has %v features, want %v", side, len(got[side]), tt.wantN)
+				}
+				for name, want := range tt.want[side] {
+					if v, ok := got[side][name]; !ok || v != want {
+						t.Errorf("NetFeatures()[%v][%v] = %v, want %v", side, name, v, want)
+					}
+				}
+			}
+		})
+	}
+}
@@ -23,6 +23,8 @@ type Move interface {
 	GetProbLose() float32
 	GetProbLoseG() float32
 	GetProbLoseBG() float32
+	GetBoard() TanBoard
+	GetPositionID() string
 }
 
 type EvalInfo struct {
@@ -30,6 +32,9 @@ type EvalInfo struct {
 	Plies   int
 }
 
+// engine-wide default for evaluating with the quantized nets
+var fEngineQuantized bool
+
 func Init(dataDir fs.FS) error {
 	initMatchEquity(dataDir, "met/Kazaross-XG2.xml")
 
@@ -44,7 +49,19 @@ func Destroy() {
 	evalShutdown()
 }
 
-func FindMoves(board TanBoard, dice [2]int, player int, scoreMoves bool, cubeful bool) (MoveList, error) {
+// SetQuantized selects whether the int8 quantized nets are used by default.
+func SetQuantized(quantized bool) {
+	fEngineQuantized = quantized
+}
+
+// IsQuantized reports the engine-wide default set by SetQuantized.
+func IsQuantized() bool {
+	return fEngineQuantized
+}
+
+// FindMoves returns the legal moves of the dice, best first when scored.
+// The cube sets the money or match context the moves are scored in.
+func FindMoves(board TanBoard, dice [2]int, player int, cube Cube, scoreMoves bool, cubeful bool, quantized bool) (MoveList, error) {
 
 	if scoreMoves {
 		var pml = _MoveList{}
@@ -55,24 +72,18 @@ func FindMoves(board TanBoard, dice [2]int, player int, scoreMoves bool, cubeful
 		} else {
 			anBoard = _TanBoard{board[0], board[1]}
 		}
-		var pci = &_CubeInfo{
-			nCube:         1,
-			fCubeOwner:    -1,
-			fMove:         player,
-			nMatchTo:      0,
-			anScore:       [2]int{0, 0},
-			fCrawford:     false,
-			fJacoby:       true,
-			fBeavers:      true,
-			arGammonPrice: [4]float32{0, 0, 0, 0},
-			bgv:           _VARIATION_STANDARD,
+		ci, err := cube.cubeInfo(player)
+		if err != nil {
+			return nil, err
 		}
+		var pci = &ci
 		var pec = &_EvalContext{
 			fCubeful:       cubeful,
 			nPlies:         2,
 			fUsePrune:      true,
 			fDeterministic: true,
 			rNoise:         0,
+			fQuantized:     quantized,
 		}
 		if err := findnSaveBestMoves(&pml, dice[0], dice[1], anBoard, nil, 0, pci, pec, aamf); err != nil {
 			return nil, err
@@ -94,3 +105,113 @@ func FindMoves(board TanBoard, dice [2]int, player int, scoreMoves bool, cubeful
 		return pml, nil
 	}
 }
+
+var aszPositionClass = [_N_CLASSES]string{
+	"over",
+	"hypergammon1",
+	"hypergammon2",
+	"hypergammon3",
+	"bearoff2",
+	"bearoff-ts",
+	"bearoff1",
+	"bearoff-os",
+	"race",
+	"crashed",
+	"contact",
+}
+
+// QuantizedComparison holds the float and quantized 0-ply results for a
+// single position; see CompareQuantized.
+type QuantizedComparison struct {
+	Class     string
+	Float     [5]float32 // win, win gammon, win backgammon, lose gammon, lose backgammon
+	Quantized [5]float32
+	// weight (out of 36) of the rolls with more than one legal move
+	RollWeight int
+	// weight (out of 36) of those rolls where both evaluations pick the same move
+	AgreeWeight int
+	// cubeless equity lost, summed over all 36 rolls, by playing the quantized
+	// choice instead of the float one (as judged by the float net)
+	EquityLoss float32
+}
+
+// CompareQuantized evaluates a position at 0-ply with both the float and the
+// quantized nets, and compares the best move each of them picks for every
+// roll.
+func CompareQuantized(board TanBoard, player int) (QuantizedComparison, error) {
+	var ret QuantizedComparison
+	var tld = _ThreadLocalData{}
+	var anBoard _TanBoard
+	if player == 1 {
+		anBoard = _TanBoard{board[1], board[0]}
+	} else {
+		anBoard = _TanBoard{board[0], board[1]}
+	}
+	var ci = _CubeInfo{}
+	if err := setCubeInfo(&ci, 1, -1, player, 0, [2]int{0, 0}, false, true, true, _VARIATION_STANDARD); err != nil {
+		return ret, err
+	}
+	var ec = _EvalContext{fCubeful: false, nPlies: 0, fDeterministic: true}
+	var ecq = ec
+	ecq.fQuantized = true
+
+	pc := classifyPosition(anBoard, _VARIATION_STANDARD)
+	ret.Class = aszPositionClass[pc]
+
+	if pc == _CLASS_OVER {
+		return ret, nil
+	}
+
+	var ar, arq [_NUM_OUTPUTS]float32
+	if err := evaluatePosition(&tld, nil, anBoard, &ar, &ci, &ec); err != nil {
+		return ret, err
+	}
+	if err := evaluatePosition(&tld, nil, anBoard, &arq, &ci, &ecq); err != nil {
+		return ret, err
+	}
+	copy(ret.Float[:], ar[:])
+	copy(ret.Quantized[:], arq[:])
+
+	for n0 := 1; n0 <= 6; n0++ {
+		for n1 := 1; n1 <= n0; n1++ {
+			var w = 2
+			if n0 == n1 {
+				w = 1
+			}
+
+			var ml, mlq _MoveList
+			generateMoves(&tld, &ml, anBoard, n0, n1, false)
+			if ml.cMoves < 2 {
+				continue
+			}
+			ml.amMoves = append([]_Move(nil), ml.amMoves[:ml.cMoves]...)
+			if err := scoreMoves(&tld, &ml, &ci, &ec, 0); err != nil {
+				return ret, err
+			}
+
+			generateMoves(&tld, &mlq, anBoard, n0, n1, false)
+			mlq.amMoves = append([]_Move(nil), mlq.amMoves[:mlq.cMoves]...)
+			if err := scoreMoves(&tld, &mlq, &ci, &ecq, 0); err != nil {
+				return ret, err
+			}
+
+			ret.RollWeight += w
+
+			keyBest := ml.amMoves[ml.iMoveBest].key
+			keyBestQ := mlq.amMoves[mlq.iMoveBest].key
+			if keyBest.equals(keyBestQ) {
+				ret.AgreeWeight += w
+				continue
+			}
+
+			for i := 0; i < ml.cMoves; i++ {
+				if ml.amMoves[i].key.equals(keyBestQ) {
+					ret.EquityLoss += float32(w) * (ml.rBestScore - ml.amMoves[i].rScore)
+					break
+				}
+			}
+		}
+	}
+
+	return ret, nil
+}
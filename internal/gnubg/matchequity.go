@@ -44,32 +44,32 @@ const (
 	_DTL  int = 6
 	_NDLG int = 6
 	_NDLB int = 7
-	_DTLG int = 7
-	_DTLB int = 8
+	_DTLG int = 8
+	_DTLB int = 9
 	/* player 0 wins, 2nd cube value */
-	_DPP0   int = 9
-	_DTWP0  int = 10
-	_NDWBP0 int = 11
-	_DTWGP0 int = 12
-	_DTWBP0 int = 13
+	_DPP0   int = 10
+	_DTWP0  int = 11
+	_NDWBP0 int = 12
+	_DTWGP0 int = 13
+	_DTWBP0 int = 14
 	/* player 0 loses, 2nd cube value */
-	_NDLP0  int = 14
-	_DTLP0  int = 15
-	_NDLBP0 int = 16
-	_DTLGP0 int = 17
-	_DTLBP0 int = 18
+	_NDLP0  int = 15
+	_DTLP0  int = 16
+	_NDLBP0 int = 17
+	_DTLGP0 int = 18
+	_DTLBP0 int = 19
 	/* player 0 wins, 3rd cube value */
-	_DPP1   int = 19
-	_DTWP1  int = 20
-	_NDWBP1 int = 21
-	_DTWGP1 int = 22
-	_DTWBP1 int = 23
+	_DPP1   int = 20
+	_DTWP1  int = 21
+	_NDWBP1 int = 22
+	_DTWGP1 int = 23
+	_DTWBP1 int = 24
 	/* player 0 loses, 3rd cube value */
-	_NDLP1  int = 24
-	_DTLP1  int = 25
-	_NDLBP1 int = 26
-	_DTLGP1 int = 27
-	_DTLBP1 int = 28
+	_NDLP1  int = 25
+	_DTLP1  int = 26
+	_NDLBP1 int = 27
+	_DTLGP1 int = 28
+	_DTLBP1 int = 29
 )
 
 // var miCurrent *met.METInfo
@@ -611,7 +611,7 @@ func getMEMultiple(nScore0 int, nScore1 int, nMatchTo int,
 
 }
 
-func getPoints(arOutput *[5]float32, pci *_CubeInfo, arCP [2]float32) int {
+func getPoints(arOutput *[5]float32, pci *_CubeInfo, arCP *[2]float32) int {
 
 	/*
 	 * Input:
@@ -738,6 +738,15 @@ func getPoints(arOutput *[5]float32, pci *_CubeInfo, arCP [2]float32) int {
 
 				rDP = aarMETResults[k][_DP]
 
+				if k > 0 {
+					i1 = _DTWP1
+					i2 = _DTWGP1
+					i3 = _DTWBP1
+				} else {
+					i1 = _DTWP0
+					i2 = _DTWGP0
+					i3 = _DTWBP0
+				}
 				rDTW = (1.0-arG[k]-arBG[k])*aarMETResults[k][i1] + arG[k]*aarMETResults[k][i2] + arBG[k]*aarMETResults[k][i3]
 
 				arCPDead[k][n] = (rDTL - rDP) / (rDTL - rDTW)
@@ -3,6 +3,7 @@ package gnubg
 import (
 	"bgweb-api/internal/gnubg/met"
 	"io/fs"
+	"math"
 	"os"
 	"testing"
 )
@@ -65,3 +66,70 @@ func Test_initMatchEquity(t *testing.T) {
 		})
 	}
 }
+
+func Test_getMEMultiple(t *testing.T) {
+	once.Do(setup)
+
+	/* 9-point match at 0-0: each result moves player 0 or 1 from 8-away,
+	 * counted from 0, by 1, 2, 3, 4 or 6 times the cube */
+	var p0, p1 [_DTLBP1 + 1]float32
+	getMEMultiple(0, 0, 9, 1, 2, 2, false, &aafMET, &aafMETPostCrawford, p0[:], p1[:])
+
+	tests := []struct {
+		name  string
+		index int
+		want  float32
+	}{
+		{"_DP", _DP, aafMET[7][8]},
+		{"_DTW", _DTW, aafMET[6][8]},
+		{"_NDWB", _NDWB, aafMET[5][8]},
+		{"_DTWG", _DTWG, aafMET[4][8]},
+		{"_DTWB", _DTWB, aafMET[2][8]},
+		{"_NDL", _NDL, aafMET[8][7]},
+		{"_DTL", _DTL, aafMET[8][6]},
+		{"_NDLB", _NDLB, aafMET[8][5]},
+		{"_DTLG", _DTLG, aafMET[8][4]},
+		{"_DTLB", _DTLB, aafMET[8][2]},
+		{"_DPP0", _DPP0, aafMET[6][8]},
+		{"_DTWP0", _DTWP0, aafMET[4][8]},
+		{"_DTWGP0", _DTWGP0, aafMET[0][8]},
+		{"_DTLP0", _DTLP0, aafMET[8][4]},
+		{"_DTLGP0", _DTLGP0, aafMET[8][0]},
+		{"_DTWP1", _DTWP1, aafMET[4][8]},
+		{"_DTLBP1", _DTLBP1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p0[tt.index]; got != tt.want {
+				t.Errorf("getMEMultiple()[%v] = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getPoints(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name     string
+		arOutput [5]float32
+	}{
+		{"should cash gammonless games", [5]float32{0.6, 0, 0, 0, 0}},
+		{"should cash games with gammons", [5]float32{0.6, 0.2, 0.01, 0.15, 0.01}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* at 2-away 2-away the cube is dead once doubled, and the
+			 * cash point is the equity of leading 1-away 2-away at
+			 * Crawford in gnubg's Kazaross-XG2 table */
+			var ci = _CubeInfo{nCube: 1, fCubeOwner: -1, nMatchTo: 2}
+			var arCP [2]float32
+			getPoints(&tt.arOutput, &ci, &arCP)
+			for i, cp := range arCP {
+				if math.Abs(float64(cp)-0.67736) > 1e-5 {
+					t.Errorf("getPoints() cash point %v = %v, want 0.67736", i, cp)
+				}
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package gnubg
 
+import (
+	"encoding/base64"
+	"fmt"
+)
+
 /*
  * Calculate log2 of Cube value.
  *
@@ -19,3 +24,112 @@ func logCube(n int) int {
 
 	return i
 }
+
+/* game states in a match ID */
+const (
+	_GAME_NONE = iota
+	_GAME_PLAYING
+	_GAME_OVER
+	_GAME_RESIGNED
+	_GAME_DROP
+)
+
+func matchIDFromKey(auchKey [9]byte) string {
+	return base64.RawStdEncoding.EncodeToString(auchKey[:])
+}
+
+func matchID(anDice [2]int, fTurn int, fResigned int, fDoubled bool, fMove int, fCubeOwner int, fCrawford bool, nMatchTo int, anScore [2]int, nCube int, fJacoby bool, gs int) string {
+	var auchKey [9]byte
+
+	addBits(auchKey[:], 0, 4, logCube(nCube))
+	addBits(auchKey[:], 4, 2, fCubeOwner&0x3)
+	addBits(auchKey[:], 6, 1, fMove)
+	addBits(auchKey[:], 7, 1, toInt(fCrawford))
+	addBits(auchKey[:], 8, 3, gs)
+	addBits(auchKey[:], 11, 1, fTurn)
+	addBits(auchKey[:], 12, 1, toInt(fDoubled))
+	addBits(auchKey[:], 13, 2, fResigned)
+	addBits(auchKey[:], 15, 3, anDice[0])
+	addBits(auchKey[:], 18, 3, anDice[1])
+	addBits(auchKey[:], 21, 15, nMatchTo&0x7fff)
+	addBits(auchKey[:], 36, 15, anScore[0]&0x7fff)
+	addBits(auchKey[:], 51, 15, anScore[1]&0x7fff)
+	addBits(auchKey[:], 66, 1, toInt(!fJacoby))
+
+	return matchIDFromKey(auchKey)
+}
+
+func toInt(f bool) int {
+	if f {
+		return 1
+	}
+	return 0
+}
+
+// MatchState is what a gnubg match ID holds. Player 0 is o and player 1
+// is x; scores are indexed the same way.
+type MatchState struct {
+	Cube Cube
+	// player on roll, and player to act, who differs while a double or
+	// resignation is pending
+	Move int
+	Turn int
+	Dice [2]int // 0 before rolling
+	// double offered, and resignation offered (1 single game, 2 gammon,
+	// 3 backgammon)
+	Doubled  bool
+	Resigned int
+	// 0 no game, 1 playing, 2 over, 3 resigned, 4 dropped
+	GameState int
+}
+
+// MatchID returns the gnubg match ID of a match state.
+func MatchID(ms MatchState) string {
+	return matchID(ms.Dice, ms.Turn, ms.Resigned, ms.Doubled, ms.Move, ms.Cube.Owner, ms.Cube.Crawford, ms.Cube.MatchTo, ms.Cube.Score, ms.Cube.Value, ms.Cube.Jacoby, ms.GameState)
+}
+
+// MatchFromID decodes a gnubg match ID.
+func MatchFromID(id string) (MatchState, error) {
+	var ms MatchState
+	var auchKey [9]byte
+
+	if len(id) != 12 {
+		return ms, fmt.Errorf("a match ID has 12 characters")
+	}
+	if _, err := base64.RawStdEncoding.Strict().Decode(auchKey[:], []byte(id)); err != nil {
+		return ms, fmt.Errorf("invalid match ID: %v", err)
+	}
+
+	ms.Cube.Value = 1 << getBits(auchKey[:], 0, 4)
+	ms.Cube.Owner = getBits(auchKey[:], 4, 2)
+	ms.Move = getBits(auchKey[:], 6, 1)
+	ms.Cube.Crawford = getBits(auchKey[:], 7, 1) != 0
+	ms.GameState = getBits(auchKey[:], 8, 3)
+	ms.Turn = getBits(auchKey[:], 11, 1)
+	ms.Doubled = getBits(auchKey[:], 12, 1) != 0
+	ms.Resigned = getBits(auchKey[:], 13, 2)
+	ms.Dice[0] = getBits(auchKey[:], 15, 3)
+	ms.Dice[1] = getBits(auchKey[:], 18, 3)
+	ms.Cube.MatchTo = getBits(auchKey[:], 21, 15)
+	ms.Cube.Score[0] = getBits(auchKey[:], 36, 15)
+	ms.Cube.Score[1] = getBits(auchKey[:], 51, 15)
+	ms.Cube.Jacoby = getBits(auchKey[:], 66, 1) == 0 /* ignored in match play */
+
+	if ms.Cube.Owner == 3 {
+		/* centred */
+		ms.Cube.Owner = -1
+	}
+
+	switch {
+	case ms.Cube.Owner == 2:
+		return ms, fmt.Errorf("invalid match ID: cube owner")
+	case ms.GameState > _GAME_DROP:
+		return ms, fmt.Errorf("invalid match ID: game state")
+	case ms.Dice[0] > 6 || ms.Dice[1] > 6 || (ms.Dice[0] == 0) != (ms.Dice[1] == 0):
+		return ms, fmt.Errorf("invalid match ID: dice")
+	case ms.Cube.MatchTo > 0 && (ms.Cube.Score[0] >= ms.Cube.MatchTo || ms.Cube.Score[1] >= ms.Cube.MatchTo) && ms.GameState == _GAME_PLAYING:
+		return ms, fmt.Errorf("invalid match ID: score")
+	}
+
+	return ms, nil
+}
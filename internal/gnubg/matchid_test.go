@@ -29,3 +29,56 @@ func Test_logCube(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    MatchState
+		wantErr bool
+	}{
+		{
+			name: "should decode a money game",
+			id:   "cAkAAAAAAAAA",
+			want: MatchState{Cube: Cube{Value: 1, Owner: -1, Jacoby: true}, Move: 1, Turn: 1, GameState: 1},
+		},
+		{
+			name: "should decode a match from the gnubg manual",
+			id:   "QYkqASAAIAAA",
+			want: MatchState{Cube: Cube{Value: 2, Owner: 0, MatchTo: 9, Score: [2]int{2, 4}, Jacoby: true}, Move: 1, Turn: 1, Dice: [2]int{5, 2}, GameState: 1},
+		},
+		{
+			name: "should decode a pending double",
+			id:   "cBEAAAAAAAAA",
+			want: MatchState{Cube: Cube{Value: 1, Owner: -1, Jacoby: true}, Move: 1, Turn: 0, Doubled: true, GameState: 1},
+		},
+		{
+			name:    "should fail on the wrong length",
+			id:      "cAkAAAAAAAA",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on bad dice",
+			id:      "cAn/AAAAAAAA",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchFromID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MatchFromID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("MatchFromID() = %+v, want %+v", got, tt.want)
+			}
+			if id := MatchID(got); id != tt.id {
+				t.Errorf("MatchID() = %v, want %v", id, tt.id)
+			}
+		})
+	}
+}
@@ -36,3 +36,7 @@ func Max(x float32, y float32) float32 {
 func Irand() int {
 	return rand.Int()
 }
+
+func Tanhf(x float32) float32 {
+	return float32(math.Tanh(float64(x)))
+}
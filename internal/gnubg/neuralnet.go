@@ -1,13 +1,42 @@
 package gnubg
 
 import (
+	"bgweb-api/internal/gnubg/math32"
 	"bgweb-api/internal/gnubg/sigmoid"
 	"fmt"
-	"io/fs"
+	"io"
+	"math"
+	"math/rand"
 )
 
 const _WEIGHTS_VERSION = "1.00"
 
+/* Same as 1.00, but every net also lists its activation functions and
+ * any hidden layers after the first one */
+const _WEIGHTS_VERSION_LAYERS = "1.01"
+
+type _NNActivation int
+
+const (
+	_NNACT_SIGMOID _NNActivation = iota
+	_NNACT_TANH
+	_NNACT_RELU
+	_NNACT_LINEAR
+	_NNACT_COUNT
+)
+
+var aszNNActivation = [_NNACT_COUNT]string{"sigmoid", "tanh", "relu", "linear"}
+
+/* A hidden layer after the first one. Weights are stored
+ * [node][node of the previous layer], the same way as arOutputWeight. */
+type _NNLayer struct {
+	cNodes      int
+	act         _NNActivation
+	rBeta       float32
+	arWeight    []float32
+	arThreshold []float32
+}
+
 type _NeuralNet struct {
 	cInput            int
 	cHidden           int
@@ -16,9 +45,12 @@ type _NeuralNet struct {
 	rBetaHidden       float32
 	rBetaOutput       float32
 	arHiddenWeight    []float32
-	arOutputWeight    []float32
+	arOutputWeight    []float32 /* [output][node of the last hidden layer] */
 	arHiddenThreshold []float32
 	arOutputThreshold []float32
+	actHidden         _NNActivation /* first hidden layer */
+	actOutput         _NNActivation
+	aLayer            []_NNLayer /* hidden layers after the first, if any */
 }
 
 type _NNEvalType int
@@ -57,22 +89,126 @@ func neuralNetCreate(pnn *_NeuralNet, cInput int, cHidden int, cOutput int, rBet
 	pnn.arOutputThreshold = make([]float32, cOutput)
 }
 
+/* Append a hidden layer after the current last one. The output weights
+ * are reallocated to match the new last layer. */
+func neuralNetAddLayer(pnn *_NeuralNet, cNodes int, act _NNActivation, rBeta float32) {
+	pnn.aLayer = append(pnn.aLayer, _NNLayer{
+		cNodes:      cNodes,
+		act:         act,
+		rBeta:       rBeta,
+		arWeight:    make([]float32, cNodes*neuralNetLastHidden(pnn)),
+		arThreshold: make([]float32, cNodes),
+	})
+	pnn.arOutputWeight = make([]float32, pnn.cOutput*cNodes)
+}
+
+/* number of nodes in the layer feeding the output layer */
+func neuralNetLastHidden(pnn *_NeuralNet) int {
+	if n := len(pnn.aLayer); n > 0 {
+		return pnn.aLayer[n-1].cNodes
+	}
+	return pnn.cHidden
+}
+
 func neuralNetDestroy(pnn *_NeuralNet) {
 	pnn.arHiddenWeight = nil
 	pnn.arOutputWeight = nil
 	pnn.arHiddenThreshold = nil
 	pnn.arOutputThreshold = nil
+	pnn.aLayer = nil
 }
 
-func verifyWeights(pf fs.File, szFilename string) error {
+func verifyWeights(pf io.Reader, szFilename string) error {
+	_, err := readWeightsVersion(pf, szFilename)
+	return err
+}
+
+/* Reads the weights file header and returns its version */
+func readWeightsVersion(pf io.Reader, szFilename string) (string, error) {
 	var file_version string
 	if n, err := fmt.Fscanf(pf, "GNU Backgammon %15s\n", &file_version); n != 1 || err != nil {
-		return fmt.Errorf("%v is not a weights file: %v/%v", szFilename, n, err)
+		return "", fmt.Errorf("%v is not a weights file: %v/%v", szFilename, n, err)
 	}
-	if file_version != _WEIGHTS_VERSION {
-		return fmt.Errorf("weights file %v, has incorrect version (%v), expected (%v)", szFilename, file_version, _WEIGHTS_VERSION)
+	if file_version != _WEIGHTS_VERSION && file_version != _WEIGHTS_VERSION_LAYERS {
+		return "", fmt.Errorf("weights file %v, has incorrect version (%v), expected (%v) or (%v)", szFilename, file_version, _WEIGHTS_VERSION, _WEIGHTS_VERSION_LAYERS)
+	}
+	return file_version, nil
+}
+
+func parseActivation(sz string) (_NNActivation, error) {
+	for i, szAct := range aszNNActivation {
+		if sz == szAct {
+			return _NNActivation(i), nil
+		}
+	}
+	return _NNACT_SIGMOID, fmt.Errorf("unknown activation function: %v", sz)
+}
+
+/* apply the activation function of a layer to its nodes */
+func activate(act _NNActivation, rBeta float32, ar []float32) {
+	switch act {
+	case _NNACT_SIGMOID:
+		for i := range ar {
+			ar[i] = sigmoid.Sigmoid(-rBeta * ar[i])
+		}
+	default:
+		for i := range ar {
+			ar[i] = activation(act, rBeta, ar[i])
+		}
+	}
+}
+
+func activation(act _NNActivation, rBeta float32, r float32) float32 {
+	switch act {
+	case _NNACT_TANH:
+		return math32.Tanhf(rBeta * r)
+	case _NNACT_RELU:
+		return math32.Max(0.0, rBeta*r)
+	case _NNACT_LINEAR:
+		return rBeta * r
+	default:
+		return sigmoid.Sigmoid(-rBeta * r)
+	}
+}
+
+/* Everything after the sums at the first hidden layer: ar holds these
+ * sums (thresholds included) on entry, and is overwritten. */
+func evaluateLayers(pnn *_NeuralNet, ar []float32, arOutput *[_NUM_OUTPUTS]float32) {
+	activate(pnn.actHidden, pnn.rBetaHidden, ar)
+
+	/* Calculate activity at any further hidden layers */
+	for _, l := range pnn.aLayer {
+		arNext := make([]float32, l.cNodes)
+		prWeight := l.arWeight
+
+		for i := 0; i < l.cNodes; i++ {
+			r := l.arThreshold[i]
+
+			for j := range ar {
+				r += ar[j] * prWeight[j]
+			}
+			prWeight = prWeight[len(ar):]
+
+			arNext[i] = r
+		}
+
+		activate(l.act, l.rBeta, arNext)
+		ar = arNext
+	}
+
+	/* Calculate activity at output nodes */
+	prWeight := pnn.arOutputWeight
+
+	for i := 0; i < pnn.cOutput; i++ {
+		r := pnn.arOutputThreshold[i]
+
+		for j := range ar {
+			r += ar[j] * prWeight[j]
+		}
+		prWeight = prWeight[len(ar):]
+
+		arOutput[i] = activation(pnn.actOutput, pnn.rBetaOutput, r)
 	}
-	return nil
 }
 
 func neuralNetEvaluate(pnn *_NeuralNet, arInput *[_NUM_INPUTS]float32, arOutput *[_NUM_OUTPUTS]float32, pnState *_NNState) error {
@@ -194,21 +330,7 @@ func evaluate(pnn *_NeuralNet, arInput *[_NUM_INPUTS]float32, ar []float32, arOu
 		copy(saveAr, ar)
 	}
 
-	for i := 0; i < cHidden; i++ {
-		ar[i] = sigmoid.Sigmoid(-pnn.rBetaHidden * ar[i])
-	}
-	/* Calculate activity at output nodes */
-	prWeight = pnn.arOutputWeight[:]
-
-	for i := 0; i < pnn.cOutput; i++ {
-		r := pnn.arOutputThreshold[i]
-
-		for j := 0; j < cHidden; j++ {
-			r += ar[j] * prWeight[0]
-			prWeight = prWeight[1:]
-		}
-		arOutput[i] = sigmoid.Sigmoid(-pnn.rBetaOutput * r)
-	}
+	evaluateLayers(pnn, ar, arOutput)
 }
 
 func evaluateFromBase(pnn *_NeuralNet, arInputDif *[_NUM_INPUTS]float32, ar []float32, arOutput *[_NUM_OUTPUTS]float32) {
@@ -251,26 +373,24 @@ func evaluateFromBase(pnn *_NeuralNet, arInputDif *[_NUM_INPUTS]float32, ar []fl
 		}
 	}
 
-	for i := 0; i < pnn.cHidden; i++ {
-		ar[i] = sigmoid.Sigmoid(-pnn.rBetaHidden * ar[i])
-	}
-
-	/* Calculate activity at output nodes */
-	prWeight = pnn.arOutputWeight[:]
-
-	for i := 0; i < pnn.cOutput; i++ {
-		r := pnn.arOutputThreshold[i]
-
-		for j := 0; j < pnn.cHidden; j++ {
-			r += ar[j] * prWeight[0]
-			prWeight = prWeight[1:]
-		}
+	evaluateLayers(pnn, ar, arOutput)
+}
 
-		arOutput[i] = sigmoid.Sigmoid(-pnn.rBetaOutput * r)
-	}
+func neuralNetLoad(pnn *_NeuralNet, pf io.Reader) error {
+	return neuralNetLoadVersion(pnn, pf, _WEIGHTS_VERSION)
 }
 
-func neuralNetLoad(pnn *_NeuralNet, pf fs.File) error {
+/* Load the next net from a weights file of the given version.
+ *
+ * A 1.01 net has, after the usual header line,
+ *
+ *   activation <first hidden layer> <output layer>
+ *   layers <number of further hidden layers>
+ *   <nodes> <activation> <beta>     (one line per further hidden layer)
+ *
+ * and the weights and thresholds of each further hidden layer are stored
+ * right after the first hidden layer's weights. */
+func neuralNetLoadVersion(pnn *_NeuralNet, pf io.Reader, szVersion string) error {
 	var dummy string
 
 	items, err := fmt.Fscanf(pf, "%d %d %d %s %f %f\n", &pnn.cInput, &pnn.cHidden, &pnn.cOutput, &dummy, &pnn.rBetaHidden, &pnn.rBetaOutput)
@@ -284,6 +404,44 @@ func neuralNetLoad(pnn *_NeuralNet, pf fs.File) error {
 	neuralNetCreate(pnn, pnn.cInput, pnn.cHidden, pnn.cOutput, pnn.rBetaHidden, pnn.rBetaOutput)
 
 	pnn.nTrained = 1
+	pnn.actHidden = _NNACT_SIGMOID
+	pnn.actOutput = _NNACT_SIGMOID
+	pnn.aLayer = nil
+
+	if szVersion == _WEIGHTS_VERSION_LAYERS {
+		var szHidden, szOutput string
+		var cLayers int
+
+		if n, err := fmt.Fscanf(pf, "activation %s %s\n", &szHidden, &szOutput); n < 2 || err != nil {
+			return fmt.Errorf("invalid neural net file: %v/%v", n, err)
+		}
+		if pnn.actHidden, err = parseActivation(szHidden); err != nil {
+			return err
+		}
+		if pnn.actOutput, err = parseActivation(szOutput); err != nil {
+			return err
+		}
+
+		if n, err := fmt.Fscanf(pf, "layers %d\n", &cLayers); n < 1 || err != nil || cLayers < 0 {
+			return fmt.Errorf("invalid neural net file: %v/%v", n, err)
+		}
+
+		for i := 0; i < cLayers; i++ {
+			var cNodes int
+			var szAct string
+			var rBeta float32
+
+			if n, err := fmt.Fscanf(pf, "%d %s %f\n", &cNodes, &szAct, &rBeta); n < 3 || err != nil || cNodes < 1 {
+				return fmt.Errorf("invalid neural net file: %v/%v", n, err)
+			}
+			act, err := parseActivation(szAct)
+			if err != nil {
+				return err
+			}
+
+			neuralNetAddLayer(pnn, cNodes, act, rBeta)
+		}
+	}
 
 	scan := func(ar []float32, len int) error {
 		for i, pr := len, 0; i > 0; i, pr = i-1, pr+1 {
@@ -298,7 +456,20 @@ func neuralNetLoad(pnn *_NeuralNet, pf fs.File) error {
 		return err
 	}
 
-	if err := scan(pnn.arOutputWeight, pnn.cHidden*pnn.cOutput); err != nil {
+	cPrev := pnn.cHidden
+	for i := range pnn.aLayer {
+		l := &pnn.aLayer[i]
+
+		if err := scan(l.arWeight, l.cNodes*cPrev); err != nil {
+			return err
+		}
+		if err := scan(l.arThreshold, l.cNodes); err != nil {
+			return err
+		}
+		cPrev = l.cNodes
+	}
+
+	if err := scan(pnn.arOutputWeight, cPrev*pnn.cOutput); err != nil {
 		return err
 	}
 
@@ -312,3 +483,203 @@ func neuralNetLoad(pnn *_NeuralNet, pf fs.File) error {
 
 	return nil
 }
+
+/* Fill a freshly created net with small random weights, scaled to the
+ * number of inputs of each layer so the sums start out in the useful
+ * range of the activation functions. Thresholds start at zero. */
+func neuralNetRandomize(pnn *_NeuralNet, rnd *rand.Rand) {
+	randomize := func(ar []float32, cIn int, rBeta float32) {
+		r := float32(math.Sqrt(3.0/float64(cIn))) / rBeta
+		for i := range ar {
+			ar[i] = (2*rnd.Float32() - 1) * r
+		}
+	}
+
+	randomize(pnn.arHiddenWeight, pnn.cInput, pnn.rBetaHidden)
+	cPrev := pnn.cHidden
+	for _, l := range pnn.aLayer {
+		randomize(l.arWeight, cPrev, l.rBeta)
+		cPrev = l.cNodes
+	}
+	randomize(pnn.arOutputWeight, cPrev, pnn.rBetaOutput)
+}
+
+/* derivative of an activation function, given its output */
+func activationDerivative(act _NNActivation, rBeta float32, y float32) float32 {
+	switch act {
+	case _NNACT_TANH:
+		return rBeta * (1 - y*y)
+	case _NNACT_RELU:
+		if y > 0 {
+			return rBeta
+		}
+		return 0
+	case _NNACT_LINEAR:
+		return rBeta
+	default:
+		return rBeta * y * (1 - y)
+	}
+}
+
+/* One step of stochastic gradient descent (backpropagation) towards
+ * arTarget, minimising half the squared error of the outputs. Returns the
+ * squared error before the step. */
+func neuralNetTrain(pnn *_NeuralNet, arInput []float32, arTarget []float32, rAlpha float32) float32 {
+	/* forward pass, keeping the output of every layer */
+	aar := make([][]float32, 0, len(pnn.aLayer)+2)
+
+	ar := make([]float32, pnn.cHidden)
+	copy(ar, pnn.arHiddenThreshold)
+	for i := 0; i < pnn.cInput; i++ {
+		if x := arInput[i]; x != 0.0 {
+			prWeight := pnn.arHiddenWeight[i*pnn.cHidden : (i+1)*pnn.cHidden]
+			for j, w := range prWeight {
+				ar[j] += w * x
+			}
+		}
+	}
+	activate(pnn.actHidden, pnn.rBetaHidden, ar)
+	aar = append(aar, ar)
+
+	for _, l := range pnn.aLayer {
+		arNext := make([]float32, l.cNodes)
+		for i := range arNext {
+			r := l.arThreshold[i]
+			for j, w := range l.arWeight[i*len(ar) : (i+1)*len(ar)] {
+				r += ar[j] * w
+			}
+			arNext[i] = r
+		}
+		activate(l.act, l.rBeta, arNext)
+		ar = arNext
+		aar = append(aar, ar)
+	}
+
+	arOutput := make([]float32, pnn.cOutput)
+	for i := range arOutput {
+		r := pnn.arOutputThreshold[i]
+		for j, w := range pnn.arOutputWeight[i*len(ar) : (i+1)*len(ar)] {
+			r += ar[j] * w
+		}
+		arOutput[i] = activation(pnn.actOutput, pnn.rBetaOutput, r)
+	}
+
+	/* error terms at the output layer */
+	var rError float32
+	arDelta := make([]float32, pnn.cOutput)
+	for i, y := range arOutput {
+		e := y - arTarget[i]
+		rError += e * e
+		arDelta[i] = e * activationDerivative(pnn.actOutput, pnn.rBetaOutput, y)
+	}
+
+	/* backward pass; each layer propagates its error terms to the
+	 * previous one before its own weights are updated */
+	backward := func(arWeight []float32, arThreshold []float32, arDelta []float32, arIn []float32, act _NNActivation, rBeta float32) []float32 {
+		arDeltaIn := make([]float32, len(arIn))
+		for i, d := range arDelta {
+			prWeight := arWeight[i*len(arIn) : (i+1)*len(arIn)]
+			for j := range arIn {
+				arDeltaIn[j] += d * prWeight[j]
+				prWeight[j] -= rAlpha * d * arIn[j]
+			}
+			arThreshold[i] -= rAlpha * d
+		}
+		for j, y := range arIn {
+			arDeltaIn[j] *= activationDerivative(act, rBeta, y)
+		}
+		return arDeltaIn
+	}
+
+	for k := len(pnn.aLayer); k >= 0; k-- {
+		arIn := aar[k]
+
+		var act _NNActivation
+		var rBeta float32
+		if k == 0 {
+			act, rBeta = pnn.actHidden, pnn.rBetaHidden
+		} else {
+			act, rBeta = pnn.aLayer[k-1].act, pnn.aLayer[k-1].rBeta
+		}
+
+		if k == len(pnn.aLayer) {
+			arDelta = backward(pnn.arOutputWeight, pnn.arOutputThreshold, arDelta, arIn, act, rBeta)
+		} else {
+			l := &pnn.aLayer[k]
+			arDelta = backward(l.arWeight, l.arThreshold, arDelta, arIn, act, rBeta)
+		}
+	}
+
+	/* first hidden layer, [input][hidden] layout */
+	for i := 0; i < pnn.cInput; i++ {
+		if x := arInput[i]; x != 0.0 {
+			prWeight := pnn.arHiddenWeight[i*pnn.cHidden : (i+1)*pnn.cHidden]
+			for j, d := range arDelta {
+				prWeight[j] -= rAlpha * d * x
+			}
+		}
+	}
+	for j, d := range arDelta {
+		pnn.arHiddenThreshold[j] -= rAlpha * d
+	}
+
+	pnn.nTrained++
+
+	return rError
+}
+
+/* Write a net the way neuralNetLoadVersion reads it. Nets with more than
+ * one hidden layer, or anything but sigmoid activations, need version
+ * 1.01. */
+func neuralNetSave(pnn *_NeuralNet, w io.Writer, szVersion string) error {
+	if szVersion == _WEIGHTS_VERSION && neuralNetNeedsLayers(pnn) {
+		return fmt.Errorf("net needs weights file version %v", _WEIGHTS_VERSION_LAYERS)
+	}
+
+	if _, err := fmt.Fprintf(w, "%d %d %d %d %v %v\n", pnn.cInput, pnn.cHidden, pnn.cOutput, pnn.nTrained, pnn.rBetaHidden, pnn.rBetaOutput); err != nil {
+		return err
+	}
+
+	if szVersion == _WEIGHTS_VERSION_LAYERS {
+		if _, err := fmt.Fprintf(w, "activation %v %v\nlayers %d\n", aszNNActivation[pnn.actHidden], aszNNActivation[pnn.actOutput], len(pnn.aLayer)); err != nil {
+			return err
+		}
+		for _, l := range pnn.aLayer {
+			if _, err := fmt.Fprintf(w, "%d %v %v\n", l.cNodes, aszNNActivation[l.act], l.rBeta); err != nil {
+				return err
+			}
+		}
+	}
+
+	write := func(ar []float32) error {
+		for _, r := range ar {
+			if _, err := fmt.Fprintf(w, "%v\n", r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := write(pnn.arHiddenWeight); err != nil {
+		return err
+	}
+	for _, l := range pnn.aLayer {
+		if err := write(l.arWeight); err != nil {
+			return err
+		}
+		if err := write(l.arThreshold); err != nil {
+			return err
+		}
+	}
+	if err := write(pnn.arOutputWeight); err != nil {
+		return err
+	}
+	if err := write(pnn.arHiddenThreshold); err != nil {
+		return err
+	}
+	return write(pnn.arOutputThreshold)
+}
+
+func neuralNetNeedsLayers(pnn *_NeuralNet) bool {
+	return len(pnn.aLayer) > 0 || pnn.actHidden != _NNACT_SIGMOID || pnn.actOutput != _NNACT_SIGMOID
+}
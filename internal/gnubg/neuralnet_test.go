@@ -1,8 +1,10 @@
 package gnubg
 
 import (
+	"math"
 	"os"
 	"testing"
+	"testing/fstest"
 )
 
 func openFile(filename string) *os.File {
@@ -134,3 +136,80 @@ func Test_neuralNetEvaluate(t *testing.T) {
 		})
 	}
 }
+
+func Test_neuralNetLoadVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		input   [2]float32
+		want    float32
+		wantErr bool
+	}{
+		{
+			name: "should load 1.00",
+			file: "GNU Backgammon 1.00\n" +
+				"2 2 1 s 1 1\n" +
+				"1\n0\n0\n1\n" + // hidden weights
+				"1\n1\n" + // output weights
+				"0\n0\n" + // hidden thresholds
+				"-1\n", // output threshold
+			input: [2]float32{0, 0},
+			want:  0.5, // hidden nodes at 0.5, output at 0
+		},
+		{
+			name: "should load 1.01 with extra layer",
+			file: "GNU Backgammon 1.01\n" +
+				"2 2 1 s 1 1\n" +
+				"activation linear sigmoid\n" +
+				"layers 1\n" +
+				"2 relu 1\n" +
+				"1\n0\n0\n1\n" + // hidden weights
+				"1\n1\n-1\n1\n" + // extra layer weights
+				"0\n0\n" + // extra layer thresholds
+				"1\n1\n" + // output weights
+				"0\n0\n" + // hidden thresholds
+				"0\n", // output threshold
+			input: [2]float32{1, 2},
+			want:  0.982, // relu(1+2) + relu(-1+2) = 4
+		},
+		{
+			name: "should fail on unknown activation",
+			file: "GNU Backgammon 1.01\n" +
+				"2 2 1 s 1 1\n" +
+				"activation softmax sigmoid\n",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on unknown version",
+			file:    "GNU Backgammon 1.02\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"test.weights": {Data: []byte(tt.file)}}
+			pf, _ := fsys.Open("test.weights")
+			defer pf.Close()
+			var pnn _NeuralNet
+			szVersion, err := readWeightsVersion(pf, "test.weights")
+			if err == nil {
+				err = neuralNetLoadVersion(&pnn, pf, szVersion)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("neuralNetLoadVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var arInput [_NUM_INPUTS]float32
+			var arOutput [_NUM_OUTPUTS]float32
+			copy(arInput[:], tt.input[:])
+			if err := neuralNetEvaluate(&pnn, &arInput, &arOutput, nil); err != nil {
+				t.Fatalf("neuralNetEvaluate() error = %v", err)
+			}
+			if math.Abs(float64(arOutput[0]-tt.want)) > 0.001 {
+				t.Errorf("neuralNetEvaluate() = %v, want %v", arOutput[0], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package gnubg
+
+import (
+	"math"
+	"sort"
+)
+
+/* Inputs other than 0 and 1 are fed to the integer path in fixed point
+ * with 12 fractional bits. All net inputs are small non-negative numbers
+ * (at most 6.0 for 15 chequers on a point). */
+const _QINPUT_SHIFT = 12
+const _QINPUT_ONE = 1 << _QINPUT_SHIFT
+
+/* Packed weights are stored as w + 128 in 16 bit lanes, so at most
+ * 65535 / 255 = 257 inputs of 1 can be summed before a lane overflows.
+ * When multiplied by another input they are moved to 32 bit lanes, which
+ * hold the sum for inputs adding up to 4294967295 / 255 / _QINPUT_ONE,
+ * i.e. 4112. Both are well above what 250 inputs can reach. */
+const _QPACK_BIAS = 128
+
+/* Quantized copy of a _NeuralNet.
+ *
+ * Every hidden node ("row" of the weight matrix) has its incoming weights
+ * stored as int8 together with a single float scale, so that
+ * weight = aiWeight * arScale. This is where nearly all the work is
+ * (cInput * cHidden multiply-adds).
+ *
+ * The gnubg nets have a few very large weights per hidden node (up to
+ * 200 against a typical magnitude of 1-5) which would make the int8 steps
+ * far too coarse for the rest of the row. The largest 1% of each row are
+ * therefore taken out of the int8 matrix and kept as float outliers.
+ *
+ * Any further layers only have a fraction of the weights but are very
+ * sensitive to rounding, so they are evaluated in float from the original
+ * net, as are the thresholds. */
+type _NeuralNetQ struct {
+	pnn            *_NeuralNet
+	cInput         int
+	cHidden        int
+	aiHiddenWeight []int8   /* same [input][hidden] layout as arHiddenWeight */
+	auHiddenPacked []uint64 /* aiHiddenWeight again, 4 to a word; see evaluateQ */
+	cPacked        int      /* words per input */
+	arHiddenScale  []float32
+	aOutlier       [][]_QOutlier /* per input */
+}
+
+type _QOutlier struct {
+	iHidden int
+	rWeight float32
+}
+
+func neuralNetQuantize(pqnn *_NeuralNetQ, pnn *_NeuralNet) {
+	pqnn.pnn = pnn
+	pqnn.cInput = pnn.cInput
+	pqnn.cHidden = pnn.cHidden
+
+	pqnn.aiHiddenWeight = make([]int8, pnn.cInput*pnn.cHidden)
+	pqnn.arHiddenScale = make([]float32, pnn.cHidden)
+	pqnn.aOutlier = make([][]_QOutlier, pnn.cInput)
+
+	cOutliers := (pnn.cInput + 99) / 100
+	arAbs := make([]float64, pnn.cInput)
+
+	for j := 0; j < pnn.cHidden; j++ {
+		for i := 0; i < pnn.cInput; i++ {
+			arAbs[i] = math.Abs(float64(pnn.arHiddenWeight[i*pnn.cHidden+j]))
+		}
+		sort.Float64s(arAbs)
+
+		/* largest magnitude left in the int8 part of the row */
+		rMax := float32(arAbs[pnn.cInput-1-cOutliers])
+
+		if rMax == 0.0 {
+			/* dead node; all weights quantize to zero */
+			pqnn.arHiddenScale[j] = 0.0
+		} else {
+			pqnn.arHiddenScale[j] = rMax / 127.0
+		}
+
+		for i := 0; i < pnn.cInput; i++ {
+			k := i*pnn.cHidden + j
+			r := pnn.arHiddenWeight[k]
+
+			if float32(math.Abs(float64(r))) > rMax {
+				pqnn.aOutlier[i] = append(pqnn.aOutlier[i], _QOutlier{j, r})
+			} else if rMax != 0.0 {
+				pqnn.aiHiddenWeight[k] = int8(math.Round(float64(r / pqnn.arHiddenScale[j])))
+			}
+		}
+	}
+
+	pqnn.cPacked = (pnn.cHidden + 3) / 4
+	pqnn.auHiddenPacked = make([]uint64, pnn.cInput*pqnn.cPacked)
+
+	for i := 0; i < pnn.cInput; i++ {
+		for j := 0; j < pqnn.cPacked*4; j++ {
+			var w int8
+			if j < pnn.cHidden {
+				w = pqnn.aiHiddenWeight[i*pnn.cHidden+j]
+			}
+			pqnn.auHiddenPacked[i*pqnn.cPacked+j/4] |= uint64(int32(w)+_QPACK_BIAS) << (16 * (j % 4))
+		}
+	}
+}
+
+func neuralNetEvaluateQ(pqnn *_NeuralNetQ, arInput []float32, arOutput *[_NUM_OUTPUTS]float32) error {
+	ar := make([]float32, pqnn.cHidden)
+
+	evaluateQ(pqnn, arInput, ar, arOutput)
+
+	return nil
+}
+
+func evaluateQ(pqnn *_NeuralNetQ, arInput []float32, ar []float32, arOutput *[_NUM_OUTPUTS]float32) {
+	cHidden := pqnn.cHidden
+
+	/* The packed weights are summed four at a time, as 16 bit lanes of a
+	 * uint64, which is where the speed up over the float nets comes from.
+	 * Most inputs are exactly 0 or 1 and can be added as they are. Other
+	 * inputs split each word into two words of 32 bit lanes first, so the
+	 * product with the input fits. As the lanes are biased to be
+	 * non-negative nothing ever carries into the next lane; the bias is
+	 * taken off once at the end. */
+	cPacked := pqnn.cPacked
+	acc := make([]uint64, 3*cPacked)
+	accOne, accEven, accOdd := acc[:cPacked], acc[cPacked:2*cPacked], acc[2*cPacked:]
+	var nOne, nSum int64
+
+	/* Calculate activity at hidden nodes */
+	copy(ar, pqnn.pnn.arHiddenThreshold)
+
+	for i, r := range arInput[:pqnn.cInput] {
+		if r == 0.0 {
+			continue
+		}
+
+		for _, o := range pqnn.aOutlier[i] {
+			ar[o.iHidden] += o.rWeight * r
+		}
+
+		/* reslice so the compiler can drop the bounds checks */
+		puWeight := pqnn.auHiddenPacked[i*cPacked : (i+1)*cPacked]
+
+		if r == 1.0 {
+			accOne := accOne[:len(puWeight)]
+			for k, u := range puWeight {
+				accOne[k] += u
+			}
+			nOne++
+			continue
+		}
+
+		x := int64(r*_QINPUT_ONE + 0.5)
+		ux := uint64(x)
+		accEven := accEven[:len(puWeight)]
+		accOdd := accOdd[:len(puWeight)]
+		for k, u := range puWeight {
+			accEven[k] += (u & 0x0000ffff0000ffff) * ux
+			accOdd[k] += ((u >> 16) & 0x0000ffff0000ffff) * ux
+		}
+		nSum += x
+	}
+
+	for j := 0; j < cHidden; j++ {
+		k, l := j/4, j%4
+
+		one := int64((accOne[k]>>(16*l))&0xffff) - nOne*_QPACK_BIAS
+
+		var frac int64
+		if l%2 == 0 {
+			frac = int64((accEven[k] >> (16 * l)) & 0xffffffff)
+		} else {
+			frac = int64((accOdd[k] >> (16 * (l - 1))) & 0xffffffff)
+		}
+		frac -= nSum * _QPACK_BIAS
+
+		ar[j] += float32(one<<_QINPUT_SHIFT+frac) * pqnn.arHiddenScale[j] / _QINPUT_ONE
+	}
+
+	evaluateLayers(pqnn.pnn, ar, arOutput)
+}
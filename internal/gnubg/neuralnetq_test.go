@@ -0,0 +1,123 @@
+package gnubg
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_neuralNetQuantize(t *testing.T) {
+	once.Do(setup)
+	tests := []struct {
+		name string
+		pnn  *_NeuralNet
+	}{
+		{name: "should quantize contact", pnn: &nnContact},
+		{name: "should quantize race", pnn: &nnRace},
+		{name: "should quantize pruning crashed", pnn: &nnpCrashed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pqnn _NeuralNetQ
+			neuralNetQuantize(&pqnn, tt.pnn)
+			if len(pqnn.aiHiddenWeight) != len(tt.pnn.arHiddenWeight) {
+				t.Fatalf("neuralNetQuantize() weight count mismatch")
+			}
+			// every weight must either be an outlier or round-trip to within
+			// half a quantization step
+			var anOutlier = make([]int, tt.pnn.cHidden)
+			for i := 0; i < tt.pnn.cInput; i++ {
+				for _, o := range pqnn.aOutlier[i] {
+					anOutlier[o.iHidden]++
+				}
+			}
+			for j, n := range anOutlier {
+				if n > (tt.pnn.cInput+99)/100 {
+					t.Fatalf("neuralNetQuantize() node %v has %v outliers", j, n)
+				}
+			}
+			for i := 0; i < tt.pnn.cInput; i++ {
+				for j := 0; j < tt.pnn.cHidden; j++ {
+					k := i*tt.pnn.cHidden + j
+					w := float32(pqnn.aiHiddenWeight[k]) * pqnn.arHiddenScale[j]
+					for _, o := range pqnn.aOutlier[i] {
+						if o.iHidden == j {
+							w = o.rWeight
+						}
+					}
+					if math.Abs(float64(w-tt.pnn.arHiddenWeight[k])) > float64(pqnn.arHiddenScale[j])/2+1e-6 {
+						t.Fatalf("neuralNetQuantize() hidden weight %v = %v, want %v", k, w, tt.pnn.arHiddenWeight[k])
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_neuralNetEvaluateQ(t *testing.T) {
+	once.Do(setup)
+	tests := []struct {
+		name  string
+		board _TanBoard
+		pnn   *_NeuralNet
+		pqnn  *_NeuralNetQ
+		calc  func(_TanBoard, []float32)
+		cIn   int
+	}{
+		{
+			name:  "should match float contact",
+			board: _TanBoard{{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0}, {0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0}},
+			pnn:   &nnContact,
+			pqnn:  &nnqContact,
+			calc:  calculateContactInputs,
+			cIn:   _NUM_INPUTS,
+		},
+		{
+			name:  "should match float race",
+			board: _TanBoard{{3, 3, 3, 2, 2, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, {0, 2, 3, 3, 3, 2, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+			pnn:   &nnRace,
+			pqnn:  &nnqRace,
+			calc:  calculateRaceInputs,
+			cIn:   _NUM_RACE_INPUTS,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var arInput = make([]float32, tt.cIn)
+			var arOutput, arOutputQ [_NUM_OUTPUTS]float32
+			tt.calc(tt.board, arInput)
+			neuralNetEvaluateSSE(tt.pnn, arInput, &arOutput, nil)
+			if err := neuralNetEvaluateQ(tt.pqnn, arInput, &arOutputQ); err != nil {
+				t.Fatalf("neuralNetEvaluateQ() error = %v", err)
+			}
+			for i := 0; i < _NUM_OUTPUTS; i++ {
+				if math.Abs(float64(arOutput[i]-arOutputQ[i])) > 0.01 {
+					t.Errorf("neuralNetEvaluateQ() output %v = %v, want %v", i, arOutputQ[i], arOutput[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompareQuantized(t *testing.T) {
+	once.Do(setup)
+	var board = TanBoard{
+		{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+		{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+	}
+	got, err := CompareQuantized(board, 1)
+	if err != nil {
+		t.Fatalf("CompareQuantized() error = %v", err)
+	}
+	if got.Class != "contact" {
+		t.Errorf("CompareQuantized() class = %v, want contact", got.Class)
+	}
+	if got.RollWeight != 36 {
+		t.Errorf("CompareQuantized() roll weight = %v, want 36", got.RollWeight)
+	}
+	if got.AgreeWeight < 30 {
+		t.Errorf("CompareQuantized() agree weight = %v, want >= 30", got.AgreeWeight)
+	}
+	if got.EquityLoss < 0 || got.EquityLoss > 36*0.01 {
+		t.Errorf("CompareQuantized() equity loss = %v", got.EquityLoss)
+	}
+}
@@ -1,7 +1,5 @@
 package gnubg
 
-import "bgweb-api/internal/gnubg/sigmoid"
-
 func neuralNetEvaluateSSE(pnn *_NeuralNet, arInput []float32, arOutput *[_NUM_OUTPUTS]float32, pnState *_NNState) error {
 	ar := make([]float32, pnn.cHidden)
 
@@ -128,20 +126,5 @@ func evaluateSSE(pnn *_NeuralNet, arInput []float32, ar []float32, arOutput *[_N
 		}
 	}
 
-	for i := 0; i < cHidden; i++ {
-		ar[i] = sigmoid.Sigmoid(-pnn.rBetaHidden * ar[i])
-	}
-
-	/* Calculate activity at output nodes */
-	prWeight = pnn.arOutputWeight
-
-	for i := 0; i < pnn.cOutput; i++ {
-		r := pnn.arOutputThreshold[i]
-
-		for j := 0; j < cHidden; j++ {
-			r += ar[j] * prWeight[0]
-			prWeight = prWeight[1:]
-		}
-		arOutput[i] = sigmoid.Sigmoid(-pnn.rBetaOutput * r)
-	}
+	evaluateLayers(pnn, ar, arOutput)
 }
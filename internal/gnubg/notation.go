@@ -0,0 +1,153 @@
+package gnubg
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Notation writes a play in standard notation, such as "8/5* 6/5",
+// "24/18(2)", "bar/22" or "6/off". board is seen from the player moving
+// and moves are as in Play.Moves, a die at a time. The moves of a chequer
+// are joined into one, with the points on the way written only when it
+// hits there, as in "13/8*/7"; identical moves are written once, with how
+// many times they are played. Points are counted from the player moving,
+// or from their opponent with flip.
+func Notation(board TanBoard, moves [][2]int, flip bool) string {
+	type path struct {
+		from  int
+		stops []int
+		hits  []bool
+	}
+	var paths []*path
+
+	var anBoard = _TanBoard(board)
+	for _, m := range moves {
+		var from, to = m[0], m[1]
+
+		var fHit bool
+		if to >= 0 && anBoard[0][23-to] == 1 {
+			fHit = true
+			anBoard[0][23-to] = 0
+			anBoard[0][24]++
+		}
+		anBoard[1][from]--
+		if to >= 0 {
+			anBoard[1][to]++
+		}
+
+		/* the chequer that moved here with an earlier die moves on */
+		var p *path
+		for _, q := range paths {
+			if q.stops[len(q.stops)-1] == from {
+				p = q
+				break
+			}
+		}
+		if p == nil {
+			p = &path{from: from}
+			paths = append(paths, p)
+		}
+		p.stops = append(p.stops, to)
+		p.hits = append(p.hits, fHit)
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		if paths[i].from != paths[j].from {
+			return paths[i].from > paths[j].from
+		}
+		return paths[i].stops[len(paths[i].stops)-1] > paths[j].stops[len(paths[j].stops)-1]
+	})
+
+	var asz []string
+	var an []int
+	for _, p := range paths {
+		var s = notationPoint(p.from, flip)
+		for i, to := range p.stops {
+			if i < len(p.stops)-1 && !p.hits[i] {
+				continue
+			}
+			s += "/" + notationPoint(to, flip)
+			if p.hits[i] {
+				s += "*"
+			}
+		}
+
+		if n := len(asz); n > 0 && asz[n-1] == s {
+			an[n-1]++
+			continue
+		}
+		asz = append(asz, s)
+		an = append(an, 1)
+	}
+
+	for i := range asz {
+		if an[i] > 1 {
+			asz[i] += fmt.Sprintf("(%v)", an[i])
+		}
+	}
+	return strings.Join(asz, " ")
+}
+
+/* a point counted from 0, 24 for the bar and -1 for off, as notation
+ * writes it */
+func notationPoint(i int, flip bool) string {
+	if flip && i >= 0 && i < 24 {
+		return strconv.Itoa(24 - i)
+	}
+	return pointNumber(i)
+}
+
+var reNotationMove = regexp.MustCompile(`^(bar|\d+)((?:/(?:\d+|off)\*?)+)(?:\((\d)\))?$`)
+
+// ParseNotation reads a play in standard notation, as Notation writes it,
+// into chequer moves as CheckPlay takes them: one from each point written
+// to the next, so that a chequer may move with several dice at once.
+// "bar" and "off" may be in any case, and hits need not be marked. Points
+// are counted as for Notation.
+func ParseNotation(s string, flip bool) ([][2]int, error) {
+	var ret = [][2]int{}
+	for _, sz := range strings.Fields(strings.ToLower(s)) {
+		var m = reNotationMove.FindStringSubmatch(sz)
+		if m == nil {
+			return nil, fmt.Errorf("invalid move %q", sz)
+		}
+
+		var asz = append([]string{m[1]}, strings.Split(m[2], "/")[1:]...)
+		var points = make([]int, len(asz))
+		for i, szPoint := range asz {
+			switch szPoint = strings.TrimSuffix(szPoint, "*"); {
+			case szPoint == "bar":
+				points[i] = 24
+			case szPoint == "off" && i == len(asz)-1:
+				points[i] = -1
+			default:
+				n, err := strconv.Atoi(szPoint)
+				if err != nil || n < 1 || n > 24 {
+					return nil, fmt.Errorf("invalid point %q in %q", szPoint, sz)
+				}
+				if flip {
+					n = 25 - n
+				}
+				points[i] = n - 1
+			}
+		}
+
+		var c = 1
+		if m[3] != "" {
+			c, _ = strconv.Atoi(m[3])
+			if c < 1 || c > 4 {
+				return nil, fmt.Errorf("invalid count in %q", sz)
+			}
+		}
+		for ; c > 0; c-- {
+			for i := 1; i < len(points); i++ {
+				ret = append(ret, [2]int{points[i-1], points[i]})
+			}
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,80 @@
+package gnubg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNotation(t *testing.T) {
+	/* an opponent's blot on the 5-point */
+	var blot = startBoard
+	blot[0][23] = 1
+	blot[0][19] = 1
+
+	var onBar = startBoard
+	onBar[1][12] -= 1
+	onBar[1][24] = 1
+
+	var bearoff = TanBoard{{23: 2, 12: 13}, {0: 2, 1: 13}}
+
+	tests := []struct {
+		name  string
+		board TanBoard
+		moves [][2]int
+		flip  bool
+		want  string
+	}{
+		{"should write a move per die", startBoard, [][2]int{{7, 4}, {5, 4}}, false, "8/5 6/5"},
+		{"should mark a hit", blot, [][2]int{{7, 4}, {5, 4}}, false, "8/5* 6/5"},
+		{"should join the moves of a chequer", startBoard, [][2]int{{12, 7}, {7, 6}}, false, "13/7"},
+		{"should write where a chequer hits on the way", blot, [][2]int{{7, 4}, {4, 3}}, false, "8/5*/4"},
+		{"should group identical moves", startBoard, [][2]int{{23, 17}, {23, 17}, {12, 6}, {12, 6}}, false, "24/18(2) 13/7(2)"},
+		{"should group running chequers", startBoard, [][2]int{{23, 17}, {17, 11}, {23, 17}, {17, 11}}, false, "24/12(2)"},
+		{"should enter from the bar", onBar, [][2]int{{24, 21}, {7, 6}}, false, "bar/22 8/7"},
+		{"should bear off", bearoff, [][2]int{{1, -1}, {0, -1}}, false, "2/off 1/off"},
+		{"should count from the opponent", blot, [][2]int{{7, 4}, {5, 4}}, true, "17/20* 19/20"},
+		{"should write nothing for no moves", startBoard, nil, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Notation(tt.board, tt.moves, tt.flip); got != tt.want {
+				t.Errorf("Notation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNotation(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		flip    bool
+		want    [][2]int
+		wantErr bool
+	}{
+		{"should read a move per die", "8/5 6/5", false, [][2]int{{7, 4}, {5, 4}}, false},
+		{"should read hits", "8/5* 6/5", false, [][2]int{{7, 4}, {5, 4}}, false},
+		{"should read a chequer moved with both dice", "13/7", false, [][2]int{{12, 6}}, false},
+		{"should read the points on the way", "13/8*/7", false, [][2]int{{12, 7}, {7, 6}}, false},
+		{"should read a count", "24/18(2) 13/7(2)", false, [][2]int{{23, 17}, {23, 17}, {12, 6}, {12, 6}}, false},
+		{"should read the bar and off", "Bar/22 6/OFF", false, [][2]int{{24, 21}, {5, -1}}, false},
+		{"should count from the opponent", "17/20* 19/20", true, [][2]int{{7, 4}, {5, 4}}, false},
+		{"should read no moves", "", false, [][2]int{}, false},
+		{"should refuse a point off the board", "26/20", false, nil, true},
+		{"should refuse a move from off", "off/20", false, nil, true},
+		{"should refuse a move through off", "6/off/3", false, nil, true},
+		{"should refuse a bad count", "24/18(5)", false, nil, true},
+		{"should refuse a move to nowhere", "24", false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNotation(tt.s, tt.flip)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNotation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNotation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
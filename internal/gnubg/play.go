@@ -0,0 +1,543 @@
+package gnubg
+
+import (
+	"fmt"
+)
+
+var aamfFilters = map[string]*[_MAX_FILTER_PLIES][_MAX_FILTER_PLIES]_MoveFilter{
+	"tiny":   &_MOVEFILTER_TINY,
+	"narrow": &_MOVEFILTER_NARROW,
+	"normal": &_MOVEFILTER_NORMAL,
+	"large":  &_MOVEFILTER_LARGE,
+	"huge":   &_MOVEFILTER_HUGE,
+}
+
+// Settings are the evaluation settings the engine plays with.
+type Settings struct {
+	Plies         int
+	Filter        string // move filter: tiny, narrow, normal (default), large or huge
+	Cubeful       bool
+	Prune         bool    // use the pruning nets when searching
+	Noise         float32 // standard deviation of the noise added to evaluations
+	Deterministic bool    // noise depends on the position only
+	Quantized     bool
+	Weights       *Weights // nets to evaluate with, nil for the engine's own
+}
+
+func (s Settings) evalContext() (_EvalContext, *[_MAX_FILTER_PLIES][_MAX_FILTER_PLIES]_MoveFilter, error) {
+	var aamf = &_MOVEFILTER_NORMAL
+	if s.Filter != "" {
+		var ok bool
+		if aamf, ok = aamfFilters[s.Filter]; !ok {
+			return _EvalContext{}, nil, fmt.Errorf("unknown move filter: %v", s.Filter)
+		}
+	}
+
+	if s.Plies < 0 || s.Plies > 7 {
+		return _EvalContext{}, nil, fmt.Errorf("invalid plies: %v", s.Plies)
+	}
+
+	return _EvalContext{
+		fCubeful:       s.Cubeful,
+		nPlies:         s.Plies,
+		fUsePrune:      s.Prune,
+		fDeterministic: s.Deterministic,
+		rNoise:         s.Noise,
+		fQuantized:     s.Quantized,
+		pWeights:       s.Weights,
+	}, aamf, nil
+}
+
+// Cube is the state of the cube and, in match play, the score.
+type Cube struct {
+	Value    int
+	Owner    int // player owning the cube, -1 when centred
+	MatchTo  int // 0 for money play
+	Score    [2]int
+	Crawford bool
+	Jacoby   bool // money play only
+	Beavers  bool // money play only
+}
+
+func (c Cube) cubeInfo(player int) (_CubeInfo, error) {
+	var ci _CubeInfo
+	if err := setCubeInfo(&ci, c.Value, c.Owner, player, c.MatchTo, c.Score, c.Crawford, c.Jacoby, c.Beavers, _VARIATION_STANDARD); err != nil {
+		return ci, fmt.Errorf("invalid cube: %v", err)
+	}
+	return ci, nil
+}
+
+// CubeDecision holds the cubeful equities of a cube decision, normalised
+// to the current cube value and seen from the player who may double.
+type CubeDecision struct {
+	Available  bool // false if the player can't double
+	NoDouble   float32
+	DoubleTake float32
+	DoublePass float32
+}
+
+// Double reports whether doubling is right.
+func (d CubeDecision) Double() bool {
+	return d.Available && d.DoubleTake >= d.NoDouble && d.DoublePass >= d.NoDouble
+}
+
+// Take reports whether taking a double is right.
+func (d CubeDecision) Take() bool {
+	return d.DoubleTake <= d.DoublePass
+}
+
+// The functions below take boards seen from the player on roll, like
+// Weights: board[1] holds that player's chequers. player tells which side
+// of the cube and score they are.
+
+// BestPlay returns the board after the best play of the dice, still seen
+// from the player who moved. The board is returned unchanged if the dice
+// can't be played.
+func BestPlay(board TanBoard, dice [2]int, player int, cube Cube, s Settings) (TanBoard, error) {
+	ml, err := findPlays(board, dice, player, cube, s, nil)
+	if err != nil {
+		return board, err
+	}
+
+	if ml.cMoves == 0 {
+		return board, nil
+	}
+
+	var anBoard _TanBoard
+	ml.amMoves[ml.iMoveBest].key.toBoard(&anBoard)
+
+	return TanBoard(anBoard), nil
+}
+
+// PlayLoss returns the equity lost by a play compared to the best one,
+// as judged with the given settings, and the number of legal plays.
+func PlayLoss(board TanBoard, dice [2]int, player int, cube Cube, played TanBoard, s Settings) (float32, int, error) {
+	ml, i, err := gradePlay(board, dice, player, cube, played, s)
+	if err != nil || ml.cMoves < 2 {
+		return 0, ml.cMoves, err
+	}
+	if i < 0 {
+		return 0, ml.cMoves, fmt.Errorf("illegal play")
+	}
+
+	return ml.amMoves[ml.iMoveBest].rScore - ml.amMoves[i].rScore, ml.cMoves, nil
+}
+
+// GradePlay scores the legal plays of the dice with the settings, best
+// first, and returns them with the index of the play reaching played.
+// That play is scored as deeply as the best one, even when the move
+// filters would have dropped it earlier. It fails if no legal play
+// reaches played.
+func GradePlay(board TanBoard, dice [2]int, player int, cube Cube, played TanBoard, s Settings) (MoveList, int, error) {
+	ml, i, err := gradePlay(board, dice, player, cube, played, s)
+	if err == nil && i < 0 {
+		err = fmt.Errorf("illegal play")
+	}
+	return ml, i, err
+}
+
+/* the plays scored as for GradePlay, and the index of the one reaching
+ * played, or -1 */
+func gradePlay(board TanBoard, dice [2]int, player int, cube Cube, played TanBoard, s Settings) (_MoveList, int, error) {
+	var key _PositionKey
+	key.fromBoard(_TanBoard(played))
+
+	ml, err := findPlays(board, dice, player, cube, s, &key)
+	if err != nil {
+		return ml, -1, err
+	}
+
+	for i := 0; i < ml.cMoves; i++ {
+		if ml.amMoves[i].key.equals(key) {
+			return ml, i, nil
+		}
+	}
+
+	return ml, -1, nil
+}
+
+// Play is a legal play of the dice: the chequer moves, from and to points
+// of the player moving counted from 0, with 24 the bar and -1 off, and the
+// board after it, still seen from that player.
+type Play struct {
+	Moves [][2]int
+	Board TanBoard
+}
+
+// LegalPlays lists the legal plays of the dice, none if they can't be
+// played. Plays reaching the same position are listed once.
+func LegalPlays(board TanBoard, dice [2]int) []Play {
+	var ml _MoveList
+	generateMoves(&_ThreadLocalData{}, &ml, _TanBoard(board), dice[0], dice[1], false)
+
+	var ret = make([]Play, ml.cMoves)
+	for i := range ret {
+		ret[i] = newPlay(&ml.amMoves[i])
+	}
+
+	return ret
+}
+
+// FindPlay finds the legal play of the dice reaching after, matching the
+// positions by their keys. When the dice can't be played, the board left
+// as it is is reached by a play with no chequer moves.
+func FindPlay(board TanBoard, dice [2]int, after TanBoard) (Play, bool) {
+	var key _PositionKey
+	key.fromBoard(_TanBoard(after))
+
+	var ml _MoveList
+	generateMoves(&_ThreadLocalData{}, &ml, _TanBoard(board), dice[0], dice[1], false)
+
+	if ml.cMoves == 0 {
+		var keyBoard _PositionKey
+		keyBoard.fromBoard(_TanBoard(board))
+		return Play{Board: board}, keyBoard.equals(key)
+	}
+
+	for i := 0; i < ml.cMoves; i++ {
+		if ml.amMoves[i].key.equals(key) {
+			return newPlay(&ml.amMoves[i]), true
+		}
+	}
+
+	return Play{}, false
+}
+
+func newPlay(pm *_Move) Play {
+	var ret Play
+	for j := 0; j < 8 && pm.anMove[j] >= 0; j += 2 {
+		ret.Moves = append(ret.Moves, [2]int{pm.anMove[j], pm.anMove[j+1]})
+	}
+	var anBoard _TanBoard
+	pm.key.toBoard(&anBoard)
+	ret.Board = TanBoard(anBoard)
+	return ret
+}
+
+// ApplyMoves moves chequers of the player on roll as Play.Moves tells,
+// hitting blots on the way. It only checks that each move can be made,
+// not that they make up a legal play.
+func ApplyMoves(board TanBoard, moves [][2]int) (TanBoard, error) {
+	var anBoard = _TanBoard(board)
+	for _, m := range moves {
+		if err := applySubMove(&anBoard, m[0], m[0]-m[1], false); err != nil {
+			return board, fmt.Errorf("can't move %v/%v: %v", m[0]+1, m[1]+1, err)
+		}
+	}
+	return TanBoard(anBoard), nil
+}
+
+// CheckPlay finds the board after a play of the dice, given by its
+// chequer moves as in Play.Moves except that a chequer may move with
+// several dice at once, as 23/19 does with 3-1. It checks that the play is
+// legal: each die moves a chequer onto an open point, chequers on the bar
+// enter first, chequers are only borne off from a full home board, and
+// as many dice as can be are played, the higher one when only one can.
+// The error tells which of these the play breaks.
+func CheckPlay(board TanBoard, dice [2]int, moves [][2]int) (TanBoard, error) {
+	var legal = LegalPlays(board, dice)
+
+	var anRoll = []int{dice[0], dice[1]}
+	if dice[0] == dice[1] {
+		anRoll = append(anRoll, dice[0], dice[0])
+	}
+
+	if !diceFit(moves, anRoll) {
+		return board, fmt.Errorf("%v can't be played with %v-%v", playText(moves), dice[0], dice[1])
+	}
+
+	var ps = playSearch{legal: legal, nDice: len(anRoll)}
+	ps.search(_TanBoard(board), moves, anRoll)
+
+	switch {
+	case ps.fFound:
+		return TanBoard(ps.anBoard), nil
+	case !ps.fPlayed && ps.err != nil:
+		return board, ps.err
+	case !ps.fPlayed:
+		return board, fmt.Errorf("%v can't be played with %v-%v", playText(moves), dice[0], dice[1])
+	case len(legal) == 0:
+		return board, fmt.Errorf("%v-%v can't be played", dice[0], dice[1])
+	}
+
+	/* the moves can be made but aren't a legal play: they leave dice
+	 * unplayed */
+	var nLegal = len(legal[0].Moves)
+	switch {
+	case ps.nUsed < nLegal && dice[0] == dice[1]:
+		return board, fmt.Errorf("%v dice must be played", nLegal)
+	case ps.nUsed < nLegal:
+		return board, fmt.Errorf("both dice must be played")
+	case nLegal == 1:
+		return board, fmt.Errorf("the higher die must be played")
+	}
+	return board, fmt.Errorf("illegal play")
+}
+
+/* whether the dice of anRoll add up to the pips of each chequer move, or
+ * to more for those bearing off, whatever the board */
+func diceFit(moves [][2]int, anRoll []int) bool {
+	if len(moves) == 0 {
+		return true
+	}
+
+	var nPips = moves[0][0] - moves[0][1]
+	for mask := 1; mask < 1<<len(anRoll); mask++ {
+		var n int
+		var anRest []int
+		for i, d := range anRoll {
+			if mask&(1<<i) != 0 {
+				n += d
+			} else {
+				anRest = append(anRest, d)
+			}
+		}
+		if (n == nPips || (moves[0][1] < 0 && n > nPips)) && diceFit(moves[1:], anRest) {
+			return true
+		}
+	}
+	return false
+}
+
+/* the search for the ways of making the chequer moves of a play */
+type playSearch struct {
+	legal []Play
+	nDice int
+	// a way that is a legal play, and the board after it
+	fFound  bool
+	anBoard _TanBoard
+	// some way makes all the moves, the most dice one of them uses, and
+	// why the first move that couldn't be made failed
+	fPlayed bool
+	nUsed   int
+	err     error
+}
+
+/* makes the chequer moves with the dice of anRoll in every order; the
+ * first move is done when its chequer reaches its destination */
+func (ps *playSearch) search(anBoard _TanBoard, moves [][2]int, anRoll []int) {
+	if ps.fFound {
+		return
+	}
+
+	if len(moves) == 0 || moves[0][0] == moves[0][1] {
+		if len(moves) > 0 {
+			ps.search(anBoard, moves[1:], anRoll)
+			return
+		}
+
+		var nUsed = ps.nDice - len(anRoll)
+		ps.fPlayed = true
+		if nUsed > ps.nUsed {
+			ps.nUsed = nUsed
+		}
+		for _, p := range ps.legal {
+			if _TanBoard(p.Board) == anBoard {
+				ps.fFound = true
+				ps.anBoard = anBoard
+				return
+			}
+		}
+		if len(ps.legal) == 0 && nUsed == 0 {
+			ps.fFound = true
+			ps.anBoard = anBoard
+		}
+		return
+	}
+
+	var iSrc, iDst = moves[0][0], moves[0][1]
+	for i, n := range anRoll {
+		if i > 0 && n == anRoll[i-1] {
+			continue
+		}
+
+		var iNext = iSrc - n
+		switch {
+		case anBoard[1][iSrc] == 0:
+			ps.fail(fmt.Errorf("no chequer on %v", pointText(iSrc)))
+			continue
+		case anBoard[1][24] > 0 && iSrc != 24:
+			ps.fail(fmt.Errorf("chequers on the bar must enter first"))
+			continue
+		case iNext < iDst && iDst >= 0:
+			continue
+		case !legalMove(anBoard, iSrc, n) && iNext >= 0:
+			ps.fail(fmt.Errorf("%v is blocked", pointText(iNext)))
+			continue
+		case !legalMove(anBoard, iSrc, n):
+			ps.fail(fmt.Errorf("chequers are only borne off once all are in the home board, and with a higher die from the highest point"))
+			continue
+		}
+
+		var anBoardNew = anBoard
+		if err := applySubMove(&anBoardNew, iSrc, n, true); err != nil {
+			ps.fail(err)
+			continue
+		}
+		if iNext < 0 {
+			iNext = -1
+		}
+
+		var anRollNew = append(append([]int{}, anRoll[:i]...), anRoll[i+1:]...)
+		var movesNew = append([][2]int{{iNext, iDst}}, moves[1:]...)
+		ps.search(anBoardNew, movesNew, anRollNew)
+	}
+}
+
+func (ps *playSearch) fail(err error) {
+	if ps.err == nil {
+		ps.err = err
+	}
+}
+
+/* a point counted from 0, 24 for the bar and -1 for off, as in "the
+ * 13-point" */
+func pointText(i int) string {
+	switch i {
+	case 24:
+		return "the bar"
+	case -1:
+		return "off"
+	}
+	return fmt.Sprintf("the %v-point", i+1)
+}
+
+func pointNumber(i int) string {
+	switch i {
+	case 24:
+		return "bar"
+	case -1:
+		return "off"
+	}
+	return fmt.Sprint(i + 1)
+}
+
+/* chequer moves as gnubg writes them, such as "13/9 6/5" */
+func playText(moves [][2]int) string {
+	var s string
+	for i, m := range moves {
+		if i > 0 {
+			s += " "
+		}
+		s += pointNumber(m[0]) + "/" + pointNumber(m[1])
+	}
+	return s
+}
+
+/* the plays scored with the settings; keyMove, if not nil, is the
+ * position after a play to score as deeply as the best */
+func findPlays(board TanBoard, dice [2]int, player int, cube Cube, s Settings, keyMove *_PositionKey) (_MoveList, error) {
+	var ml _MoveList
+
+	ci, err := cube.cubeInfo(player)
+	if err != nil {
+		return ml, err
+	}
+
+	ec, aamf, err := s.evalContext()
+	if err != nil {
+		return ml, err
+	}
+
+	err = findnSaveBestMoves(&ml, dice[0], dice[1], _TanBoard(board), keyMove, 0, &ci, &ec, aamf)
+
+	return ml, err
+}
+
+// EvaluatePosition returns the cubeless win, win gammon, win backgammon,
+// lose gammon and lose backgammon chances of the player on roll, before
+// rolling, at the plies of the settings.
+func EvaluatePosition(board TanBoard, player int, cube Cube, s Settings) ([5]float32, error) {
+	var arOutput [_NUM_OUTPUTS]float32
+
+	ci, err := cube.cubeInfo(player)
+	if err != nil {
+		return arOutput, err
+	}
+
+	ec, _, err := s.evalContext()
+	if err != nil {
+		return arOutput, err
+	}
+	ec.fCubeful = false
+
+	err = evaluatePosition(&_ThreadLocalData{}, nil, _TanBoard(board), &arOutput, &ci, &ec)
+
+	return arOutput, err
+}
+
+// EvaluateCube works out the cube decision of the player on roll, before
+// rolling. The evaluation is always cubeful.
+func EvaluateCube(board TanBoard, player int, cube Cube, s Settings) (CubeDecision, error) {
+	var ret CubeDecision
+
+	ci, err := cube.cubeInfo(player)
+	if err != nil {
+		return ret, err
+	}
+
+	ec, _, err := s.evalContext()
+	if err != nil {
+		return ret, err
+	}
+	ec.fCubeful = true
+
+	var rDP float32
+	ret.Available = getDPEq(nil, &rDP, ci)
+
+	var aciCubePos [2]_CubeInfo
+	aciCubePos[0] = ci
+	if err := setCubeInfo(&aciCubePos[1], 2*ci.nCube, 1-player, player, ci.nMatchTo, ci.anScore, ci.fCrawford, ci.fJacoby, ci.fBeavers, ci.bgv); err != nil {
+		/* doubling would exceed the match; only no double is possible */
+		aciCubePos[1].nCube = -1
+		ret.Available = false
+	}
+
+	var arOutput [_NUM_OUTPUTS]float32
+	var arCubeful = make([]float32, 2)
+
+	if err := evaluatePositionCubeful3(&_ThreadLocalData{}, nil, _TanBoard(board), &arOutput, arCubeful, aciCubePos[:], 2, &aciCubePos[0], &ec, ec.nPlies, true); err != nil {
+		return ret, err
+	}
+
+	if ci.nMatchTo > 0 {
+		ret.NoDouble = mwc2eq(arCubeful[0], &ci)
+		ret.DoubleTake = mwc2eq(arCubeful[1], &ci)
+		ret.DoublePass = mwc2eq(rDP, &ci)
+	} else {
+		/* money equities are normed to the cube of each position */
+		ret.NoDouble = arCubeful[0]
+		ret.DoubleTake = 2 * arCubeful[1]
+		ret.DoublePass = rDP
+	}
+
+	if !ret.Available {
+		ret.DoubleTake, ret.DoublePass = ret.NoDouble, ret.NoDouble
+	}
+
+	return ret, nil
+}
+
+// ResignationEquity returns the equity, normalised as EvaluateCube's, of the
+// player who accepts the opponent's resignation for the points of a single
+// game, a gammon or a backgammon: 1, 2 or 3 times the cube.
+func ResignationEquity(player int, cube Cube, points int) (float32, error) {
+	ci, err := cube.cubeInfo(player)
+	if err != nil {
+		return 0, err
+	}
+
+	if ci.nMatchTo == 0 {
+		return float32(points) / float32(ci.nCube), nil
+	}
+
+	var rMwc = getME(ci.anScore[0], ci.anScore[1], ci.nMatchTo, player, points, player, ci.fCrawford, &aafMET, &aafMETPostCrawford)
+	return mwc2eq(rMwc, &ci), nil
+}
+
+// CanDouble reports whether the player may double, given the cube and
+// the match score.
+func (c Cube) CanDouble(player int) bool {
+	ci, err := c.cubeInfo(player)
+	return err == nil && getDPEq(nil, nil, ci)
+}
@@ -0,0 +1,252 @@
+package gnubg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBestPlay(t *testing.T) {
+	once.Do(setup)
+
+	/* 3-1: point on the 5 */
+	var want = startBoard
+	want[1][7] -= 1
+	want[1][5] -= 1
+	want[1][4] += 2
+
+	for _, cube := range []Cube{{Value: 1, Owner: -1}, {Value: 1, Owner: -1, MatchTo: 5}} {
+		got, err := BestPlay(startBoard, [2]int{3, 1}, 0, cube, Settings{Cubeful: true})
+		if err != nil {
+			t.Fatalf("BestPlay() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("BestPlay(%+v) = %v, want %v", cube, got, want)
+		}
+	}
+}
+
+func TestLegalPlays(t *testing.T) {
+	tests := []struct {
+		name  string
+		board TanBoard
+		dice  [2]int
+		want  int
+	}{
+		{"should list the plays of 3-1", startBoard, [2]int{3, 1}, 16},
+		{"should list the plays of 6-6", startBoard, [2]int{6, 6}, 11},
+		{"should list none when dancing", TanBoard{{2, 2, 2, 2, 2, 2}, {24: 1}}, [2]int{6, 6}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LegalPlays(tt.board, tt.dice)
+			if len(got) != tt.want {
+				t.Fatalf("LegalPlays() = %v plays, want %v", len(got), tt.want)
+			}
+			for _, p := range got {
+				if b, err := ApplyMoves(tt.board, p.Moves); err != nil || b != p.Board {
+					t.Errorf("ApplyMoves(%v) = %v, %v, want %v", p.Moves, b, err, p.Board)
+				}
+			}
+		})
+	}
+}
+
+func TestFindPlay(t *testing.T) {
+	/* 8/5 6/5 */
+	var after = startBoard
+	after[1][7] -= 1
+	after[1][5] -= 1
+	after[1][4] += 2
+
+	var closed = TanBoard{{2, 2, 2, 2, 2, 2}, {24: 1}}
+
+	tests := []struct {
+		name   string
+		board  TanBoard
+		dice   [2]int
+		after  TanBoard
+		want   [][2]int
+		wantOK bool
+	}{
+		{"should find the play of 3-1", startBoard, [2]int{3, 1}, after, [][2]int{{7, 4}, {5, 4}}, true},
+		{"should find the play with the dice in either order", startBoard, [2]int{1, 3}, after, [][2]int{{5, 4}, {7, 4}}, true},
+		{"should find no play of other dice", startBoard, [2]int{4, 2}, after, nil, false},
+		{"should find no play leaving the board as it is", startBoard, [2]int{3, 1}, startBoard, nil, false},
+		{"should find the empty play when dancing", closed, [2]int{6, 6}, closed, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FindPlay(tt.board, tt.dice, tt.after)
+			if ok != tt.wantOK {
+				t.Fatalf("FindPlay() found = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Board != tt.after || !reflect.DeepEqual(got.Moves, tt.want) {
+				t.Errorf("FindPlay() = %v, %v, want %v, %v", got.Moves, got.Board, tt.want, tt.after)
+			}
+		})
+	}
+}
+
+func TestApplyMoves(t *testing.T) {
+	/* 8/5 6/5 */
+	var want = startBoard
+	want[1][7] -= 1
+	want[1][5] -= 1
+	want[1][4] += 2
+
+	if got, err := ApplyMoves(startBoard, [][2]int{{7, 4}, {5, 4}}); err != nil || got != want {
+		t.Errorf("ApplyMoves() = %v, %v, want %v", got, err, want)
+	}
+	if _, err := ApplyMoves(startBoard, [][2]int{{2, 0}}); err == nil {
+		t.Errorf("ApplyMoves() from an empty point error = nil")
+	}
+	if _, err := ApplyMoves(startBoard, [][2]int{{23, 18}}); err == nil {
+		t.Errorf("ApplyMoves() onto a made point error = nil")
+	}
+}
+
+func TestPlayLoss(t *testing.T) {
+	once.Do(setup)
+
+	var s = Settings{Cubeful: true}
+	var cube = Cube{Value: 1, Owner: -1}
+
+	best, err := BestPlay(startBoard, [2]int{3, 1}, 0, cube, s)
+	if err != nil {
+		t.Fatalf("BestPlay() error = %v", err)
+	}
+
+	loss, cPlays, err := PlayLoss(startBoard, [2]int{3, 1}, 0, cube, best, s)
+	if err != nil || loss != 0 || cPlays != 16 {
+		t.Errorf("PlayLoss(best) = %v, %v, %v, want 0, 16, nil", loss, cPlays, err)
+	}
+
+	/* 24/20 */
+	var bad = startBoard
+	bad[1][23] -= 1
+	bad[1][19] += 1
+
+	loss, _, err = PlayLoss(startBoard, [2]int{3, 1}, 0, cube, bad, s)
+	if err != nil || loss <= 0.05 {
+		t.Errorf("PlayLoss(24/20) = %v, %v, want a clear error", loss, err)
+	}
+
+	if _, _, err := PlayLoss(startBoard, [2]int{3, 1}, 0, cube, startBoard, s); err == nil {
+		t.Errorf("PlayLoss(illegal) error = nil, want an error")
+	}
+}
+
+func TestGradePlay(t *testing.T) {
+	once.Do(setup)
+
+	/* 6-4: 24/14, which the move filters drop before 2 plies */
+	var played = startBoard
+	played[1][23] -= 1
+	played[1][13] += 1
+
+	ml, i, err := GradePlay(startBoard, [2]int{6, 4}, 0, Cube{Value: 1, Owner: -1}, played, Settings{Plies: 2, Prune: true})
+	if err != nil {
+		t.Fatalf("GradePlay() error = %v", err)
+	}
+	var best, move = ml.GetMove(0), ml.GetMove(i)
+	if i == 0 || move.GetEvalInfo().Plies != best.GetEvalInfo().Plies || move.GetEquity() >= best.GetEquity() {
+		t.Errorf("GradePlay() play %v at %v plies, %v, best at %v plies, %v", i, move.GetEvalInfo().Plies, move.GetEquity(), best.GetEvalInfo().Plies, best.GetEquity())
+	}
+
+	if _, _, err := GradePlay(startBoard, [2]int{6, 4}, 0, Cube{Value: 1, Owner: -1}, startBoard, Settings{}); err == nil {
+		t.Errorf("GradePlay(illegal) error = nil, want an error")
+	}
+}
+
+func TestCheckPlay(t *testing.T) {
+	var onBar = startBoard
+	onBar[1][12] -= 1
+	onBar[1][24] = 1
+
+	tests := []struct {
+		name    string
+		board   TanBoard
+		dice    [2]int
+		moves   [][2]int
+		want    [][2]int // the same play with a chequer move per die
+		wantErr string
+	}{
+		{name: "should play a move per die", board: startBoard, dice: [2]int{3, 1}, moves: [][2]int{{7, 4}, {5, 4}}, want: [][2]int{{7, 4}, {5, 4}}},
+		{name: "should play a chequer with both dice", board: startBoard, dice: [2]int{3, 1}, moves: [][2]int{{23, 19}}, want: [][2]int{{23, 20}, {20, 19}}},
+		{name: "should enter from the bar", board: onBar, dice: [2]int{3, 1}, moves: [][2]int{{24, 21}, {7, 6}}, want: [][2]int{{24, 21}, {7, 6}}},
+		{name: "should refuse a chequer moved too far", board: startBoard, dice: [2]int{3, 1}, moves: [][2]int{{12, 7}}, wantErr: "13/8 can't be played with 3-1"},
+		{name: "should refuse an empty point", board: startBoard, dice: [2]int{3, 1}, moves: [][2]int{{2, 1}, {5, 2}}, wantErr: "no chequer on the 3-point"},
+		{name: "should refuse a blocked point", board: startBoard, dice: [2]int{6, 5}, moves: [][2]int{{23, 18}, {23, 17}}, wantErr: "the 19-point is blocked"},
+		{name: "should enter first", board: onBar, dice: [2]int{3, 1}, moves: [][2]int{{7, 4}, {24, 23}}, wantErr: "chequers on the bar must enter first"},
+		{name: "should play both dice", board: startBoard, dice: [2]int{3, 1}, moves: [][2]int{{7, 4}}, wantErr: "both dice must be played"},
+		{name: "should play all four dice", board: startBoard, dice: [2]int{6, 6}, moves: [][2]int{{23, 17}, {23, 17}}, wantErr: "4 dice must be played"},
+		{
+			name:  "should play the higher die",
+			board: TanBoard{{23: 2}, {7: 1}}, dice: [2]int{6, 1}, moves: [][2]int{{7, 6}},
+			wantErr: "the higher die must be played",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckPlay(tt.board, tt.dice, tt.moves)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("CheckPlay() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckPlay() error = %v", err)
+			}
+			if want, _ := ApplyMoves(tt.board, tt.want); got != want {
+				t.Errorf("CheckPlay() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEvaluateCube(t *testing.T) {
+	once.Do(setup)
+
+	/* closed board against two chequers on the bar: too good to double */
+	var closeout = TanBoard{
+		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 3, 3, 3, 0, 0, 1, 2},
+		{2, 2, 2, 2, 2, 3, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	}
+
+	tests := []struct {
+		name       string
+		board      TanBoard
+		cube       Cube
+		wantDouble bool
+		wantTake   bool
+	}{
+		{"opening money", startBoard, Cube{Value: 1, Owner: -1}, false, true},
+		{"opening match", startBoard, Cube{Value: 1, Owner: -1, MatchTo: 5}, false, true},
+		{"closeout money", closeout, Cube{Value: 1, Owner: -1}, false, false},
+		{"closeout match", closeout, Cube{Value: 1, Owner: -1, MatchTo: 5}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateCube(tt.board, 0, tt.cube, Settings{})
+			if err != nil {
+				t.Fatalf("EvaluateCube() error = %v", err)
+			}
+			if !got.Available {
+				t.Errorf("EvaluateCube() = %+v, want the cube available", got)
+			}
+			if got.Double() != tt.wantDouble || got.Take() != tt.wantTake {
+				t.Errorf("EvaluateCube() = %+v, want double %v take %v", got, tt.wantDouble, tt.wantTake)
+			}
+		})
+	}
+
+	/* no cube in the Crawford game */
+	got, err := EvaluateCube(startBoard, 0, Cube{Value: 1, Owner: -1, MatchTo: 5, Score: [2]int{4, 2}, Crawford: true}, Settings{})
+	if err != nil || got.Available {
+		t.Errorf("EvaluateCube(Crawford) = %+v, %v, want the cube unavailable", got, err)
+	}
+}
@@ -1,5 +1,10 @@
 package gnubg
 
+import (
+	"encoding/base64"
+	"fmt"
+)
+
 type _PositionKey struct {
 	data [7]int
 }
@@ -187,3 +192,121 @@ func positionIndex(g int, anBoard [6]int) int {
 	 * only called from bearoffgammon, so this should be fine. */
 	return positionF(fBits, 15, g)
 }
+
+/* Position IDs and match IDs are base64, without padding, of keys whose
+ * bits are filled from the least significant bit of the first byte. */
+func addBits(auchKey []byte, bitPos int, nBits int, value int) {
+	for i := 0; i < nBits; i++ {
+		if value&(1<<i) != 0 {
+			auchKey[(bitPos+i)/8] |= 1 << ((bitPos + i) % 8)
+		}
+	}
+}
+
+func getBits(auchKey []byte, bitPos int, nBits int) int {
+	var value int
+	for i := 0; i < nBits; i++ {
+		if auchKey[(bitPos+i)/8]&(1<<((bitPos+i)%8)) != 0 {
+			value |= 1 << i
+		}
+	}
+	return value
+}
+
+/* The chequers of anBoard[0], then those of anBoard[1], each point from
+ * the ace to the bar as a 1 per chequer followed by a 0. */
+func oldPositionKey(anBoard _TanBoard, auchKey *[10]byte) {
+	var iBit int
+
+	*auchKey = [10]byte{}
+
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 25; j++ {
+			nc := anBoard[i][j]
+			addBits(auchKey[:], iBit, nc, (1<<nc)-1)
+			iBit += nc + 1
+		}
+	}
+}
+
+func oldPositionFromKey(anBoard *_TanBoard, auchKey [10]byte) error {
+	var i, j int
+
+	*anBoard = _TanBoard{}
+
+	for k := 0; k < 80 && i < 2; k++ {
+		if getBits(auchKey[:], k, 1) != 0 {
+			anBoard[i][j]++
+		} else if j++; j == 25 {
+			i++
+			j = 0
+		}
+	}
+
+	if i < 2 {
+		return fmt.Errorf("too many chequers")
+	}
+
+	return nil
+}
+
+func positionID(anBoard _TanBoard) string {
+	var auchKey [10]byte
+
+	oldPositionKey(anBoard, &auchKey)
+
+	return base64.RawStdEncoding.EncodeToString(auchKey[:])
+}
+
+func positionFromID(anBoard *_TanBoard, pchEnc string) error {
+	var auchKey [10]byte
+
+	if len(pchEnc) != 14 {
+		return fmt.Errorf("a position ID has 14 characters")
+	}
+	if _, err := base64.RawStdEncoding.Strict().Decode(auchKey[:], []byte(pchEnc)); err != nil {
+		return fmt.Errorf("invalid position ID: %v", err)
+	}
+
+	if err := oldPositionFromKey(anBoard, auchKey); err != nil {
+		return err
+	}
+
+	return checkPosition(*anBoard)
+}
+
+/* Reject positions no game can reach: more than 15 chequers a side, or
+ * both sides on the same point. */
+func checkPosition(anBoard _TanBoard) error {
+	for i := 0; i < 2; i++ {
+		var ac int
+		for j := 0; j < 25; j++ {
+			ac += anBoard[i][j]
+		}
+		if ac > 15 {
+			return fmt.Errorf("player %v has %v chequers", i, ac)
+		}
+	}
+
+	for j := 0; j < 24; j++ {
+		if anBoard[0][j] > 0 && anBoard[1][23-j] > 0 {
+			return fmt.Errorf("both players have chequers on point %v", j+1)
+		}
+	}
+
+	return nil
+}
+
+// PositionID returns the gnubg position ID of a board seen from the player
+// on roll, as in Weights: board[1] holds that player's chequers.
+func PositionID(board TanBoard) string {
+	return positionID(_TanBoard(board))
+}
+
+// PositionFromID decodes a gnubg position ID into a board seen from the
+// player on roll.
+func PositionFromID(id string) (TanBoard, error) {
+	var anBoard _TanBoard
+	err := positionFromID(&anBoard, id)
+	return TanBoard(anBoard), err
+}
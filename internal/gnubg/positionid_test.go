@@ -72,3 +72,69 @@ func Test_PositionKey_fromBoard(t *testing.T) {
 		})
 	}
 }
+
+func TestPositionID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    TanBoard
+		wantErr bool
+	}{
+		{
+			name: "should decode the starting position",
+			id:   "4HPwATDgc/ABMA",
+			want: startBoard,
+		},
+		{
+			name: "should decode a position from the gnubg manual",
+			id:   "jGfkASjg8wcBMA",
+			want: TanBoard{
+				{0, 0, 2, 0, 0, 4, 0, 2, 0, 0, 1, 0, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 0},
+				{0, 0, 0, 0, 0, 5, 0, 7, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+			},
+		},
+		{
+			name: "should decode an empty board",
+			id:   "AAAAAAAAAAAAAA",
+			want: TanBoard{},
+		},
+		{
+			name:    "should fail on the wrong length",
+			id:      "4HPwATDgc/ABM",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on bad characters",
+			id:      "4HPwATDgc/AB-A",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on too many chequers",
+			id:      "////////////AA",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on both sides on a point",
+			id:      "4HPwATDgc/ABMB",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PositionFromID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PositionFromID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PositionFromID() = %v, want %v", got, tt.want)
+			}
+			if id := PositionID(got); id != tt.id {
+				t.Errorf("PositionID() = %v, want %v", id, tt.id)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package gnubg
+
+import (
+	"math"
+)
+
+// RaceDecision is the cube action a racing formula recommends in a money
+// game: whether the player on roll should double, or redouble when they
+// own the cube, and whether their opponent should take.
+type RaceDecision struct {
+	Double   bool
+	Redouble bool
+	Take     bool
+}
+
+// Race holds the racing counts of a position, as gnubg's "show pipcount",
+// "show keith", "show thorp" and "show kleinman" give them. Pairs are
+// indexed as the board: 1 for the player on roll, 0 for their opponent.
+type Race struct {
+	// no contact left between the two sides, when the formulas apply
+	Race bool
+	Pips [2]int
+
+	// Keith counts, and that of the player on roll increased by 1/7
+	Keith         [2]int
+	KeithAdjusted float32
+	KeithAction   RaceDecision
+
+	// Thorp counts, and that of the player on roll increased by 10% when
+	// over 30
+	Thorp         [2]int
+	ThorpAdjusted float32
+	ThorpAction   RaceDecision
+
+	// Kleinman's K, 0 when the player on roll is more than 4 pips behind,
+	// and the chance of winning it gives
+	Kleinman       float32
+	KleinmanWin    float32
+	KleinmanAction RaceDecision
+
+	// whether a side's chequers are all in the one-sided bearoff database,
+	// and if so the average number of rolls to bear them off, its standard
+	// deviation, the effective pip count and the wastage over the pips
+	Bearoff [2]bool
+	Rolls   [2]float32
+	RollsSD [2]float32
+	EPC     [2]float32
+	Wastage [2]float32
+}
+
+// average pips of a roll, doubles counting twice
+const pipsPerRoll = 49.0 / 6.0
+
+// AnalyseRace works out the racing counts of a board seen from the player
+// on roll, as for PositionID.
+func AnalyseRace(board TanBoard) (Race, error) {
+	var ret Race
+	var anBoard = _TanBoard(board)
+
+	var anPips [2]int
+	pipCount(anBoard, &anPips)
+	ret.Pips = anPips
+	ret.Race = !isContact(anBoard)
+
+	ret.Keith = keithCount(anBoard)
+	ret.KeithAdjusted = float32(ret.Keith[1]) * 8 / 7
+	ret.KeithAction = RaceDecision{
+		Double:   ret.KeithAdjusted-float32(ret.Keith[0]) <= 4,
+		Redouble: ret.KeithAdjusted-float32(ret.Keith[0]) <= 3,
+		Take:     ret.KeithAdjusted-float32(ret.Keith[0]) >= 2,
+	}
+
+	ret.Thorp = thorpCount(anBoard)
+	ret.ThorpAdjusted = float32(ret.Thorp[1])
+	if ret.Thorp[1] > 30 {
+		ret.ThorpAdjusted *= 1.1
+	}
+	ret.ThorpAction = RaceDecision{
+		Double:   ret.ThorpAdjusted-float32(ret.Thorp[0]) <= 2,
+		Redouble: ret.ThorpAdjusted-float32(ret.Thorp[0]) <= 1,
+		Take:     ret.ThorpAdjusted-float32(ret.Thorp[0]) > -2,
+	}
+
+	ret.Kleinman, ret.KleinmanWin = kleinmanCount(anPips[1], anPips[0])
+	ret.KleinmanAction = RaceDecision{
+		Double:   ret.Kleinman >= 0.44,
+		Redouble: ret.Kleinman >= 0.61,
+		Take:     ret.Kleinman < 1.2,
+	}
+
+	for i := 0; i < 2; i++ {
+		if !isOneSidedBearoff(pbc1, anBoard[i]) {
+			continue
+		}
+
+		var ar [4]float32
+		if err := bearoffDist(pbc1, positionBearoff(anBoard.getHomeBoard(i), pbc1.nPoints, pbc1.nChequers), nil, nil, &ar, nil, nil); err != nil {
+			return ret, err
+		}
+		ret.Bearoff[i] = true
+		ret.Rolls[i] = ar[0]
+		ret.RollsSD[i] = ar[1]
+		ret.EPC[i] = ar[0] * pipsPerRoll
+		ret.Wastage[i] = ret.EPC[i] - float32(anPips[i])
+	}
+
+	return ret, nil
+}
+
+/* whether the sides can still hit each other */
+func isContact(anBoard _TanBoard) bool {
+	var nBack, nOppBack int
+	for nOppBack = 24; nOppBack > 0 && anBoard[0][nOppBack] == 0; nOppBack-- {
+	}
+	for nBack = 24; nBack > 0 && anBoard[1][nBack] == 0; nBack-- {
+	}
+	return nBack+nOppBack > 22
+}
+
+/* whether one side's chequers are all within the points of a one-sided
+ * database */
+func isOneSidedBearoff(pbc *_BearOffContext, side [25]int) bool {
+	if pbc == nil || pbc.bt != _BEAROFF_ONESIDED {
+		return false
+	}
+
+	var n int
+	for i, c := range side {
+		if c > 0 && i >= pbc.nPoints {
+			return false
+		}
+		n += c
+	}
+	return n > 0 && n <= pbc.nChequers
+}
+
+/* pip counts plus 2 for each chequer beyond the first on the ace point, 1
+ * for each beyond the first on the deuce point and the third on the trey
+ * point, and 1 for each gap on the 4, 5 and 6 points */
+func keithCount(anBoard _TanBoard) [2]int {
+	var anCount [2]int
+	pipCount(anBoard, &anCount)
+
+	for i := 0; i < 2; i++ {
+		anCount[i] += 2 * maxInt(0, anBoard[i][0]-1)
+		anCount[i] += maxInt(0, anBoard[i][1]-1)
+		anCount[i] += maxInt(0, anBoard[i][2]-3)
+		for x := 3; x < 6; x++ {
+			if anBoard[i][x] == 0 {
+				anCount[i]++
+			}
+		}
+	}
+
+	return anCount
+}
+
+/* pip counts plus 2 for each chequer left and 1 for each on the ace point,
+ * less 1 for each home board point held */
+func thorpCount(anBoard _TanBoard) [2]int {
+	var anCount [2]int
+	pipCount(anBoard, &anCount)
+
+	for i := 0; i < 2; i++ {
+		for x := 0; x < 25; x++ {
+			anCount[i] += 2 * anBoard[i][x]
+		}
+		anCount[i] += anBoard[i][0]
+		for x := 0; x < 6; x++ {
+			if anBoard[i][x] > 0 {
+				anCount[i]--
+			}
+		}
+	}
+
+	return anCount
+}
+
+/* Kleinman's K = (D + 4)^2 / (S - 4), D being how far the player on roll
+ * leads and S the pips of both, and the chance of winning it gives */
+func kleinmanCount(nPipOnRoll int, nPipNotOnRoll int) (float32, float32) {
+	var nDiff = nPipNotOnRoll - nPipOnRoll
+	var nSum = nPipNotOnRoll + nPipOnRoll
+
+	/* a few pips from the end the formula breaks down; keep it finite */
+	var rZ = float64(nDiff+4) / (2 * math.Sqrt(float64(maxInt(nSum-4, 1))))
+	var rWin = float32(0.5 * (1 + math.Erf(rZ)))
+	if nDiff < -4 {
+		return 0, rWin
+	}
+	return float32(4 * rZ * rZ), rWin
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
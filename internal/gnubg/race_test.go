@@ -0,0 +1,71 @@
+package gnubg
+
+import (
+	"testing"
+)
+
+func TestAnalyseRace(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name  string
+		board TanBoard
+		check func(t *testing.T, r Race)
+	}{
+		{
+			name:  "should count the starting position",
+			board: TanBoard{{5: 5, 7: 3, 12: 5, 23: 2}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			check: func(t *testing.T, r Race) {
+				if r.Race || r.Pips != [2]int{167, 167} || r.Keith != [2]int{169, 169} || r.Thorp != [2]int{196, 196} {
+					t.Errorf("AnalyseRace() = %+v", r)
+				}
+				if r.Bearoff != [2]bool{} || r.EPC != [2]float32{} {
+					t.Errorf("AnalyseRace() has an EPC outside the database: %+v", r)
+				}
+			},
+		},
+		{
+			name:  "should follow each formula in a race",
+			board: TanBoard{{5: 15}, {4: 4, 5: 10}},
+			check: func(t *testing.T, r Race) {
+				if !r.Race || r.Pips != [2]int{90, 80} || r.Keith != [2]int{92, 81} || r.Thorp != [2]int{119, 106} {
+					t.Errorf("AnalyseRace() = %+v", r)
+				}
+				/* a take only by Kleinman's K, just under 1.2 */
+				if want := (RaceDecision{Double: true, Redouble: true, Take: true}); r.KleinmanAction != want {
+					t.Errorf("AnalyseRace() Kleinman %+v, want %+v", r.KleinmanAction, want)
+				}
+				if want := (RaceDecision{Double: true, Redouble: true}); r.KeithAction != want || r.ThorpAction != want {
+					t.Errorf("AnalyseRace() Keith %+v, Thorp %+v, want %+v", r.KeithAction, r.ThorpAction, want)
+				}
+				if r.Kleinman < 1.18 || r.Kleinman > 1.19 || r.KleinmanWin < 0.77 || r.KleinmanWin > 0.79 {
+					t.Errorf("AnalyseRace() Kleinman %v, %v", r.Kleinman, r.KleinmanWin)
+				}
+			},
+		},
+		{
+			name:  "should work out the effective pip count in the bearoff database",
+			board: TanBoard{{0: 2, 1: 2, 2: 2, 3: 3, 4: 3, 5: 3}, {0: 2, 1: 2, 2: 2, 3: 3, 4: 3, 5: 3}},
+			check: func(t *testing.T, r Race) {
+				if r.Bearoff != [2]bool{true, true} || r.Pips != [2]int{57, 57} {
+					t.Errorf("AnalyseRace() = %+v", r)
+				}
+				if r.EPC[1] < 60 || r.EPC[1] > 75 || r.Wastage[1] != r.EPC[1]-57 || r.Rolls[1]*pipsPerRoll != r.EPC[1] {
+					t.Errorf("AnalyseRace() EPC %v, wastage %v, rolls %v", r.EPC[1], r.Wastage[1], r.Rolls[1])
+				}
+				if (r.KeithAction != RaceDecision{Take: true}) {
+					t.Errorf("AnalyseRace() Keith %+v", r.KeithAction)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AnalyseRace(tt.board)
+			if err != nil {
+				t.Fatalf("AnalyseRace() error = %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
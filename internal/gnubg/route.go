@@ -0,0 +1,101 @@
+package gnubg
+
+import (
+	"fmt"
+)
+
+// Route tells how the engine comes by the evaluation of a position.
+type Route struct {
+	Class     string // as PositionClass names it
+	Evaluator string // database, net or rule of the static evaluation
+	// plies searched ahead; the two-sided databases and finished games
+	// are exact and aren't searched
+	Plies int
+	// sanityCheck changed the static evaluation of the position itself;
+	// evaluations searched ahead may still be adjusted at their leaves
+	SanityAdjusted bool
+	// the evaluation is in the cache, so evaluating it now returns the
+	// cached numbers; cubeful evaluations are never cached
+	Cached bool
+}
+
+// ExplainEvaluation works out the Route of evaluating a board seen from
+// the player on roll, as for EvaluatePosition, with the settings. It
+// evaluates the position statically but neither searches nor adds to the
+// cache, so call it before evaluating to see whether that will hit the
+// cache.
+func ExplainEvaluation(board TanBoard, player int, cube Cube, s Settings) (Route, error) {
+	var ret Route
+
+	ci, err := cube.cubeInfo(player)
+	if err != nil {
+		return ret, err
+	}
+
+	ec, _, err := s.evalContext()
+	if err != nil {
+		return ret, err
+	}
+
+	var anBoard = _TanBoard(board)
+	pc := classifyPosition(anBoard, ci.bgv)
+
+	ret.Class = aszPositionClass[pc]
+	ret.Evaluator = classEvaluator(pc, &ec)
+
+	switch {
+	case pc == _CLASS_OVER:
+	case pc > _CLASS_PERFECT:
+		ret.Plies = ec.nPlies
+	case ec.fCubeful && ci.nMatchTo > 0:
+		/* cubeful match play searches the two-sided databases too */
+		ret.Plies = ec.nPlies
+	}
+
+	var arOutput [_NUM_OUTPUTS]float32
+	if err := staticEvaluation(nil, anBoard, &arOutput, &ci, &ec, pc); err != nil {
+		return ret, err
+	}
+	if needsSanityCheck(pc, &ec) {
+		var arSane = arOutput
+		sanityCheck(anBoard, &arSane)
+		ret.SanityAdjusted = arSane != arOutput
+	}
+
+	/* the key evaluatePositionCache files the evaluation under */
+	if cCache != 0 && !ec.fCubeful && ec.rNoise == 0.0 && ec.pWeights == nil {
+		var e _CacheNodeDetail
+		e.key.fromBoard(anBoard)
+		e.nEvalContext = evalKey(&ec, ec.nPlies, &ci, false)
+		ret.Cached = cacheContains(&cEval, &e)
+	}
+
+	return ret, nil
+}
+
+/* name of what gives the static evaluation of a class */
+func classEvaluator(pc _PositionClass, pec *_EvalContext) string {
+	switch pc {
+	case _CLASS_OVER:
+		return "game over"
+	case _CLASS_HYPERGAMMON1, _CLASS_HYPERGAMMON2, _CLASS_HYPERGAMMON3:
+		return fmt.Sprintf("hyper%1d.bd", pc-_CLASS_HYPERGAMMON1+1)
+	case _CLASS_BEAROFF2:
+		return "gnubg_ts0.bd"
+	case _CLASS_BEAROFF_TS:
+		return "gnubg_ts.bd"
+	case _CLASS_BEAROFF1:
+		return "gnubg_os0.bd"
+	case _CLASS_BEAROFF_OS:
+		return "gnubg_os.bd"
+	}
+
+	var name = aszPositionClass[pc] + " net"
+	switch {
+	case pec.pWeights != nil:
+		return "custom " + name
+	case pec.fQuantized:
+		return "quantized " + name
+	}
+	return name
+}
@@ -0,0 +1,82 @@
+package gnubg
+
+import (
+	"testing"
+)
+
+func TestExplainEvaluation(t *testing.T) {
+	once.Do(setup)
+
+	var centred = Cube{Value: 1, Owner: -1}
+	/* a position no other test evaluates, so nothing is cached */
+	var contact = TanBoard{{5: 5, 7: 3, 12: 5, 20: 1, 23: 1}, {4: 2, 5: 4, 7: 2, 12: 5, 23: 2}}
+
+	tests := []struct {
+		name     string
+		board    TanBoard
+		s        Settings
+		evaluate bool // evaluate the position first
+		want     Route
+	}{
+		{
+			name:  "should search the contact net",
+			board: contact,
+			s:     Settings{Plies: 2, Prune: true},
+			want:  Route{Class: "contact", Evaluator: "contact net", Plies: 2},
+		},
+		{
+			name:  "should name the quantized nets",
+			board: contact,
+			s:     Settings{Quantized: true},
+			want:  Route{Class: "contact", Evaluator: "quantized contact net"},
+		},
+		{
+			name:     "should find an evaluation in the cache",
+			board:    TanBoard{{5: 4, 7: 3, 12: 5, 23: 2, 24: 1}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			s:        Settings{Plies: 1},
+			evaluate: true,
+			want:     Route{Class: "contact", Evaluator: "contact net", Plies: 1, Cached: true},
+		},
+		{
+			name:     "should not cache a noisy evaluation",
+			board:    TanBoard{{5: 3, 7: 3, 12: 5, 23: 2, 24: 2}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			s:        Settings{Noise: 0.01, Deterministic: true},
+			evaluate: true,
+			want:     Route{Class: "contact", Evaluator: "contact net"},
+		},
+		{
+			name:  "should sanity check a race without gammons",
+			board: TanBoard{{5: 3}, {5: 5, 10: 5, 14: 5}},
+			want:  Route{Class: "race", Evaluator: "race net", SanityAdjusted: true},
+		},
+		{
+			name:  "should read the one-sided database",
+			board: TanBoard{{0: 1}, {0: 1}},
+			s:     Settings{Plies: 1},
+			want:  Route{Class: "bearoff1", Evaluator: "gnubg_os0.bd", Plies: 1},
+		},
+		{
+			name:  "should not search a finished game",
+			board: TanBoard{{}, {0: 1}},
+			s:     Settings{Plies: 2},
+			want:  Route{Class: "over", Evaluator: "game over"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.evaluate {
+				if _, err := EvaluatePosition(tt.board, 1, centred, tt.s); err != nil {
+					t.Fatalf("EvaluatePosition() error = %v", err)
+				}
+			}
+
+			got, err := ExplainEvaluation(tt.board, 1, centred, tt.s)
+			if err != nil {
+				t.Fatalf("ExplainEvaluation() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExplainEvaluation() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
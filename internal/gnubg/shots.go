@@ -0,0 +1,128 @@
+package gnubg
+
+// Shots are the rolls on which one side hits the blots of the other.
+// Rolls are written with the higher die first, and each of the 21 comes
+// up once, so a roll that isn't a double counts as 2 of the 36.
+type Shots struct {
+	Shots int // rolls out of 36 that hit at least one blot
+	Rolls [][2]int
+	// rolls out of 36 that hit two blots or more
+	DoubleHits  int
+	DoubleRolls [][2]int
+	Blots       []BlotShots
+	// rolls out of 36 on which a chequer of the side with the blots enters
+	// from the bar against the hitter's home board
+	Enter int
+}
+
+// BlotShots are the rolls that hit one blot.
+type BlotShots struct {
+	Point  int // 1 to 24, counted from the blot's side
+	Shots  int // rolls out of 36 that hit it
+	Rolls  [][2]int
+	Direct bool // a hitter is within 6 pips
+}
+
+// CountShots works out the rolls on which the side 1-side of a board hits
+// the blots of side, in the layout of TanBoard. A roll hits a blot when one
+// of its legal plays does, so the points on the way, the chequers the
+// hitter has to enter from the bar first and the rule of playing both dice
+// are all taken into account.
+func CountShots(board TanBoard, side int) Shots {
+	var ret Shots
+
+	/* the hitter is on roll */
+	var anBoard = _TanBoard{board[side], board[1-side]}
+
+	var iBlot [24]int
+	for i := 0; i < 24; i++ {
+		iBlot[i] = -1
+		if anBoard[0][i] == 1 {
+			iBlot[i] = len(ret.Blots)
+			ret.Blots = append(ret.Blots, BlotShots{Point: i + 1, Direct: directShot(anBoard, i)})
+		}
+	}
+
+	ret.Enter = enterRolls(anBoard[1])
+
+	if len(ret.Blots) == 0 {
+		return ret
+	}
+
+	var tld = &_ThreadLocalData{}
+	var pml _MoveList
+	for n0 := 6; n0 >= 1; n0-- {
+		for n1 := n0; n1 >= 1; n1-- {
+			var roll = [2]int{n0, n1}
+			var c = 2
+			if n0 == n1 {
+				c = 1
+			}
+
+			/* the blots hit by any of the legal plays, and whether one of
+			 * them hits two */
+			var afHit = make([]bool, len(ret.Blots))
+			var fDouble bool
+			generateMoves(tld, &pml, anBoard, n0, n1, false)
+			for i := 0; i < pml.cMoves; i++ {
+				var anBoardMove _TanBoard
+				pml.amMoves[i].key.toBoard(&anBoardMove)
+
+				var cHit int
+				for j := 0; j < 24; j++ {
+					if iBlot[j] >= 0 && anBoardMove[0][j] == 0 {
+						afHit[iBlot[j]] = true
+						cHit++
+					}
+				}
+				fDouble = fDouble || cHit > 1
+			}
+
+			var fHit bool
+			for i, f := range afHit {
+				if f {
+					fHit = true
+					ret.Blots[i].Shots += c
+					ret.Blots[i].Rolls = append(ret.Blots[i].Rolls, roll)
+				}
+			}
+			if fHit {
+				ret.Shots += c
+				ret.Rolls = append(ret.Rolls, roll)
+			}
+			if fDouble {
+				ret.DoubleHits += c
+				ret.DoubleRolls = append(ret.DoubleRolls, roll)
+			}
+		}
+	}
+
+	return ret
+}
+
+/* whether a chequer of anBoard[1] is within 6 pips of the blot of
+ * anBoard[0] on point i; with chequers on the bar, only they are */
+func directShot(anBoard _TanBoard, i int) bool {
+	for n := 1; n <= 6; n++ {
+		var j = 23 - i + n
+		if j > 24 || (anBoard[1][24] > 0 && j != 24) {
+			continue
+		}
+		if anBoard[1][j] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+/* rolls out of 36 on which a chequer enters from the bar against the home
+ * board of anBoardOpp */
+func enterRolls(anBoardOpp [25]int) int {
+	var nClosed int
+	for i := 0; i < 6; i++ {
+		if anBoardOpp[i] >= 2 {
+			nClosed++
+		}
+	}
+	return 36 - nClosed*nClosed
+}
@@ -0,0 +1,85 @@
+package gnubg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountShots(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name       string
+		hitter     []int // points of the hitter's chequers, from its side, 0-based
+		blots      []int // points of the blots, from their side, 0-based
+		block      []int // points of the blots' side with 2 chequers
+		want       int
+		wantDouble int
+		wantDirect bool
+		wantEnter  int
+	}{
+		{name: "should hit 1 away", hitter: []int{14}, blots: []int{10}, want: 11, wantDirect: true, wantEnter: 36},
+		{name: "should hit 6 away", hitter: []int{19}, blots: []int{10}, want: 17, wantDirect: true, wantEnter: 36},
+		{name: "should hit 7 away", hitter: []int{20}, blots: []int{10}, want: 6, wantEnter: 36},
+		{name: "should hit 11 away", hitter: []int{24}, blots: []int{10}, want: 2, wantEnter: 36},
+		{name: "should hit 12 away", hitter: []int{23}, blots: []int{12}, want: 3, wantEnter: 36},
+		{name: "should be blocked by points on the way", hitter: []int{20}, blots: []int{10}, block: []int{9, 8, 7, 6, 5, 4}, want: 0, wantEnter: 36},
+		{
+			name:   "should hit two blots with one roll",
+			hitter: []int{14, 15}, blots: []int{10, 11},
+			/* 2-1, 3-1, 2-2 and 1-1 hit both, 2-1 with the same chequer */
+			want: 27, wantDouble: 6, wantDirect: true, wantEnter: 36,
+		},
+		{
+			name:   "should only hit from the bar",
+			hitter: []int{24, 14, 5, 5, 4, 4, 3, 3}, blots: []int{10},
+			/* entering with either die and hitting with a 1, or 6-5 from
+			 * the bar */
+			want: 13, wantEnter: 27,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var board TanBoard
+			for _, i := range tt.hitter {
+				board[1][i]++
+			}
+			for _, i := range tt.blots {
+				board[0][i] = 1
+			}
+			for _, i := range tt.block {
+				board[0][i] = 2
+			}
+
+			got := CountShots(board, 0)
+			if got.Shots != tt.want || got.DoubleHits != tt.wantDouble || got.Blots[0].Direct != tt.wantDirect || got.Enter != tt.wantEnter {
+				t.Errorf("CountShots() = %+v, want %v shots, %v double, direct %v, enter %v", got, tt.want, tt.wantDouble, tt.wantDirect, tt.wantEnter)
+			}
+
+			var c int
+			for _, roll := range got.Rolls {
+				if c += 2; roll[0] == roll[1] {
+					c--
+				}
+			}
+			if c != got.Shots {
+				t.Errorf("CountShots() rolls %v make %v, not %v", got.Rolls, c, got.Shots)
+			}
+		})
+	}
+}
+
+func TestCountShotsRolls(t *testing.T) {
+	once.Do(setup)
+
+	/* a blot 8 away is hit by 6-2, 5-3, 4-4 and 2-2 */
+	var board TanBoard
+	board[0][10] = 1
+	board[1][21] = 1
+
+	got := CountShots(board, 0)
+	var want = [][2]int{{6, 2}, {5, 3}, {4, 4}, {2, 2}}
+	if !reflect.DeepEqual(got.Rolls, want) || !reflect.DeepEqual(got.Blots[0].Rolls, want) || got.Shots != 6 {
+		t.Errorf("CountShots() = %+v, want rolls %v", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package gnubg
+
+// Blot is a lone chequer on a point, which the opponent may hit.
+type Blot struct {
+	Point  int  // 1 to 24, counted from the blot's side
+	Shots  int  // rolls out of 36 that hit it
+	Direct bool // a hitter is within 6 pips
+}
+
+// Structure describes the chequers of one side of a board.
+type Structure struct {
+	Blots []Blot
+	// rolls out of 36 on which the opponent hits at least one blot
+	Shots int
+	// points with 2 chequers or more, counted from the side as for Blot;
+	// those from 19 to 24 in the opponent's home board are also Anchors
+	Points  []int
+	Anchors []int
+	// longest run of made points, from the lowest point PrimeFrom; the
+	// one nearest home when there are several
+	Prime     int
+	PrimeFrom int
+	Bar       int // chequers on the bar
+	// rolls out of 36 on which the rearmost chequer gets past the
+	// opponent's points, 36 when it is past all of their chequers
+	Escapes int
+	// blots and spare chequers on the points from 4 to 11, from which new
+	// points in the home board and the outfield are made
+	Builders int
+	// made points with no spare chequers
+	Stripped []int
+}
+
+// AnalyseStructure describes the blots, shots, points, primes and
+// builders of both sides of a board, in the layout of TanBoard. Shots are
+// counted exactly, as CountShots counts them.
+func AnalyseStructure(board TanBoard) [2]Structure {
+	var ret [2]Structure
+
+	for side := 0; side < 2; side++ {
+		var anBoard, anBoardOpp = board[side], board[1-side]
+		var s = &ret[side]
+
+		var shots = CountShots(board, side)
+		for _, b := range shots.Blots {
+			s.Blots = append(s.Blots, Blot{Point: b.Point, Shots: b.Shots, Direct: b.Direct})
+		}
+		s.Shots = shots.Shots
+
+		for i := 0; i < 24; i++ {
+			if anBoard[i] >= 2 {
+				s.Points = append(s.Points, i+1)
+				if i >= 18 {
+					s.Anchors = append(s.Anchors, i+1)
+				}
+				if anBoard[i] == 2 {
+					s.Stripped = append(s.Stripped, i+1)
+				}
+			}
+		}
+
+		for i, n := 0, 0; i < 24; i++ {
+			if anBoard[i] < 2 {
+				n = 0
+				continue
+			}
+			if n++; n > s.Prime {
+				s.Prime = n
+				s.PrimeFrom = i + 2 - n
+			}
+		}
+
+		s.Bar = anBoard[24]
+
+		var nBack, nOppBack = lastChequer(anBoard), lastChequer(anBoardOpp)
+		if nBack+nOppBack > 22 {
+			s.Escapes = escapes(anBoardOpp, nBack)
+		} else {
+			s.Escapes = 36
+		}
+
+		for i := 3; i <= 10; i++ {
+			if anBoard[i] == 1 {
+				s.Builders++
+			} else if anBoard[i] > 2 {
+				s.Builders += anBoard[i] - 2
+			}
+		}
+	}
+
+	return ret
+}
+
+/* point of the rearmost chequer of a side, 24 for the bar, or -1 */
+func lastChequer(anBoard [25]int) int {
+	var i int
+	for i = 24; i >= 0 && anBoard[i] == 0; i-- {
+	}
+	return i
+}
@@ -0,0 +1,55 @@
+package gnubg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyseStructure(t *testing.T) {
+	once.Do(setup)
+	tests := []struct {
+		name  string
+		board TanBoard
+		want  [2]Structure
+	}{
+		{
+			name:  "should describe the starting position",
+			board: TanBoard{{5: 5, 7: 3, 12: 5, 23: 2}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			want: [2]Structure{
+				{Points: []int{6, 8, 13, 24}, Anchors: []int{24}, Prime: 1, PrimeFrom: 6, Escapes: 24, Builders: 4, Stripped: []int{24}},
+				{Points: []int{6, 8, 13, 24}, Anchors: []int{24}, Prime: 1, PrimeFrom: 6, Escapes: 24, Builders: 4, Stripped: []int{24}},
+			},
+		},
+		{
+			name:  "should count the shots at a slotted 5 point",
+			board: TanBoard{{4: 1, 5: 4, 7: 3, 12: 5, 23: 2}, {5: 5, 7: 3, 12: 5, 23: 2}},
+			want: [2]Structure{
+				{
+					Blots:  []Blot{{Point: 5, Shots: 15, Direct: true}},
+					Shots:  15,
+					Points: []int{6, 8, 13, 24}, Anchors: []int{24}, Prime: 1, PrimeFrom: 6, Escapes: 24, Builders: 4, Stripped: []int{24},
+				},
+				{Points: []int{6, 8, 13, 24}, Anchors: []int{24}, Prime: 1, PrimeFrom: 6, Escapes: 24, Builders: 4, Stripped: []int{24}},
+			},
+		},
+		{
+			name:  "should enter from the bar before hitting",
+			board: TanBoard{{3: 2, 4: 2, 5: 3, 6: 2, 7: 2, 12: 3, 24: 1}, {5: 5, 7: 3, 12: 5, 14: 1}},
+			want: [2]Structure{
+				{Points: []int{4, 5, 6, 7, 8, 13}, Prime: 5, PrimeFrom: 4, Bar: 1, Escapes: 25, Builders: 1, Stripped: []int{4, 5, 7, 8}},
+				{
+					Blots:  []Blot{{Point: 15, Shots: 10}},
+					Shots:  10,
+					Points: []int{6, 8, 13}, Prime: 1, PrimeFrom: 6, Escapes: 33, Builders: 4,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnalyseStructure(tt.board); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AnalyseStructure() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package gnubg
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// The nets of a weights file, in the order they are stored.
+const (
+	NetContact = iota
+	NetRace
+	NetCrashed
+	NetPruneContact
+	NetPruneCrashed
+	NetPruneRace
+	NumNets
+)
+
+// Weights is a full set of nets as stored in a gnubg.weights file, which
+// can be evaluated and trained independently of the nets the engine uses.
+//
+// Boards passed to its methods are seen from the player on roll:
+// board[1] holds the chequers of the player on roll, board[0] those of the
+// opponent, each counted from their own side. A Weights is not safe for
+// concurrent use.
+type Weights struct {
+	anNet [NumNets]_NeuralNet
+	tld   *_ThreadLocalData
+}
+
+// NewWeights returns a set of nets with cHidden hidden nodes each and
+// small random weights, ready to be trained from scratch.
+func NewWeights(cHidden int, rnd *rand.Rand) *Weights {
+	var w = &Weights{}
+	var acInput = [NumNets]int{_NUM_INPUTS, _NUM_RACE_INPUTS, _NUM_INPUTS, _NUM_PRUNING_INPUTS, _NUM_PRUNING_INPUTS, _NUM_PRUNING_INPUTS}
+
+	for i := range w.anNet {
+		neuralNetCreate(&w.anNet[i], acInput[i], cHidden, _NUM_OUTPUTS, 0.1, 1.0)
+		neuralNetRandomize(&w.anNet[i], rnd)
+	}
+
+	return w
+}
+
+// LoadWeights reads a gnubg.weights file of version 1.00 or 1.01.
+func LoadWeights(r io.Reader, name string) (*Weights, error) {
+	var w = &Weights{}
+
+	szVersion, err := readWeightsVersion(r, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range w.anNet {
+		if err := neuralNetLoadVersion(&w.anNet[i], r, szVersion); err != nil {
+			return nil, fmt.Errorf("error while loading net %v: %v", i, err)
+		}
+	}
+
+	return w, nil
+}
+
+// Save writes the nets in the gnubg.weights format. Version 1.00 is used
+// unless one of the nets needs 1.01.
+func (w *Weights) Save(wr io.Writer) error {
+	var szVersion = _WEIGHTS_VERSION
+	for i := range w.anNet {
+		if neuralNetNeedsLayers(&w.anNet[i]) {
+			szVersion = _WEIGHTS_VERSION_LAYERS
+		}
+	}
+
+	if _, err := fmt.Fprintf(wr, "GNU Backgammon %v\n", szVersion); err != nil {
+		return err
+	}
+
+	for i := range w.anNet {
+		if err := neuralNetSave(&w.anNet[i], wr, szVersion); err != nil {
+			return fmt.Errorf("error while saving net %v: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// nets evaluating a position class, main and pruning
+func classNets(pc _PositionClass) (int, int, bool) {
+	switch pc {
+	case _CLASS_RACE:
+		return NetRace, NetPruneRace, true
+	case _CLASS_CRASHED:
+		return NetCrashed, NetPruneCrashed, true
+	case _CLASS_CONTACT:
+		return NetContact, NetPruneContact, true
+	}
+	return 0, 0, false
+}
+
+func classInputs(anBoard _TanBoard, pc _PositionClass) []float32 {
+	var arInput []float32
+
+	switch pc {
+	case _CLASS_RACE:
+		arInput = make([]float32, _NUM_RACE_INPUTS)
+		calculateRaceInputs(anBoard, arInput)
+	case _CLASS_CRASHED:
+		arInput = make([]float32, _NUM_INPUTS)
+		calculateCrashedInputs(anBoard, arInput)
+	default:
+		arInput = make([]float32, _NUM_INPUTS)
+		calculateContactInputs(anBoard, arInput)
+	}
+
+	return arInput
+}
+
+// Evaluate returns the cubeless 0-ply win, win gammon, win backgammon,
+// lose gammon and lose backgammon chances of the player on roll. Positions
+// the nets don't cover (game over, bearoff databases) are evaluated the
+// same way the engine does.
+func (w *Weights) Evaluate(board TanBoard) [5]float32 {
+	var anBoard = _TanBoard(board)
+	var arOutput [_NUM_OUTPUTS]float32
+
+	pc := classifyPosition(anBoard, _VARIATION_STANDARD)
+
+	if w.evaluateClass(anBoard, pc, &arOutput, _VARIATION_STANDARD) {
+		sanityCheck(anBoard, &arOutput)
+	} else {
+		acef[pc](anBoard, &arOutput, _VARIATION_STANDARD, nil)
+	}
+
+	return arOutput
+}
+
+/* Static evaluation with the nets, the way evalRace, evalCrashed and
+ * evalContact do it; false for classes the nets don't cover. Safe for
+ * concurrent use. */
+func (w *Weights) evaluateClass(anBoard _TanBoard, pc _PositionClass, arOutput *[_NUM_OUTPUTS]float32, bgv _BGVariation) bool {
+	iNet, _, ok := classNets(pc)
+	if !ok {
+		return false
+	}
+
+	neuralNetEvaluateSSE(&w.anNet[iNet], classInputs(anBoard, pc), arOutput, nil)
+	if pc == _CLASS_RACE {
+		/* special evaluation of backgammons overrides net output */
+		evalRaceBG(anBoard, arOutput, bgv)
+	}
+
+	return true
+}
+
+/* pruning net for a position class, see findBestMoveInEval */
+func (w *Weights) pruningNet(pc _PositionClass) *_NeuralNet {
+	_, iPrune, _ := classNets(pc)
+	return &w.anNet[iPrune]
+}
+
+// Train moves the net for the board's position class, and its pruning
+// net, one step towards the target chances. It returns the squared error
+// of the main net before the step, and false if no net covers the
+// position.
+func (w *Weights) Train(board TanBoard, target [5]float32, rAlpha float32) (float32, bool) {
+	var anBoard = _TanBoard(board)
+
+	pc := classifyPosition(anBoard, _VARIATION_STANDARD)
+
+	iNet, iPrune, ok := classNets(pc)
+	if !ok {
+		return 0, false
+	}
+
+	rError := neuralNetTrain(&w.anNet[iNet], classInputs(anBoard, pc), target[:], rAlpha)
+
+	arInput := make([]float32, _NUM_PRUNING_INPUTS)
+	baseInputs(anBoard, arInput)
+	neuralNetTrain(&w.anNet[iPrune], arInput, target[:], rAlpha)
+
+	return rError, true
+}
+
+// Plays returns the position after each legal play of the dice, still
+// seen from the player who moved. It is empty if the dice can't be played.
+func (w *Weights) Plays(board TanBoard, dice [2]int) []TanBoard {
+	if w.tld == nil {
+		w.tld = &_ThreadLocalData{}
+	}
+
+	var ml _MoveList
+	generateMoves(w.tld, &ml, _TanBoard(board), dice[0], dice[1], false)
+
+	var ret = make([]TanBoard, ml.cMoves)
+	for i := 0; i < ml.cMoves; i++ {
+		var anBoard _TanBoard
+		ml.amMoves[i].key.toBoard(&anBoard)
+		ret[i] = TanBoard(anBoard)
+	}
+
+	return ret
+}
+
+// SwapSides turns a board around so that it is seen from the other player.
+func SwapSides(board TanBoard) TanBoard {
+	return TanBoard{board[1], board[0]}
+}
+
+// PositionClass names the class the engine puts a position in, which
+// decides how it is evaluated: "over", "bearoff1", "bearoff2", "race",
+// "crashed", "contact" and so on.
+func PositionClass(board TanBoard) string {
+	return aszPositionClass[classifyPosition(_TanBoard(board), _VARIATION_STANDARD)]
+}
+
+// NetInputs returns the inputs of the net evaluating the position, or nil
+// if no net covers its class.
+func NetInputs(board TanBoard) []float32 {
+	var anBoard = _TanBoard(board)
+
+	pc := classifyPosition(anBoard, _VARIATION_STANDARD)
+	if _, _, ok := classNets(pc); !ok {
+		return nil
+	}
+
+	return classInputs(anBoard, pc)
+}
+
+// PositionKey returns the engine's packed key of the position, 7 words of
+// 8 four-bit chequer counts each, as hexadecimal.
+func PositionKey(board TanBoard) string {
+	var key _PositionKey
+	key.fromBoard(_TanBoard(board))
+
+	var sz string
+	for _, n := range key.data {
+		sz += fmt.Sprintf("%08x", uint32(n))
+	}
+	return sz
+}
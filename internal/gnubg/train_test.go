@@ -0,0 +1,112 @@
+package gnubg
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+var startBoard = TanBoard{
+	{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+	{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+}
+
+func TestWeights_Evaluate(t *testing.T) {
+	once.Do(setup)
+
+	f, err := os.Open("../../cmd/bgweb-api/data/gnubg.weights")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w, err := LoadWeights(f, "gnubg.weights")
+	if err != nil {
+		t.Fatalf("LoadWeights() error = %v", err)
+	}
+
+	/* must agree with the engine's own nets */
+	var want [_NUM_OUTPUTS]float32
+	acef[_CLASS_CONTACT](_TanBoard(startBoard), &want, _VARIATION_STANDARD, nil)
+
+	if got := w.Evaluate(startBoard); got != want {
+		t.Errorf("Evaluate() = %v, want %v", got, want)
+	}
+}
+
+func TestWeights_Train(t *testing.T) {
+	once.Do(setup)
+
+	w := NewWeights(8, rand.New(rand.NewSource(1)))
+	target := [5]float32{0.6, 0.2, 0.01, 0.1, 0.005}
+
+	first, ok := w.Train(startBoard, target, 0.1)
+	if !ok {
+		t.Fatalf("Train() = %v, want true", ok)
+	}
+
+	var last float32
+	for i := 0; i < 100; i++ {
+		last, _ = w.Train(startBoard, target, 0.1)
+	}
+
+	if last >= first/10 {
+		t.Errorf("Train() error = %v after 100 steps, started at %v", last, first)
+	}
+
+	/* no net covers a finished game */
+	if _, ok := w.Train(TanBoard{{}, startBoard[1]}, target, 0.1); ok {
+		t.Errorf("Train() = %v for a finished game, want false", ok)
+	}
+}
+
+func TestWeights_Save(t *testing.T) {
+	once.Do(setup)
+
+	w := NewWeights(8, rand.New(rand.NewSource(1)))
+
+	var buf bytes.Buffer
+	if err := w.Save(&buf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	w2, err := LoadWeights(&buf, "test.weights")
+	if err != nil {
+		t.Fatalf("LoadWeights() error = %v", err)
+	}
+
+	if got, want := w2.Evaluate(startBoard), w.Evaluate(startBoard); got != want {
+		t.Errorf("Evaluate() after Save() = %v, want %v", got, want)
+	}
+}
+
+func TestPositionClass(t *testing.T) {
+	once.Do(setup)
+
+	tests := []struct {
+		name       string
+		board      TanBoard
+		want       string
+		wantInputs int
+	}{
+		{"should classify contact", startBoard, "contact", _NUM_INPUTS},
+		{"should classify race", TanBoard{{6: 5, 7: 5, 8: 5}, {6: 5, 7: 5, 8: 5}}, "race", _NUM_RACE_INPUTS},
+		{"should classify game over", TanBoard{{}, {0: 1}}, "over", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PositionClass(tt.board); got != tt.want {
+				t.Errorf("PositionClass() = %v, want %v", got, tt.want)
+			}
+			if got := NetInputs(tt.board); len(got) != tt.wantInputs {
+				t.Errorf("NetInputs() = %v inputs, want %v", len(got), tt.wantInputs)
+			}
+		})
+	}
+
+	/* 5 on the 6 and 3 on the 8, 5 on the 13, 2 on the 24 */
+	if got, want := PositionKey(startBoard), "30500000000500002000000030500000000500002000000000000000"; got != want {
+		t.Errorf("PositionKey() = %v, want %v", got, want)
+	}
+}
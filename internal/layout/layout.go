@@ -0,0 +1,50 @@
+// Package layout converts boards between the chequer layouts of the API
+// and gnubg's TanBoard.
+package layout
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+)
+
+// TanBoard converts a board from the API into gnubg's {x, o} layout.
+func TanBoard(board openapi.Board) gnubg.TanBoard {
+	return gnubg.TanBoard{Points(board.X), Points(board.O)}
+}
+
+// Board converts a board in gnubg's {x, o} layout into the API's.
+func Board(board gnubg.TanBoard) openapi.Board {
+	return openapi.Board{X: CheckerLayout(board[0]), O: CheckerLayout(board[1])}
+}
+
+// Points returns the number of chequers on each point of the layout, the
+// 1 point at index 0 and the bar at index 24.
+func Points(l openapi.CheckerLayout) [25]int {
+	var ret [25]int
+	for i, p := range fields(&l) {
+		if *p != nil {
+			ret[i] = **p
+		}
+	}
+	return ret
+}
+
+// CheckerLayout is the layout of the chequers on each point, indexed as in
+// Points. Empty points are left out.
+func CheckerLayout(points [25]int) openapi.CheckerLayout {
+	var ret openapi.CheckerLayout
+	for i, p := range fields(&ret) {
+		if n := points[i]; n != 0 {
+			*p = &n
+		}
+	}
+	return ret
+}
+
+func fields(l *openapi.CheckerLayout) [25]**int {
+	return [25]**int{
+		&l.N1, &l.N2, &l.N3, &l.N4, &l.N5, &l.N6, &l.N7, &l.N8, &l.N9, &l.N10, &l.N11, &l.N12,
+		&l.N13, &l.N14, &l.N15, &l.N16, &l.N17, &l.N18, &l.N19, &l.N20, &l.N21, &l.N22, &l.N23, &l.N24,
+		&l.Bar,
+	}
+}
@@ -0,0 +1,25 @@
+package layout
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/openapi"
+	"reflect"
+	"testing"
+)
+
+func TestBoard(t *testing.T) {
+	var n2, n5 = 2, 5
+	var board = openapi.Board{
+		X: openapi.CheckerLayout{N6: &n5, N24: &n2},
+		O: openapi.CheckerLayout{Bar: &n2},
+	}
+	var want = gnubg.TanBoard{{5: 5, 23: 2}, {24: 2}}
+
+	got := TanBoard(board)
+	if got != want {
+		t.Errorf("TanBoard() = %v, want %v", got, want)
+	}
+	if back := Board(got); !reflect.DeepEqual(back, board) {
+		t.Errorf("Board() = %+v, want %+v", back, board)
+	}
+}
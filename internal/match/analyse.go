@@ -0,0 +1,102 @@
+package match
+
+import (
+	"bgweb-api/internal/gnubg"
+	"strconv"
+	"strings"
+)
+
+// Analyse replays the match and fills in the analysis of every play with
+// a choice and every cube action, judged with the given settings.
+func Analyse(m *Match, s gnubg.Settings) error {
+	return m.Replay(func(p Position, a Action) error {
+		an, err := analyseAction(p, a, s)
+		if err != nil || an == nil {
+			return err
+		}
+		m.Games[p.Game].Actions[p.Action].Analysis = an
+		return nil
+	})
+}
+
+func analyseAction(p Position, a Action, s gnubg.Settings) (*Analysis, error) {
+	var board = p.MoverBoard()
+	var cube = p.State.Cube
+
+	switch a.Type {
+	case ActionMove:
+		played, _ := gnubg.ApplyMoves(board, a.Moves)
+		loss, cPlays, err := gnubg.PlayLoss(board, a.Dice, a.Player, cube, played, s)
+		if err != nil || cPlays < 2 {
+			return nil, err
+		}
+		best, err := gnubg.BestPlay(board, a.Dice, a.Player, cube, s)
+		if err != nil {
+			return nil, err
+		}
+		for _, play := range gnubg.LegalPlays(board, a.Dice) {
+			if play.Board == best {
+				return &Analysis{Loss: loss, Best: FormatMoves(play.Moves)}, nil
+			}
+		}
+		return &Analysis{Loss: loss}, nil
+
+	case ActionDouble:
+		d, err := gnubg.EvaluateCube(board, a.Player, cube, s)
+		if err != nil {
+			return nil, err
+		}
+		var rDouble = min32(d.DoubleTake, d.DoublePass)
+		if d.Double() {
+			return &Analysis{Best: "double"}, nil
+		}
+		return &Analysis{Loss: d.NoDouble - rDouble, Best: "no double"}, nil
+
+	case ActionTake, ActionDrop:
+		/* the doubler's decision, from their side of the board */
+		var doubler = 1 - a.Player
+		d, err := gnubg.EvaluateCube(gnubg.SwapSides(board), doubler, cube, s)
+		if err != nil {
+			return nil, err
+		}
+		var rDouble = min32(d.DoubleTake, d.DoublePass)
+		var an = Analysis{Best: "drop"}
+		if d.Take() {
+			an.Best = "take"
+		}
+		if a.Type == ActionTake {
+			an.Loss = d.DoubleTake - rDouble
+		} else {
+			an.Loss = d.DoublePass - rDouble
+		}
+		return &an, nil
+	}
+
+	return nil, nil
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// FormatMoves writes chequer moves as "8/5 6/5", with "bar" and "off".
+func FormatMoves(moves [][2]int) string {
+	var asz []string
+	for _, m := range moves {
+		asz = append(asz, formatPoint(m[0])+"/"+formatPoint(m[1]))
+	}
+	return strings.Join(asz, " ")
+}
+
+func formatPoint(n int) string {
+	switch n {
+	case 24:
+		return "bar"
+	case -1:
+		return "off"
+	}
+	return strconv.Itoa(n + 1)
+}
@@ -0,0 +1,414 @@
+package match
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Jellyfish match files, as written by most servers and programs:
+//
+//	 5 point match
+//
+//	 Game 1
+//	 alice : 0                         bob : 0
+//	  1) 31: 8/5 6/5                   64: 24/18 13/9
+//	  2)  Doubles => 2                  Takes
+//	  3) 62: 24/18 13/11               ...
+//	                                    Wins 2 points
+//
+// Each line holds the turns of the player in the left column, player 0,
+// and of the one in the right column, player 1, with points counted from
+// the side of the player moving. Files may start with "; [Name "value"]"
+// lines of match information.
+
+var (
+	reMatchLength = regexp.MustCompile(`^(\d+) point match$`)
+	reGame        = regexp.MustCompile(`^Game (\d+)$`)
+	rePlayers     = regexp.MustCompile(`^(.*\S)\s*:\s*(\d+)\s+(.*\S)\s*:\s*(\d+)$`)
+	reTurn        = regexp.MustCompile(`^\s*\d+\)`)
+	reAction      = regexp.MustCompile(`\d\d:|Doubles|Takes|Drops|Passes|Rejects|Beavers|Wins`)
+	reInfo        = regexp.MustCompile(`^;\s*\[(.+?)\s+"(.*)"\]`)
+)
+
+/* actions starting this far into a line, after its turn number, are in
+ * the right column */
+const matRightColumn = 20
+
+// MatError is a problem with a line of a match file.
+type MatError struct {
+	Line int
+	Err  error
+}
+
+func (e *MatError) Error() string {
+	return fmt.Sprintf("line %v: %v", e.Line, e.Err)
+}
+
+func (e *MatError) Unwrap() error {
+	return e.Err
+}
+
+type matReader struct {
+	m Match
+	// the game being read, with its board in the {x, o} layout, the line
+	// of each action, and whether a double is pending
+	g       *Game
+	board   gnubg.TanBoard
+	lines   [][]int
+	doubled bool
+}
+
+// ReadMat reads a Jellyfish match file. Every play is resolved against
+// the legal plays of its dice, so that plays written in short, such as
+// 13/7 for 13/8/7, or with their hits left out are understood, and the
+// match is replayed to check it. Errors are MatErrors.
+func ReadMat(r io.Reader) (Match, error) {
+	var mr = matReader{m: Match{Crawford: true}}
+	var s = bufio.NewScanner(r)
+
+	for iLine := 1; s.Scan(); iLine++ {
+		if err := mr.line(s.Text()); err != nil {
+			return mr.m, &MatError{iLine, err}
+		}
+		if mr.g != nil && len(mr.lines) > 0 {
+			/* note the line of actions added */
+			var lines = &mr.lines[len(mr.lines)-1]
+			for len(*lines) < len(mr.g.Actions) {
+				*lines = append(*lines, iLine)
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return mr.m, err
+	}
+	mr.endGame()
+
+	if len(mr.m.Games) == 0 {
+		return mr.m, fmt.Errorf("no games in match file")
+	}
+
+	if err := mr.m.Replay(nil); err != nil {
+		var re *ReplayError
+		if errors.As(err, &re) {
+			return mr.m, &MatError{mr.lines[re.Game][re.Action], re.Err}
+		}
+		return mr.m, err
+	}
+
+	return mr.m, nil
+}
+
+func (mr *matReader) line(sz string) error {
+	var trimmed = strings.TrimSpace(sz)
+
+	if sub := reInfo.FindStringSubmatch(trimmed); sub != nil {
+		mr.info(sub[1], sub[2])
+		return nil
+	}
+	if sub := reMatchLength.FindStringSubmatch(trimmed); sub != nil {
+		mr.m.MatchTo, _ = strconv.Atoi(sub[1])
+		return nil
+	}
+	if reGame.MatchString(trimmed) {
+		mr.endGame()
+		mr.m.Games = append(mr.m.Games, Game{Winner: -1})
+		mr.g = &mr.m.Games[len(mr.m.Games)-1]
+		mr.board = StartBoard
+		mr.lines = append(mr.lines, nil)
+		mr.doubled = false
+		return nil
+	}
+	if mr.g == nil || trimmed == "" {
+		return nil
+	}
+
+	if sub := rePlayers.FindStringSubmatch(trimmed); sub != nil && len(mr.g.Actions) == 0 && !reTurn.MatchString(sz) {
+		mr.m.Players = [2]string{sub[1], sub[3]}
+		mr.g.Score[0], _ = strconv.Atoi(sub[2])
+		mr.g.Score[1], _ = strconv.Atoi(sub[4])
+		mr.setCrawford()
+		return nil
+	}
+
+	/* the turns, after the turn number if any */
+	var start = 0
+	if loc := reTurn.FindStringIndex(sz); loc != nil {
+		start = loc[1]
+	}
+	var rest = sz[start:]
+
+	var locs = reAction.FindAllStringIndex(rest, -1)
+	if len(locs) == 0 {
+		if reTurn.MatchString(sz) {
+			/* a turn number alone */
+			return nil
+		}
+		return fmt.Errorf("can't read %q", trimmed)
+	}
+	if len(locs) > 2 {
+		return fmt.Errorf("more than two turns in %q", trimmed)
+	}
+
+	for i, loc := range locs {
+		var end = len(rest)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+
+		var player = i
+		if len(locs) == 1 && loc[0] >= matRightColumn {
+			player = 1
+		}
+
+		if err := mr.action(player, strings.TrimSpace(rest[loc[0]:end])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mr *matReader) info(name string, value string) {
+	switch name {
+	case "Player 1":
+		mr.m.Players[0] = value
+	case "Player 2":
+		mr.m.Players[1] = value
+	case "Site":
+		mr.m.Place = value
+	case "Event":
+		mr.m.Event = value
+	case "Round":
+		mr.m.Round = value
+	case "EventDate":
+		mr.m.Date = value
+	case "Crawford":
+		mr.m.Crawford = value == "On"
+	case "Jacoby":
+		mr.m.Jacoby = value == "On"
+	}
+}
+
+/* the Crawford game is the first one a player is a point from winning */
+func (mr *matReader) setCrawford() {
+	var m = &mr.m
+	if !m.Crawford || m.MatchTo == 0 {
+		return
+	}
+
+	var fAway = func(score [2]int) bool {
+		return score[0] == m.MatchTo-1 || score[1] == m.MatchTo-1
+	}
+
+	var iGame = len(m.Games) - 1
+	mr.g.Crawford = fAway(mr.g.Score) && (iGame == 0 || !fAway(m.Games[iGame-1].Score))
+}
+
+func (mr *matReader) endGame() {
+	var g = mr.g
+	if g == nil || g.Winner < 0 {
+		return
+	}
+
+	/* a game won without bearing off or a drop was resigned */
+	var fDrop = len(g.Actions) > 0 && g.Actions[len(g.Actions)-1].Type == ActionDrop
+	var fOver = chequers(mr.board[0]) == 0 || chequers(mr.board[1]) == 0
+	g.Resigned = !fDrop && !fOver
+}
+
+func chequers(an [25]int) int {
+	var n int
+	for _, c := range an {
+		n += c
+	}
+	return n
+}
+
+func (mr *matReader) action(player int, sz string) error {
+	var g = mr.g
+	var a = Action{Player: player}
+
+	var word, arg, _ = strings.Cut(sz, " ")
+	switch word {
+	case "Doubles":
+		a.Type = ActionDouble
+		mr.doubled = true
+	case "Takes":
+		a.Type = ActionTake
+		mr.doubled = false
+	case "Drops", "Passes", "Rejects":
+		a.Type = ActionDrop
+		mr.doubled = false
+	case "Beavers":
+		return fmt.Errorf("beavers are not supported")
+	case "Wins":
+		/* Wins 2 points [and the match] */
+		fields := strings.Fields(arg)
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "point") {
+			return fmt.Errorf("can't read %q", sz)
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("can't read %q", sz)
+		}
+		g.Winner, g.Points = player, n
+		return nil
+	default:
+		/* 31: 8/5 6/5 */
+		var err error
+		if a.Dice, err = parseMatDice(word); err != nil {
+			return err
+		}
+		if a.Moves, err = mr.resolve(player, a.Dice, strings.TrimSpace(arg)); err != nil {
+			return err
+		}
+	}
+
+	g.Actions = append(g.Actions, a)
+	return nil
+}
+
+func parseMatDice(sz string) ([2]int, error) {
+	if len(sz) != 3 || sz[0] < '1' || sz[0] > '6' || sz[1] < '1' || sz[1] > '6' || sz[2] != ':' {
+		return [2]int{}, fmt.Errorf("invalid dice: %q", sz)
+	}
+	return [2]int{int(sz[0] - '0'), int(sz[1] - '0')}, nil
+}
+
+/* a chequer going from a point to another, n times; points are as in
+ * gnubg.Play.Moves */
+type matMove struct {
+	from, to, n int
+}
+
+// Work out which legal play of the dice the notation is, and play it.
+func (mr *matReader) resolve(player int, dice [2]int, sz string) ([][2]int, error) {
+	var board = mr.board
+	if player == 1 {
+		board = gnubg.SwapSides(board)
+	}
+
+	moves, cHits, err := parseMatMoves(sz)
+	if err != nil {
+		return nil, err
+	}
+
+	var plays = gnubg.LegalPlays(board, dice)
+	if len(plays) == 0 {
+		if len(moves) > 0 {
+			return nil, fmt.Errorf("%v%v can't be played: %v", dice[0], dice[1], sz)
+		}
+		return nil, nil
+	}
+	if len(moves) == 0 {
+		return nil, fmt.Errorf("%v%v must be played", dice[0], dice[1])
+	}
+
+	/* the chequers of the player after the play */
+	var an = board[1]
+	for _, m := range moves {
+		for i := 0; i < m.n; i++ {
+			if an[m.from] == 0 {
+				return nil, fmt.Errorf("no chequer to move from %v: %v", formatPoint(m.from), sz)
+			}
+			an[m.from]--
+			if m.to >= 0 {
+				an[m.to]++
+			}
+		}
+	}
+
+	var candidates []gnubg.Play
+	for _, play := range plays {
+		if play.Board[1] == an {
+			candidates = append(candidates, play)
+		}
+	}
+	if len(candidates) > 1 {
+		/* the same chequers moved, hitting on the way or not */
+		var hit []gnubg.Play
+		for _, play := range candidates {
+			if play.Board[0][24]-board[0][24] == cHits {
+				hit = append(hit, play)
+			}
+		}
+		candidates = hit
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("illegal play of %v%v: %v", dice[0], dice[1], sz)
+	case 1:
+	default:
+		return nil, fmt.Errorf("ambiguous play of %v%v: %v", dice[0], dice[1], sz)
+	}
+
+	if player == 1 {
+		mr.board = gnubg.SwapSides(candidates[0].Board)
+	} else {
+		mr.board = candidates[0].Board
+	}
+
+	return candidates[0].Moves, nil
+}
+
+/* "24/18(2) 13/7*" and the like, with the number of hits marked */
+func parseMatMoves(sz string) ([]matMove, int, error) {
+	var ret []matMove
+	var cHits int
+
+	for _, field := range strings.Fields(sz) {
+		var n = 1
+		if i := strings.IndexByte(field, '('); i >= 0 {
+			var err error
+			if !strings.HasSuffix(field, ")") {
+				return nil, 0, fmt.Errorf("invalid move: %v", field)
+			}
+			if n, err = strconv.Atoi(field[i+1 : len(field)-1]); err != nil || n < 1 || n > 4 {
+				return nil, 0, fmt.Errorf("invalid move: %v", field)
+			}
+			field = field[:i]
+		}
+
+		cHits += n * strings.Count(field, "*")
+		var points = strings.Split(strings.ReplaceAll(field, "*", ""), "/")
+		if len(points) < 2 {
+			return nil, 0, fmt.Errorf("invalid move: %v", field)
+		}
+
+		var an = make([]int, len(points))
+		for i, p := range points {
+			var ok bool
+			if an[i], ok = parseMatPoint(p); !ok {
+				return nil, 0, fmt.Errorf("invalid move: %v", field)
+			}
+			if i > 0 && an[i] >= an[i-1] {
+				return nil, 0, fmt.Errorf("invalid move: %v", field)
+			}
+		}
+
+		ret = append(ret, matMove{an[0], an[len(an)-1], n})
+	}
+
+	return ret, cHits, nil
+}
+
+func parseMatPoint(sz string) (int, bool) {
+	switch strings.ToLower(sz) {
+	case "bar", "25":
+		return 24, true
+	case "off", "0":
+		return -1, true
+	}
+	n, err := strconv.Atoi(sz)
+	if err != nil || n < 1 || n > 24 {
+		return 0, false
+	}
+	return n - 1, true
+}
@@ -0,0 +1,91 @@
+package match
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const matMatch = `; [Site "FIBS"]
+; [Player 1 "alice"]
+; [Player 2 "bob"]
+; [EventDate "2023.05.01"]
+
+ 3 point match
+
+ Game 1
+ alice : 0                           bob : 0
+  1)                                 31: 8/5 6/5
+  2) 64: 24/14                        Doubles => 2
+  3)  Drops
+                                      Wins 1 point
+
+ Game 2
+ alice : 0                           bob : 1
+  1) 52: 13/8 13/11                  43: 24/20 24/21
+  2) 31: 8/5* 6/5                    64: bar/21 13/7
+  3)  Doubles => 2                    Takes
+  4) 66: 13/7(2) 8/2(2)
+      Wins 4 points and the match
+`
+
+func TestReadMat(t *testing.T) {
+	m, err := ReadMat(strings.NewReader(matMatch))
+	if err != nil {
+		t.Fatalf("ReadMat() error = %v", err)
+	}
+
+	if m.Players != [2]string{"alice", "bob"} || m.MatchTo != 3 || !m.Crawford || m.Place != "FIBS" || m.Date != "2023.05.01" {
+		t.Errorf("ReadMat() = %+v, want alice and bob's 3 point match", m)
+	}
+	if len(m.Games) != 2 {
+		t.Fatalf("ReadMat() = %v games, want 2", len(m.Games))
+	}
+
+	var g = m.Games[0]
+	if len(g.Actions) != 4 || g.Winner != 1 || g.Points != 1 || g.Resigned {
+		t.Errorf("ReadMat() game 1 = %+v, want 4 actions won by bob dropping", g)
+	}
+	/* 24/14 is 24/18/14, as 24/20 is blocked */
+	if a := g.Actions[1]; a.Player != 0 || FormatMoves(a.Moves) != "24/18 18/14" {
+		t.Errorf("ReadMat() game 1 action 2 = %+v, want 24/18 18/14", a)
+	}
+	if a := g.Actions[2]; a.Player != 1 || a.Type != ActionDouble {
+		t.Errorf("ReadMat() game 1 action 3 = %+v, want bob doubling", a)
+	}
+
+	g = m.Games[1]
+	if g.Score != [2]int{0, 1} || len(g.Actions) != 7 || g.Winner != 0 || g.Points != 4 || !g.Resigned {
+		t.Errorf("ReadMat() game 2 = %+v, want 7 actions won by alice resigned", g)
+	}
+}
+
+func TestReadMat_errors(t *testing.T) {
+	const header = " 3 point match\n\n Game 1\n alice : 0                           bob : 0\n"
+
+	tests := []struct {
+		name     string
+		mat      string
+		wantLine int
+		want     string
+	}{
+		{"should report an illegal play", header + "  1) 31: 8/5 6/4\n", 5, "illegal play of 31"},
+		{"should report a blocked play", header + "  1) 65: 24/19 13/7\n", 5, "illegal play of 65"},
+		{"should report an empty point", header + "  1) 31: 7/4 6/5\n", 5, "no chequer to move from 7"},
+		{"should report unreadable dice", header + "  1) 71: 8/1\n", 5, "invalid dice"},
+		{"should report a take without a double", header + "  1) 31: 8/5 6/5                    Takes\n", 5, "no double to answer"},
+		{"should report a missing play", header + "  1) 31:\n", 5, "31 must be played"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadMat(strings.NewReader(tt.mat))
+			var me *MatError
+			if !errors.As(err, &me) {
+				t.Fatalf("ReadMat() error = %v, want a MatError", err)
+			}
+			if me.Line != tt.wantLine || !strings.Contains(me.Err.Error(), tt.want) {
+				t.Errorf("ReadMat() error = %v, want line %v: %v", err, tt.wantLine, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Package match holds the record of a match or money session as read from
+// match files, replays it through the engine to check it, and analyses
+// its plays and cube actions.
+//
+// Players are numbered as in gnubg: 0 is o and 1 is x. Boards are in the
+// {x, o} layout of the API unless told otherwise.
+package match
+
+import (
+	"bgweb-api/internal/gnubg"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Match is a match, or a money session when MatchTo is 0.
+type Match struct {
+	Players [2]string
+	MatchTo int
+	// rules of the match; Crawford in match play, Jacoby in money play
+	Crawford bool
+	Jacoby   bool
+
+	Event, Round, Place, Date, Annotator, Comment string
+
+	Games []Game
+}
+
+// Game is one game of a match.
+type Game struct {
+	// score at the start of the game
+	Score    [2]int
+	Crawford bool
+	// starting position, the usual one if nil
+	Board *gnubg.TanBoard
+
+	Actions []Action
+
+	// winner and points won, Winner being -1 for an unfinished game
+	Winner   int
+	Points   int
+	Resigned bool
+}
+
+// ActionType is what a player does on their turn.
+type ActionType int
+
+const (
+	ActionMove ActionType = iota
+	ActionDouble
+	ActionTake
+	ActionDrop
+)
+
+// Action is a roll and play, or a cube action.
+type Action struct {
+	Type   ActionType
+	Player int
+	Dice   [2]int
+	// chequer moves of a play, as gnubg.Play.Moves; none when dancing
+	Moves [][2]int
+
+	// set by Analyse, or read from the file
+	Analysis *Analysis
+	Comment  string
+}
+
+// Analysis tells how an action compares to the best one.
+type Analysis struct {
+	// equity lost to the best action, normalised to the cube value, and
+	// the best action in the notation of the action type
+	Loss float32
+	Best string
+}
+
+// StartBoard is the usual starting position, the same for x and o.
+var StartBoard = gnubg.TanBoard{
+	{5: 5, 7: 3, 12: 5, 23: 2},
+	{5: 5, 7: 3, 12: 5, 23: 2},
+}
+
+// Position is the state of a game before an action.
+type Position struct {
+	Game, Action int
+	Board        gnubg.TanBoard
+	// the cube and score, with Move the player acting; Dice are those of
+	// the action
+	State gnubg.MatchState
+}
+
+// MoverBoard is the board seen from the player acting, as the engine takes
+// it.
+func (p Position) MoverBoard() gnubg.TanBoard {
+	if p.State.Move == 1 {
+		return gnubg.SwapSides(p.Board)
+	}
+	return p.Board
+}
+
+// ReadFile reads a gnubg .sgf file, or a Jellyfish .mat or .txt file, by
+// its extension, and replays it to check it. Errors are prefixed with the
+// file name.
+func ReadFile(file string) (Match, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return Match{}, err
+	}
+	defer f.Close()
+
+	var m Match
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".sgf":
+		m, err = ReadSGF(f)
+		if err == nil {
+			err = m.Replay(nil)
+		}
+	case ".mat", ".txt":
+		m, err = ReadMat(f)
+	default:
+		err = fmt.Errorf("unknown match file type")
+	}
+	if err != nil {
+		return m, fmt.Errorf("%v: %w", file, err)
+	}
+
+	return m, nil
+}
+
+// ReplayError is an action Replay finds wrong.
+type ReplayError struct {
+	// counted from 0, as in Position
+	Game, Action int
+	Err          error
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("game %v, action %v: %v", e.Game+1, e.Action+1, e.Err)
+}
+
+func (e *ReplayError) Unwrap() error {
+	return e.Err
+}
+
+// Replay plays the match through, checking every play is legal and every
+// cube action allowed, and calls visit with the position before each
+// action. Its own errors are ReplayErrors.
+func (m Match) Replay(visit func(Position, Action) error) error {
+	for iGame, g := range m.Games {
+		var p = Position{Game: iGame, Board: StartBoard}
+		if g.Board != nil {
+			p.Board = *g.Board
+		}
+		p.State = gnubg.MatchState{
+			Cube: gnubg.Cube{
+				Value:    1,
+				Owner:    -1,
+				MatchTo:  m.MatchTo,
+				Score:    g.Score,
+				Crawford: g.Crawford,
+				Jacoby:   m.Jacoby && m.MatchTo == 0,
+			},
+			GameState: 1,
+		}
+
+		var fDoubled bool
+		for iAction, a := range g.Actions {
+			p.Action = iAction
+			p.State.Move, p.State.Turn = a.Player, a.Player
+			p.State.Dice = a.Dice
+			p.State.Doubled = fDoubled
+
+			if err := p.check(a, fDoubled); err != nil {
+				return &ReplayError{iGame, iAction, err}
+			}
+			if visit != nil {
+				if err := visit(p, a); err != nil {
+					return err
+				}
+			}
+
+			switch a.Type {
+			case ActionMove:
+				board, _ := gnubg.ApplyMoves(p.MoverBoard(), a.Moves)
+				/* back to {x, o} */
+				p.Board = board
+				if a.Player == 1 {
+					p.Board = gnubg.SwapSides(board)
+				}
+			case ActionDouble:
+				fDoubled = true
+			case ActionTake:
+				fDoubled = false
+				p.State.Cube.Value *= 2
+				p.State.Cube.Owner = a.Player
+			case ActionDrop:
+				fDoubled = false
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p Position) check(a Action, fDoubled bool) error {
+	if a.Player != 0 && a.Player != 1 {
+		return fmt.Errorf("invalid player: %v", a.Player)
+	}
+
+	switch a.Type {
+	case ActionMove:
+		if fDoubled {
+			return fmt.Errorf("moving with a double pending")
+		}
+		return checkPlay(p.MoverBoard(), a.Dice, a.Moves)
+	case ActionDouble:
+		if fDoubled || !p.State.Cube.CanDouble(a.Player) {
+			return fmt.Errorf("player %v can't double", a.Player)
+		}
+	case ActionTake, ActionDrop:
+		if !fDoubled {
+			return fmt.Errorf("no double to answer")
+		}
+	default:
+		return fmt.Errorf("invalid action: %v", a.Type)
+	}
+
+	return nil
+}
+
+func checkPlay(board gnubg.TanBoard, dice [2]int, moves [][2]int) error {
+	if dice[0] < 1 || dice[0] > 6 || dice[1] < 1 || dice[1] > 6 {
+		return fmt.Errorf("invalid dice: %v", dice)
+	}
+
+	plays := gnubg.LegalPlays(board, dice)
+	if len(plays) == 0 {
+		if len(moves) > 0 {
+			return fmt.Errorf("%v can't be played", dice)
+		}
+		return nil
+	}
+
+	after, err := gnubg.ApplyMoves(board, moves)
+	if err != nil {
+		return err
+	}
+	for _, play := range plays {
+		if play.Board == after {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("illegal play of %v: %v", dice, FormatMoves(moves))
+}
@@ -0,0 +1,93 @@
+package match
+
+import (
+	"bgweb-api/internal/gnubg"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var once sync.Once
+
+func setup() {
+	if err := gnubg.Init(os.DirFS("../../cmd/bgweb-api/data")); err != nil {
+		panic(err)
+	}
+}
+
+func TestMatch_Replay(t *testing.T) {
+	var illegal = Match{Games: []Game{{Actions: []Action{
+		{Type: ActionMove, Player: 1, Dice: [2]int{3, 1}, Moves: [][2]int{{23, 20}, {23, 21}}},
+	}}}}
+	var crawford = Match{MatchTo: 3, Crawford: true, Games: []Game{{Score: [2]int{2, 0}, Crawford: true, Actions: []Action{
+		{Type: ActionDouble, Player: 1},
+	}}}}
+	var take = Match{Games: []Game{{Actions: []Action{
+		{Type: ActionTake, Player: 0},
+	}}}}
+	var dance = Match{Games: []Game{{Board: &gnubg.TanBoard{{24: 1}, {0: 2, 1: 2, 2: 2, 3: 2, 4: 2, 5: 2}}, Actions: []Action{
+		{Type: ActionMove, Player: 1, Dice: [2]int{6, 5}},
+	}}}}
+
+	tests := []struct {
+		name    string
+		m       Match
+		want    int
+		wantErr string
+	}{
+		{"should replay a match", sgfWant, 6, ""},
+		{"should replay a dance", dance, 1, ""},
+		{"should fail on an illegal play", illegal, 0, "game 1, action 1: illegal play"},
+		{"should fail on a double in the Crawford game", crawford, 0, "game 1, action 1: player 1 can't double"},
+		{"should fail on a take without a double", take, 0, "game 1, action 1: no double"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int
+			err := tt.m.Replay(func(p Position, a Action) error {
+				got++
+				return nil
+			})
+			if (err != nil) != (tt.wantErr != "") || (err != nil && !strings.HasPrefix(err.Error(), tt.wantErr)) {
+				t.Fatalf("Match.Replay() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Match.Replay() visited %v actions, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyse(t *testing.T) {
+	once.Do(setup)
+
+	var m = sgfWant
+	m.Games = append([]Game{}, sgfWant.Games...)
+	for i := range m.Games {
+		m.Games[i].Actions = append([]Action{}, sgfWant.Games[i].Actions...)
+	}
+
+	if err := Analyse(&m, gnubg.Settings{Cubeful: true}); err != nil {
+		t.Fatalf("Analyse() error = %v", err)
+	}
+
+	/* the opening 3-1 */
+	if an := m.Games[0].Actions[0].Analysis; an == nil || an.Loss != 0 || an.Best != "8/5 6/5" {
+		t.Errorf("Analyse() opening = %+v, want no loss and 8/5 6/5", an)
+	}
+	/* doubling and dropping in the opening */
+	if an := m.Games[0].Actions[2].Analysis; an == nil || an.Best != "no double" || an.Loss <= 0 {
+		t.Errorf("Analyse() double = %+v, want a loss against no double", an)
+	}
+	if an := m.Games[0].Actions[3].Analysis; an == nil || an.Best != "take" || an.Loss <= 0 {
+		t.Errorf("Analyse() drop = %+v, want a loss against take", an)
+	}
+	/* bearing off two chequers rather than one */
+	if an := m.Games[1].Actions[1].Analysis; an == nil || an.Loss != 0 {
+		t.Errorf("Analyse() bearoff = %+v, want no loss", an)
+	}
+	if sgfWant.Games[0].Actions[0].Analysis != nil {
+		t.Errorf("Analyse() changed its input")
+	}
+}
@@ -0,0 +1,576 @@
+package match
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// gnubg saves each game of a match as an SGF game tree (GM[6]) of its own:
+// a root node with the match information, an optional setup node, then a
+// node per action. White, W, is player 0 and black, B, player 1.
+//
+// Points are letters counted from black's side, 'a' being black's 1 point
+// and white's 24; 'y' is the bar and 'z' off. A play is its dice and the
+// chequer moves, as in B[31hefe]; cube actions are B[double], W[take] and
+// W[drop].
+
+type sgfProperty struct {
+	ident  string
+	values []string
+}
+
+type sgfNode []sgfProperty
+
+func (n sgfNode) get(ident string) ([]string, bool) {
+	for _, p := range n {
+		if p.ident == ident {
+			return p.values, true
+		}
+	}
+	return nil, false
+}
+
+type sgfParser struct {
+	r    *bufio.Reader
+	line int
+}
+
+// ReadSGF reads a gnubg SGF match file.
+func ReadSGF(r io.Reader) (Match, error) {
+	var ret Match
+	var p = sgfParser{r: bufio.NewReader(r), line: 1}
+
+	for {
+		c, err := p.skipSpace()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ret, err
+		}
+		if c != '(' {
+			return ret, p.errorf("expected '(', found %q", c)
+		}
+
+		nodes, err := p.gameTree()
+		if err != nil {
+			return ret, err
+		}
+		if err := ret.addSGFGame(nodes); err != nil {
+			return ret, err
+		}
+	}
+
+	if len(ret.Games) == 0 {
+		return ret, fmt.Errorf("no games in SGF file")
+	}
+
+	return ret, nil
+}
+
+func (p *sgfParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("SGF line %v: %v", p.line, fmt.Sprintf(format, a...))
+}
+
+func (p *sgfParser) read() (byte, error) {
+	c, err := p.r.ReadByte()
+	if c == '\n' {
+		p.line++
+	}
+	return c, err
+}
+
+func (p *sgfParser) skipSpace() (byte, error) {
+	for {
+		c, err := p.read()
+		if err != nil || (c != ' ' && c != '\t' && c != '\r' && c != '\n') {
+			return c, err
+		}
+	}
+}
+
+func (p *sgfParser) unread(c byte) {
+	_ = p.r.UnreadByte()
+	if c == '\n' {
+		p.line--
+	}
+}
+
+/* the main line of a game tree whose '(' is read; variations are
+ * skipped */
+func (p *sgfParser) gameTree() ([]sgfNode, error) {
+	var nodes []sgfNode
+	var fVariation bool
+
+	for {
+		c, err := p.skipSpace()
+		if err != nil {
+			return nil, p.errorf("unterminated game tree")
+		}
+
+		switch c {
+		case ';':
+			node, err := p.node()
+			if err != nil {
+				return nil, err
+			}
+			if !fVariation {
+				nodes = append(nodes, node)
+			}
+		case '(':
+			variation, err := p.gameTree()
+			if err != nil {
+				return nil, err
+			}
+			if !fVariation {
+				nodes = append(nodes, variation...)
+				fVariation = true
+			}
+		case ')':
+			return nodes, nil
+		default:
+			return nil, p.errorf("unexpected %q", c)
+		}
+	}
+}
+
+func (p *sgfParser) node() (sgfNode, error) {
+	var ret sgfNode
+
+	for {
+		c, err := p.skipSpace()
+		if err != nil {
+			return nil, p.errorf("unterminated node")
+		}
+		if c < 'A' || c > 'Z' {
+			p.unread(c)
+			return ret, nil
+		}
+
+		var prop = sgfProperty{ident: string(c)}
+		for {
+			if c, err = p.read(); err != nil {
+				return nil, p.errorf("unterminated property")
+			}
+			if c < 'A' || c > 'Z' {
+				break
+			}
+			prop.ident += string(c)
+		}
+
+		for {
+			if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+				if c, err = p.skipSpace(); err != nil {
+					return nil, p.errorf("unterminated property")
+				}
+			}
+			if c != '[' {
+				p.unread(c)
+				break
+			}
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			prop.values = append(prop.values, value)
+			if c, err = p.read(); err != nil {
+				return nil, p.errorf("unterminated game tree")
+			}
+		}
+
+		if len(prop.values) == 0 {
+			return nil, p.errorf("property %v has no value", prop.ident)
+		}
+		ret = append(ret, prop)
+	}
+}
+
+func (p *sgfParser) value() (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := p.read()
+		if err != nil {
+			return "", p.errorf("unterminated property value")
+		}
+		switch c {
+		case ']':
+			return sb.String(), nil
+		case '\\':
+			if c, err = p.read(); err != nil {
+				return "", p.errorf("unterminated property value")
+			}
+			if c == '\n' {
+				/* soft line break */
+				continue
+			}
+		}
+		sb.WriteByte(c)
+	}
+}
+
+func (m *Match) addSGFGame(nodes []sgfNode) error {
+	if len(nodes) == 0 {
+		return fmt.Errorf("empty SGF game tree")
+	}
+
+	var g = Game{Winner: -1}
+	var root = nodes[0]
+	var iGame = len(m.Games) + 1
+
+	if gm, ok := root.get("GM"); ok && gm[0] != "6" {
+		return fmt.Errorf("game %v: not a backgammon game: GM[%v]", iGame, gm[0])
+	}
+
+	for _, prop := range root {
+		var sz = prop.values[0]
+		switch prop.ident {
+		case "MI":
+			for _, v := range prop.values {
+				key, value, _ := strings.Cut(v, ":")
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					continue
+				}
+				switch key {
+				case "length":
+					m.MatchTo = n
+				case "ws":
+					g.Score[0] = n
+				case "bs":
+					g.Score[1] = n
+				}
+			}
+		case "PW":
+			m.Players[0] = sz
+		case "PB":
+			m.Players[1] = sz
+		case "RU":
+			for _, rule := range strings.Split(sz, ":") {
+				switch rule {
+				case "Crawford":
+					m.Crawford = true
+				case "CrawfordGame":
+					g.Crawford = true
+				case "Jacoby":
+					m.Jacoby = true
+				}
+			}
+		case "RE":
+			if err := g.setResult(sz); err != nil {
+				return fmt.Errorf("game %v: %v", iGame, err)
+			}
+		case "EV":
+			m.Event = sz
+		case "RO":
+			m.Round = sz
+		case "PC":
+			m.Place = sz
+		case "DT":
+			m.Date = sz
+		case "AN":
+			m.Annotator = sz
+		case "GC":
+			m.Comment = sz
+		}
+	}
+
+	for i, node := range nodes {
+		if err := g.addSGFNode(node, i == 0); err != nil {
+			return fmt.Errorf("game %v, node %v: %v", iGame, i+1, err)
+		}
+	}
+
+	m.Games = append(m.Games, g)
+	return nil
+}
+
+func (g *Game) setResult(sz string) error {
+	if sz == "" || sz == "?" || sz == "Void" || sz == "0" {
+		return nil
+	}
+	if len(sz) < 3 || (sz[0] != 'W' && sz[0] != 'B') || sz[1] != '+' {
+		return fmt.Errorf("invalid result: %v", sz)
+	}
+
+	var points = sz[2:]
+	g.Resigned = strings.HasSuffix(points, "R")
+	n, err := strconv.Atoi(strings.TrimSuffix(points, "R"))
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid result: %v", sz)
+	}
+
+	g.Winner = toPlayer(sz[0])
+	g.Points = n
+	return nil
+}
+
+func toPlayer(c byte) int {
+	if c == 'B' {
+		return 1
+	}
+	return 0
+}
+
+func (g *Game) addSGFNode(node sgfNode, fRoot bool) error {
+	for _, prop := range node {
+		var sz = prop.values[0]
+		switch prop.ident {
+		case "AE", "AW", "AB":
+			if len(g.Actions) > 0 {
+				return fmt.Errorf("setting up the board during a game is not supported")
+			}
+			if err := g.setupSGF(prop); err != nil {
+				return err
+			}
+		case "CV":
+			if sz != "1" {
+				return fmt.Errorf("setting the cube is not supported")
+			}
+		case "CP":
+			if sz != "c" {
+				return fmt.Errorf("setting the cube is not supported")
+			}
+		case "B", "W":
+			a, err := parseSGFAction(toPlayer(prop.ident[0]), sz)
+			if err != nil {
+				return err
+			}
+			g.Actions = append(g.Actions, a)
+		case "C":
+			if !fRoot && len(g.Actions) > 0 {
+				g.Actions[len(g.Actions)-1].Comment = sz
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Game) setupSGF(prop sgfProperty) error {
+	if g.Board == nil {
+		var board = StartBoard
+		g.Board = &board
+	}
+
+	var points []int
+	for _, v := range prop.values {
+		from, to, fRange := strings.Cut(v, ":")
+		if !fRange {
+			to = from
+		}
+		if len(from) != 1 || len(to) != 1 || from[0] < 'a' || to[0] > 'y' || from[0] > to[0] {
+			return fmt.Errorf("invalid point: %v", v)
+		}
+		for c := from[0]; c <= to[0]; c++ {
+			points = append(points, int(c-'a'))
+		}
+	}
+
+	/* letters count from black's side, which is x */
+	for _, n := range points {
+		switch prop.ident {
+		case "AE":
+			if n == 24 {
+				g.Board[0][24], g.Board[1][24] = 0, 0
+			} else {
+				g.Board[0][n], g.Board[1][23-n] = 0, 0
+			}
+		case "AB":
+			g.Board[0][n]++
+		case "AW":
+			if n == 24 {
+				g.Board[1][24]++
+			} else {
+				g.Board[1][23-n]++
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseSGFAction(player int, sz string) (Action, error) {
+	var a = Action{Player: player}
+
+	switch sz {
+	case "double":
+		a.Type = ActionDouble
+		return a, nil
+	case "take":
+		a.Type = ActionTake
+		return a, nil
+	case "drop":
+		a.Type = ActionDrop
+		return a, nil
+	}
+
+	if len(sz) < 2 || len(sz)%2 != 0 || sz[0] < '1' || sz[0] > '6' || sz[1] < '1' || sz[1] > '6' {
+		return a, fmt.Errorf("invalid action: %v", sz)
+	}
+	a.Dice = [2]int{int(sz[0] - '0'), int(sz[1] - '0')}
+
+	for i := 2; i < len(sz); i += 2 {
+		var m [2]int
+		for j := range m {
+			n, ok := sgfPoint(player, sz[i+j])
+			if !ok {
+				return a, fmt.Errorf("invalid action: %v", sz)
+			}
+			m[j] = n
+		}
+		a.Moves = append(a.Moves, m)
+	}
+
+	return a, nil
+}
+
+/* a point letter as a point of the player */
+func sgfPoint(player int, c byte) (int, bool) {
+	switch {
+	case c == 'y':
+		return 24, true
+	case c == 'z':
+		return -1, true
+	case c < 'a' || c > 'x':
+		return 0, false
+	case player == 1:
+		return int(c - 'a'), true
+	default:
+		return int('x' - c), true
+	}
+}
+
+func sgfLetter(player int, n int) byte {
+	switch {
+	case n == 24:
+		return 'y'
+	case n < 0:
+		return 'z'
+	case player == 1:
+		return 'a' + byte(n)
+	default:
+		return 'x' - byte(n)
+	}
+}
+
+// WriteSGF writes the match in gnubg's SGF format, with the analysis of
+// the actions as comments and move marks.
+func WriteSGF(w io.Writer, m Match) error {
+	var bw = bufio.NewWriter(w)
+
+	for iGame, g := range m.Games {
+		fmt.Fprintf(bw, "(;FF[4]GM[6]CA[UTF-8]AP[bgweb-api]MI[length:%d][game:%d][ws:%d][bs:%d]", m.MatchTo, iGame, g.Score[0], g.Score[1])
+		writeSGFProperty(bw, "PW", m.Players[0])
+		writeSGFProperty(bw, "PB", m.Players[1])
+
+		var rules []string
+		if m.Crawford {
+			rules = append(rules, "Crawford")
+		}
+		if g.Crawford {
+			rules = append(rules, "CrawfordGame")
+		}
+		if m.Jacoby {
+			rules = append(rules, "Jacoby")
+		}
+		writeSGFProperty(bw, "RU", strings.Join(rules, ":"))
+
+		if g.Winner >= 0 {
+			var sz = fmt.Sprintf("%c+%d", "WB"[g.Winner], g.Points)
+			if g.Resigned {
+				sz += "R"
+			}
+			writeSGFProperty(bw, "RE", sz)
+		}
+		writeSGFProperty(bw, "EV", m.Event)
+		writeSGFProperty(bw, "RO", m.Round)
+		writeSGFProperty(bw, "PC", m.Place)
+		writeSGFProperty(bw, "DT", m.Date)
+		writeSGFProperty(bw, "AN", m.Annotator)
+		writeSGFProperty(bw, "GC", m.Comment)
+
+		if g.Board != nil {
+			bw.WriteString("\n;AE[a:y]")
+			writeSGFSetup(bw, "AW", 0, g.Board[1])
+			writeSGFSetup(bw, "AB", 1, g.Board[0])
+		}
+
+		for _, a := range g.Actions {
+			fmt.Fprintf(bw, "\n;%c[%s]", "WB"[a.Player], formatSGFAction(a))
+			writeSGFAnalysis(bw, a)
+		}
+
+		bw.WriteString(")\n")
+	}
+
+	return bw.Flush()
+}
+
+func writeSGFProperty(bw *bufio.Writer, ident string, value string) {
+	if value == "" {
+		return
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `]`, `\]`)
+	fmt.Fprintf(bw, "%s[%s]", ident, value)
+}
+
+func writeSGFSetup(bw *bufio.Writer, ident string, player int, an [25]int) {
+	var sz string
+	for i, n := range an {
+		for j := 0; j < n; j++ {
+			sz += "[" + string(sgfLetter(player, i)) + "]"
+		}
+	}
+	if sz != "" {
+		bw.WriteString(ident + sz)
+	}
+}
+
+func formatSGFAction(a Action) string {
+	switch a.Type {
+	case ActionDouble:
+		return "double"
+	case ActionTake:
+		return "take"
+	case ActionDrop:
+		return "drop"
+	}
+
+	var sz = fmt.Sprintf("%d%d", a.Dice[0], a.Dice[1])
+	for _, m := range a.Moves {
+		sz += string([]byte{sgfLetter(a.Player, m[0]), sgfLetter(a.Player, m[1])})
+	}
+	return sz
+}
+
+/* gnubg's default thresholds of doubtful, bad and very bad actions */
+const (
+	skillDoubtful = 0.04
+	skillBad      = 0.08
+	skillVeryBad  = 0.16
+)
+
+func writeSGFAnalysis(bw *bufio.Writer, a Action) {
+	var comment = a.Comment
+
+	if an := a.Analysis; an != nil {
+		if comment != "" {
+			comment += "\n"
+		}
+		comment += fmt.Sprintf("Best: %v\nEquity lost: %.3f", an.Best, an.Loss)
+
+		switch {
+		case an.Loss >= skillVeryBad:
+			bw.WriteString("BM[2]")
+		case an.Loss >= skillBad:
+			bw.WriteString("BM[1]")
+		case an.Loss >= skillDoubtful:
+			bw.WriteString("DO[]")
+		}
+	}
+
+	writeSGFProperty(bw, "C", comment)
+}
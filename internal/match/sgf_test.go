@@ -0,0 +1,136 @@
+package match
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/* a 3-point match as gnubg saves it */
+const sgfMatch = `(;FF[4]GM[6]CA[UTF-8]AP[GNU Backgammon:1.06.002]MI[length:3][game:0][ws:0][bs:0][wtime:0][btime:0][wtimeouts:0][btimeouts:0]PW[gnubg]PB[alice]RU[Crawford]RE[B+1]EV[Club night]DT[2023-05-01]
+;B[31hefe]
+;W[64aglp]C[Running]
+;B[double]
+;W[drop])
+(;FF[4]GM[6]CA[UTF-8]AP[GNU Backgammon:1.06.002]MI[length:3][game:1][ws:0][bs:1][wtime:0][btime:0][wtimeouts:0][btimeouts:0]PW[gnubg]PB[alice]RU[Crawford]RE[B+1R]
+;AE[a:y]AW[x][w][w][v]AB[a][a][b][b]
+;W[21vxwx]
+;B[21bzaz])
+`
+
+var sgfWant = Match{
+	Players:  [2]string{"gnubg", "alice"},
+	MatchTo:  3,
+	Crawford: true,
+	Event:    "Club night",
+	Date:     "2023-05-01",
+	Games: []Game{
+		{
+			Actions: []Action{
+				{Type: ActionMove, Player: 1, Dice: [2]int{3, 1}, Moves: [][2]int{{7, 4}, {5, 4}}},
+				{Type: ActionMove, Player: 0, Dice: [2]int{6, 4}, Moves: [][2]int{{23, 17}, {12, 8}}, Comment: "Running"},
+				{Type: ActionDouble, Player: 1},
+				{Type: ActionDrop, Player: 0},
+			},
+			Winner: 1,
+			Points: 1,
+		},
+		{
+			Score: [2]int{0, 1},
+			Board: &gnubg.TanBoard{
+				{0: 2, 1: 2},
+				{0: 1, 1: 2, 2: 1},
+			},
+			Actions: []Action{
+				{Type: ActionMove, Player: 0, Dice: [2]int{2, 1}, Moves: [][2]int{{2, 0}, {1, 0}}},
+				{Type: ActionMove, Player: 1, Dice: [2]int{2, 1}, Moves: [][2]int{{1, -1}, {0, -1}}},
+			},
+			Winner:   1,
+			Points:   1,
+			Resigned: true,
+		},
+	},
+}
+
+func TestReadSGF(t *testing.T) {
+	tests := []struct {
+		name    string
+		sgf     string
+		want    Match
+		wantErr bool
+	}{
+		{
+			name: "should read a gnubg match",
+			sgf:  sgfMatch,
+			want: sgfWant,
+		},
+		{
+			name:    "should fail on another game",
+			sgf:     "(;FF[4]GM[1]SZ[19])",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on an invalid move",
+			sgf:     "(;FF[4]GM[6]\n;B[71hefe])",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on an unterminated value",
+			sgf:     "(;FF[4]GM[6]\n;B[31hefe)",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on no games",
+			sgf:     "  \n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadSGF(strings.NewReader(tt.sgf))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadSGF() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadSGF() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteSGF(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteSGF(&b, sgfWant); err != nil {
+		t.Fatalf("WriteSGF() error = %v", err)
+	}
+
+	got, err := ReadSGF(&b)
+	if err != nil {
+		t.Fatalf("ReadSGF() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, sgfWant) {
+		t.Errorf("ReadSGF(WriteSGF()) = %+v, want %+v", got, sgfWant)
+	}
+}
+
+func TestWriteSGF_analysis(t *testing.T) {
+	var m = Match{Games: []Game{{
+		Winner: -1,
+		Actions: []Action{
+			{Type: ActionMove, Player: 1, Dice: [2]int{3, 1}, Moves: [][2]int{{23, 20}, {20, 19}}, Analysis: &Analysis{Loss: 0.175, Best: "8/5 6/5"}},
+		},
+	}}}
+
+	var b bytes.Buffer
+	if err := WriteSGF(&b, m); err != nil {
+		t.Fatalf("WriteSGF() error = %v", err)
+	}
+
+	var want = ";B[31xuut]BM[2]C[Best: 8/5 6/5\nEquity lost: 0.175]"
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("WriteSGF() = %v, want it to hold %v", b.String(), want)
+	}
+}
@@ -1,21 +1,45 @@
 // Package openapi provides primitives to interact with the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen version v1.9.1 DO NOT EDIT.
+// Code generated by github.com/deepmap/oapi-codegen version v1.10.1 DO NOT EDIT.
 package openapi
 
 import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"path"
 	"strings"
 
+	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 )
 
+// Defines values for ApplyArgsNumbering.
+const (
+	ApplyArgsNumberingO ApplyArgsNumbering = "o"
+
+	ApplyArgsNumberingX ApplyArgsNumbering = "x"
+)
+
+// Defines values for ApplyArgsPlayer.
+const (
+	ApplyArgsPlayerO ApplyArgsPlayer = "o"
+
+	ApplyArgsPlayerX ApplyArgsPlayer = "x"
+)
+
+// Defines values for BearoffArgsPlayer.
+const (
+	BearoffArgsPlayerO BearoffArgsPlayer = "o"
+
+	BearoffArgsPlayerX BearoffArgsPlayer = "x"
+)
+
 // Defines values for CheckerPlayFrom.
 const (
 	CheckerPlayFromBar CheckerPlayFrom = "bar"
@@ -122,6 +146,55 @@ const (
 	CheckerPlayToOff CheckerPlayTo = "off"
 )
 
+// Defines values for FeaturesArgsPlayer.
+const (
+	FeaturesArgsPlayerO FeaturesArgsPlayer = "o"
+
+	FeaturesArgsPlayerX FeaturesArgsPlayer = "x"
+)
+
+// Defines values for GradeArgsNumbering.
+const (
+	GradeArgsNumberingO GradeArgsNumbering = "o"
+
+	GradeArgsNumberingX GradeArgsNumbering = "x"
+)
+
+// Defines values for GradeArgsPlayer.
+const (
+	GradeArgsPlayerO GradeArgsPlayer = "o"
+
+	GradeArgsPlayerX GradeArgsPlayer = "x"
+)
+
+// Defines values for InferArgsNumbering.
+const (
+	InferArgsNumberingO InferArgsNumbering = "o"
+
+	InferArgsNumberingX InferArgsNumbering = "x"
+)
+
+// Defines values for InferArgsPlayer.
+const (
+	InferArgsPlayerO InferArgsPlayer = "o"
+
+	InferArgsPlayerX InferArgsPlayer = "x"
+)
+
+// Defines values for MoveArgsLanguage.
+const (
+	MoveArgsLanguageEn MoveArgsLanguage = "en"
+
+	MoveArgsLanguageFr MoveArgsLanguage = "fr"
+)
+
+// Defines values for MoveArgsNumbering.
+const (
+	MoveArgsNumberingO MoveArgsNumbering = "o"
+
+	MoveArgsNumberingX MoveArgsNumbering = "x"
+)
+
 // Defines values for MoveArgsPlayer.
 const (
 	MoveArgsPlayerO MoveArgsPlayer = "o"
@@ -129,6 +202,271 @@ const (
 	MoveArgsPlayerX MoveArgsPlayer = "x"
 )
 
+// Defines values for NetFeaturesClass.
+const (
+	NetFeaturesClassContact NetFeaturesClass = "contact"
+
+	NetFeaturesClassCrashed NetFeaturesClass = "crashed"
+
+	NetFeaturesClassRace NetFeaturesClass = "race"
+)
+
+// Defines values for RaceArgsPlayer.
+const (
+	RaceArgsPlayerO RaceArgsPlayer = "o"
+
+	RaceArgsPlayerX RaceArgsPlayer = "x"
+)
+
+// Defines values for RouteClass.
+const (
+	RouteClassBearoff1 RouteClass = "bearoff1"
+
+	RouteClassBearoff2 RouteClass = "bearoff2"
+
+	RouteClassBearoffOs RouteClass = "bearoff-os"
+
+	RouteClassBearoffTs RouteClass = "bearoff-ts"
+
+	RouteClassContact RouteClass = "contact"
+
+	RouteClassCrashed RouteClass = "crashed"
+
+	RouteClassHypergammon1 RouteClass = "hypergammon1"
+
+	RouteClassHypergammon2 RouteClass = "hypergammon2"
+
+	RouteClassHypergammon3 RouteClass = "hypergammon3"
+
+	RouteClassOver RouteClass = "over"
+
+	RouteClassRace RouteClass = "race"
+)
+
+// Defines values for RouteArgsPlayer.
+const (
+	RouteArgsPlayerO RouteArgsPlayer = "o"
+
+	RouteArgsPlayerX RouteArgsPlayer = "x"
+)
+
+// Defines values for ShotsArgsPlayer.
+const (
+	ShotsArgsPlayerO ShotsArgsPlayer = "o"
+
+	ShotsArgsPlayerX ShotsArgsPlayer = "x"
+)
+
+// Defines values for ShotsInfoPlayer.
+const (
+	ShotsInfoPlayerO ShotsInfoPlayer = "o"
+
+	ShotsInfoPlayerX ShotsInfoPlayer = "x"
+)
+
+// Defines values for SvgArgsHome.
+const (
+	SvgArgsHomeLeft SvgArgsHome = "left"
+
+	SvgArgsHomeRight SvgArgsHome = "right"
+)
+
+// Defines values for SvgArgsNumbering.
+const (
+	SvgArgsNumberingO SvgArgsNumbering = "o"
+
+	SvgArgsNumberingX SvgArgsNumbering = "x"
+)
+
+// Defines values for SvgArgsNumbers.
+const (
+	SvgArgsNumbersNone SvgArgsNumbers = "none"
+
+	SvgArgsNumbersO SvgArgsNumbers = "o"
+
+	SvgArgsNumbersX SvgArgsNumbers = "x"
+)
+
+// Defines values for SvgArgsPerspective.
+const (
+	SvgArgsPerspectiveO SvgArgsPerspective = "o"
+
+	SvgArgsPerspectiveX SvgArgsPerspective = "x"
+)
+
+// Defines values for SvgArgsPlayer.
+const (
+	SvgArgsPlayerO SvgArgsPlayer = "o"
+
+	SvgArgsPlayerX SvgArgsPlayer = "x"
+)
+
+// Defines values for SvgArgsTheme.
+const (
+	SvgArgsThemeBlue SvgArgsTheme = "blue"
+
+	SvgArgsThemeClassic SvgArgsTheme = "classic"
+
+	SvgArgsThemeDark SvgArgsTheme = "dark"
+
+	SvgArgsThemeMono SvgArgsTheme = "mono"
+)
+
+// The position after a play, with the opponent on roll
+type AppliedMove struct {
+	Board Board `json:"board"`
+
+	// Chequers borne off
+	BorneOff int `json:"borneOff"`
+
+	// Points where a chequer is hit, counted from the mover
+	Hits []int `json:"hits"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId PositionId `json:"positionId"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid Xgid `json:"xgid"`
+}
+
+// The position is given as for `/getmoves`, and the play by `play` or `notation`.
+type ApplyArgs struct {
+	Board *Board `json:"board,omitempty"`
+
+	// 2-slot array of dice values been thrown. With a Match ID, defaults to its dice. With dice, the play is checked to be legal.
+	Dice *[]int `json:"dice,omitempty"`
+
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
+
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
+
+	// The play in standard notation instead of `play`, such as `8/5* 6/5`, `24/18(2)` or `bar/22`. Hits need not be marked.
+	Notation *string `json:"notation,omitempty"`
+
+	// Player from whose side points are counted in notation. Defaults to the player on roll.
+	Numbering *ApplyArgsNumbering `json:"numbering,omitempty"`
+
+	// The chequers moved, in the order they are moved. A chequer may be moved with more than one die at once, such as 24/20 with 3-1.
+	Play *[]CheckerPlay `json:"play,omitempty"`
+
+	// Player on roll. With a Match ID, defaults to its player on roll.
+	Player *ApplyArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// Player from whose side points are counted in notation. Defaults to the player on roll.
+type ApplyArgsNumbering string
+
+// Player on roll. With a Match ID, defaults to its player on roll.
+type ApplyArgsPlayer string
+
+// The position is given as for `/getmoves`, without dice.
+type BearoffArgs struct {
+	Board *Board `json:"board,omitempty"`
+
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
+
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
+
+	// Player on roll. With a Match ID, defaults to its player on roll.
+	Player *BearoffArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// Player on roll. With a Match ID, defaults to its player on roll.
+type BearoffArgsPlayer string
+
+// Money game cube decisions of the player on roll, before rolling
+type BearoffCubeInfo struct {
+	// Cube decision, with equities normalised to the cube before doubling
+	Double CubeAction `json:"double"`
+
+	// Equities of the player on roll, normalised to the cube
+	Equities CubefulEquities `json:"equities"`
+
+	// Were the equities read from the two-sided bearoff database? If not, they were evaluated by the neural nets.
+	Exact bool `json:"exact"`
+
+	// Evaluation details
+	Info *EvalInfo `json:"info,omitempty"`
+
+	// Cube decision, with equities normalised to the cube before doubling
+	Redouble CubeAction `json:"redouble"`
+}
+
+// BearoffDistribution defines model for BearoffDistribution.
+type BearoffDistribution struct {
+	// Probability of bearing off the first chequer, saving the gammon, in exactly n rolls, at index n
+	Gammon []float32 `json:"gammon"`
+
+	// Expected number of rolls to bear off the first chequer
+	GammonRolls float32 `json:"gammonRolls"`
+
+	// Standard deviation of the rolls to bear off the first chequer
+	GammonRollsSd float32 `json:"gammonRollsSd"`
+
+	// Probability of bearing off all chequers in exactly n rolls, at index n
+	Off []float32 `json:"off"`
+
+	// Expected number of rolls to bear off all chequers
+	Rolls float32 `json:"rolls"`
+
+	// Standard deviation of the rolls to bear off all chequers
+	RollsSd float32 `json:"rollsSd"`
+}
+
+// Bearoff distributions of both players, and the chances of the player on roll in the race, worked out from them as gnubg evaluates one-sided bearoffs
+type BearoffInfo struct {
+	// Probability of the player on roll losing a gammon
+	LoseG float32             `json:"loseG"`
+	O     BearoffDistribution `json:"o"`
+
+	// Probability of the player on roll winning
+	Win float32 `json:"win"`
+
+	// Probability of the player on roll winning a gammon
+	WinG float32             `json:"winG"`
+	X    BearoffDistribution `json:"x"`
+}
+
+// A lone chequer the opponent may hit
+type Blot struct {
+	// Is one of the opponent's chequers within 6 pips?
+	Direct bool `json:"direct"`
+	Point  int  `json:"point"`
+
+	// Rolls out of 36 that hit it
+	Shots int `json:"shots"`
+}
+
+// BlotShots defines model for BlotShots.
+type BlotShots struct {
+	// Is one of the opponent's chequers within 6 pips?
+	Direct bool `json:"direct"`
+
+	// Point of the blot, counted from the player's side
+	Point       int     `json:"point"`
+	Probability float32 `json:"probability"`
+	Rolls       []Roll  `json:"rolls"`
+
+	// Rolls out of 36 that hit it
+	Shots int `json:"shots"`
+}
+
 // Board defines model for Board.
 type Board struct {
 	// Number of checkers in each point on the board.
@@ -182,6 +520,54 @@ type CheckerPlayFrom string
 // Point where the checker will move
 type CheckerPlayTo string
 
+// Cube decision, with equities normalised to the cube before doubling
+type CubeAction struct {
+	// Is doubling right?
+	Double     bool    `json:"double"`
+	DoublePass float32 `json:"doublePass"`
+	DoubleTake float32 `json:"doubleTake"`
+	NoDouble   float32 `json:"noDouble"`
+
+	// Is taking right?
+	Take bool `json:"take"`
+}
+
+// Equities of the player on roll, normalised to the cube
+type CubefulEquities struct {
+	// Equity with the cube centred
+	Centred float32 `json:"centred"`
+
+	// Equity with the cube unavailable
+	Cubeless float32 `json:"cubeless"`
+
+	// Equity with the opponent owning the cube
+	OppOwned float32 `json:"oppOwned"`
+
+	// Equity owning the cube
+	Owned float32 `json:"owned"`
+}
+
+// EffectivePipCount defines model for EffectivePipCount.
+type EffectivePipCount struct {
+	// Average rolls to bear off times the average pips of a roll, 49/6
+	Epc float32 `json:"epc"`
+
+	// Average rolls to bear off
+	Rolls float32 `json:"rolls"`
+
+	// Standard deviation of the rolls to bear off
+	RollsSd float32 `json:"rollsSd"`
+
+	// Effective pip count over the pip count
+	Wastage float32 `json:"wastage"`
+}
+
+// Effective pip count of each player whose chequers are all in the one-sided bearoff database, which covers up to 15 chequers in the home board.
+type EffectivePipCounts struct {
+	O *EffectivePipCount `json:"o,omitempty"`
+	X *EffectivePipCount `json:"x,omitempty"`
+}
+
 // Evaluation details
 type EvalInfo struct {
 	// Was cube decision considered?
@@ -189,6 +575,9 @@ type EvalInfo struct {
 
 	// How many turns ahead was considered. Typically 3, but may terminate earlier if probabilty already looks certain.
 	Plies int `json:"plies"`
+
+	// How the engine came by an evaluation
+	Route *Route `json:"route,omitempty"`
 }
 
 // Score of the move
@@ -206,81 +595,999 @@ type Evaluation struct {
 	Probability *Probability `json:"probability,omitempty"`
 }
 
-// Backgammon move
-type Move struct {
-	// Score of the move
-	Evaluation *Evaluation    `json:"evaluation,omitempty"`
-	Play       *[]CheckerPlay `json:"play,omitempty"`
+// Inputs describing one player's chequers, as the net takes them. The contact and crashed nets' are `off1` to `off3` for the chequers borne off, `breakContact`, `backChequer`, `backAnchor`, `forwardAnchor`, `pipLoss`, `p1` and `p2` for the rolls hitting one and two chequers, `backEscapes`, `aContain`, `aContain2`, `contain`, `contain2`, `mobility`, `moment2`, `enter`, `enter2`, `timing`, `backbone`, `backG`, `backG1`, `freePip` and `backRescapes`. The race net's are `menOff1` to `menOff14`, the one for the number of chequers borne off being 1, and `crossovers`.
+type Features struct {
+	AdditionalProperties map[string]float32 `json:"-"`
 }
 
-// MoveArgs defines model for MoveArgs.
-type MoveArgs struct {
-	Board Board `json:"board"`
+// The position is given as for `/getmoves`, without dice.
+type FeaturesArgs struct {
+	Board *Board `json:"board,omitempty"`
+
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
+
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
+
+	// Player on roll. With a Match ID, defaults to its player on roll.
+	Player *FeaturesArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// Player on roll. With a Match ID, defaults to its player on roll.
+type FeaturesArgsPlayer string
+
+// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+type FibsBoard string
+
+// The position is given as for `/getmoves`, and the play by `play` or `notation`.
+type GradeArgs struct {
+	Board *Board `json:"board,omitempty"`
 
 	// Is doubling cube in use?
 	Cubeful *bool `json:"cubeful,omitempty"`
 
-	// 2-slot array of dice values been thrown
-	Dice []int `json:"dice"`
+	// 2-slot array of dice values been thrown. With a Match ID, defaults to its dice.
+	Dice *[]int `json:"dice,omitempty"`
 
-	// Max number of moves to return. if not supplied means return all available moves.
-	MaxMoves *int `json:"max-moves,omitempty"`
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
 
-	// Player on roll
-	Player MoveArgsPlayer `json:"player"`
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
 
-	// Whether or not to calculate equities for each available move. Takes longer.
-	ScoreMoves *bool `json:"score-moves,omitempty"`
+	// The play in standard notation instead of `play`, such as `8/5* 6/5`, `24/18(2)` or `bar/22`. Hits need not be marked.
+	Notation *string `json:"notation,omitempty"`
+
+	// Player from whose side points are counted in notation. Defaults to the player on roll.
+	Numbering *GradeArgsNumbering `json:"numbering,omitempty"`
+
+	// The chequers moved, in the order they are moved. A chequer may be moved with more than one die at once, such as 24/20 with 3-1.
+	Play *[]CheckerPlay `json:"play,omitempty"`
+
+	// Player on roll. With a Match ID, defaults to its player on roll.
+	Player *GradeArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// Evaluate with the int8 quantized neural nets. Defaults to the server setting.
+	Quantized *bool `json:"quantized,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
 }
 
-// Player on roll
-type MoveArgsPlayer string
+// Player from whose side points are counted in notation. Defaults to the player on roll.
+type GradeArgsNumbering string
 
-// Probabilty of win/lose after making this move. Values are percentage proportions of 1, i.e. 1 means 100% certainty, 0.5 means 50% etc.
-type Probability struct {
-	// Probabilty of losing the game. Always `1 - win`
-	Lose float32 `json:"lose"`
+// Player on roll. With a Match ID, defaults to its player on roll.
+type GradeArgsPlayer string
 
-	// Probabilty of losing a backgammon
-	LoseBG float32 `json:"loseBG"`
+// The opponent's rolls that hit a player's blots. Rolls are written with the higher die first; each roll that isn't a double comes up 2 times out of 36.
+type HitChances struct {
+	Blots                []BlotShots `json:"blots"`
+	DoubleHitProbability float32     `json:"doubleHitProbability"`
+	DoubleHitRolls       []Roll      `json:"doubleHitRolls"`
 
-	// Probabilty of losing a gammon
-	LoseG float32 `json:"loseG"`
+	// Rolls out of 36 that hit two blots or more
+	DoubleHits int `json:"doubleHits"`
 
-	// Probabilty of winning the game
-	Win float32 `json:"win"`
+	// Rolls out of 36 on which a chequer of the player enters from the bar against the opponent's home board
+	Enter            int     `json:"enter"`
+	EnterProbability float32 `json:"enterProbability"`
 
-	// Probability of winning a backgammon
-	WinBG float32 `json:"winBG"`
+	// Rolls out of 36 that hit at least one blot
+	Hits        int     `json:"hits"`
+	Probability float32 `json:"probability"`
+	Rolls       []Roll  `json:"rolls"`
+}
 
-	// Probability of winning a gammon
-	WinG float32 `json:"winG"`
+// The position before the turn is given as for `/getmoves`.
+type InferArgs struct {
+	After Board  `json:"after"`
+	Board *Board `json:"board,omitempty"`
+
+	// 2-slot array of dice values been thrown. With a Match ID, defaults to its dice. Without dice, every roll is tried.
+	Dice *[]int `json:"dice,omitempty"`
+
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
+
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
+
+	// Player from whose side points are counted in notation. Defaults to the player on roll.
+	Numbering *InferArgsNumbering `json:"numbering,omitempty"`
+
+	// Player on roll. With a Match ID, defaults to its player on roll.
+	Player *InferArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
 }
 
-// PostGetmovesJSONBody defines parameters for PostGetmoves.
-type PostGetmovesJSONBody MoveArgs
+// Player from whose side points are counted in notation. Defaults to the player on roll.
+type InferArgsNumbering string
 
-// PostGetmovesJSONRequestBody defines body for PostGetmoves for application/json ContentType.
-type PostGetmovesJSONRequestBody PostGetmovesJSONBody
+// Player on roll. With a Match ID, defaults to its player on roll.
+type InferArgsPlayer string
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Get moves
-	// (POST /getmoves)
-	PostGetmoves(ctx echo.Context) error
+// A legal play reaching the board after the turn
+type InferredMove struct {
+	// Chequers borne off
+	BorneOff int `json:"borneOff"`
+
+	// Dice of a roll, the higher first
+	Dice Roll `json:"dice"`
+
+	// Points where a chequer is hit, counted from the mover
+	Hits []int `json:"hits"`
+
+	// The play in standard notation
+	Notation string `json:"notation"`
+
+	// The chequers moved, a die at a time; none when the roll can't be played
+	Play []CheckerPlay `json:"play"`
 }
 
-// ServerInterfaceWrapper converts echo contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler ServerInterface
+// KleinmanCount defines model for KleinmanCount.
+type KleinmanCount struct {
+	// Cube action a racing formula recommends
+	Action RaceAction `json:"action"`
+
+	// Kleinman's K, (D + 4)² / (S - 4), where D is the lead of the player on roll and S the pips of both. 0 when they are more than 4 pips behind.
+	K float32 `json:"k"`
+
+	// Chance of the player on roll winning the race, by Kleinman's formula
+	Win float32 `json:"win"`
 }
 
-// PostGetmoves converts echo context to params.
-func (w *ServerInterfaceWrapper) PostGetmoves(ctx echo.Context) error {
+// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+type MatchId string
+
+// Backgammon move
+type Move struct {
+	// Why the move is best or what makes it worse than the first, when asked for
+	Commentary *string `json:"commentary,omitempty"`
+
+	// Score of the move
+	Evaluation *Evaluation `json:"evaluation,omitempty"`
+
+	// The play in standard notation, with the moves of a chequer joined, hits marked and identical moves counted
+	Notation *string        `json:"notation,omitempty"`
+	Play     *[]CheckerPlay `json:"play,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// The position is given by `board` or `positionId`, the player on roll and dice by `player` and `dice` or by `matchId`, which also sets the cube and score. An `xgid` or `fibsBoard` gives all of them at once and can't be combined with the others, `player` and `dice` aside.
+type MoveArgs struct {
+	Board *Board `json:"board,omitempty"`
+
+	// Add a sentence to each move explaining why the first is best, or what makes the others worse, from the equities, gammon chances and structure of the moves. Needs the moves scored.
+	Commentary *bool `json:"commentary,omitempty"`
+
+	// Is doubling cube in use?
+	Cubeful *bool `json:"cubeful,omitempty"`
+
+	// 2-slot array of dice values been thrown. With a Match ID, defaults to its dice.
+	Dice *[]int `json:"dice,omitempty"`
+
+	// Add the route of the evaluation of the position after each move to its evaluation info.
+	Explain *bool `json:"explain,omitempty"`
+
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
+
+	// Language of the commentary
+	Language *MoveArgsLanguage `json:"language,omitempty"`
+
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
+
+	// Max number of moves to return. if not supplied means return all available moves.
+	MaxMoves *int `json:"max-moves,omitempty"`
+
+	// Player from whose side points are counted in notation. Defaults to the player on roll.
+	Numbering *MoveArgsNumbering `json:"numbering,omitempty"`
+
+	// Player on roll. With a Match ID, defaults to its player on roll.
+	Player *MoveArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// Evaluate with the int8 quantized neural nets. Faster, slightly less accurate. Defaults to the server setting.
+	Quantized *bool `json:"quantized,omitempty"`
+
+	// Whether or not to calculate equities for each available move. Takes longer.
+	ScoreMoves *bool `json:"score-moves,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// Language of the commentary
+type MoveArgsLanguage string
+
+// Player from whose side points are counted in notation. Defaults to the player on roll.
+type MoveArgsNumbering string
+
+// Player on roll. With a Match ID, defaults to its player on roll.
+type MoveArgsPlayer string
+
+// A legal play scored against the best move
+type MoveGrade struct {
+	// Backgammon move
+	Best Move `json:"best"`
+
+	// Equity given up against the best move, 0 for the best
+	Error float32 `json:"error"`
+
+	// Backgammon move
+	Move Move `json:"move"`
+
+	// Number of legal moves
+	Moves int `json:"moves"`
+
+	// Rank of the play among the legal moves, 1 for the best
+	Rank int `json:"rank"`
+}
+
+// The legal plays reaching the board after the turn
+type MoveInference struct {
+	// A play for each roll that reaches the board, higher rolls first. A roll's plays reaching the same board are one move, given once.
+	Moves []InferredMove `json:"moves"`
+}
+
+// MoveShots defines model for MoveShots.
+type MoveShots struct {
+	Play *[]CheckerPlay `json:"play,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// The opponent's rolls that hit a player's blots. Rolls are written with the higher die first; each roll that isn't a double comes up 2 times out of 36.
+	Shots HitChances `json:"shots"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// Structure of the position after a move
+type MoveStructure struct {
+	// How the move differs from the first one, the best when the moves are scored, such as leaving more shots or breaking a point; empty for the first move
+	Comparison []string `json:"comparison"`
+
+	// Score of the move
+	Evaluation *Evaluation `json:"evaluation,omitempty"`
+
+	// Chequers the move hits
+	Hits int `json:"hits"`
+
+	// Structure of one player's chequers. Points are counted from the player's side.
+	O    Structure     `json:"o"`
+	Play []CheckerPlay `json:"play"`
+
+	// Structure of one player's chequers. Points are counted from the player's side.
+	X Structure `json:"x"`
+}
+
+// Inputs of the neural net evaluating a position, by name
+type NetFeatures struct {
+	// Class of the position, which is also the net evaluating it
+	Class NetFeaturesClass `json:"class"`
+
+	// Inputs describing one player's chequers, as the net takes them. The contact and crashed nets' are `off1` to `off3` for the chequers borne off, `breakContact`, `backChequer`, `backAnchor`, `forwardAnchor`, `pipLoss`, `p1` and `p2` for the rolls hitting one and two chequers, `backEscapes`, `aContain`, `aContain2`, `contain`, `contain2`, `mobility`, `moment2`, `enter`, `enter2`, `timing`, `backbone`, `backG`, `backG1`, `freePip` and `backRescapes`. The race net's are `menOff1` to `menOff14`, the one for the number of chequers borne off being 1, and `crossovers`.
+	O Features `json:"o"`
+
+	// Inputs describing one player's chequers, as the net takes them. The contact and crashed nets' are `off1` to `off3` for the chequers borne off, `breakContact`, `backChequer`, `backAnchor`, `forwardAnchor`, `pipLoss`, `p1` and `p2` for the rolls hitting one and two chequers, `backEscapes`, `aContain`, `aContain2`, `contain`, `contain2`, `mobility`, `moment2`, `enter`, `enter2`, `timing`, `backbone`, `backG`, `backG1`, `freePip` and `backRescapes`. The race net's are `menOff1` to `menOff14`, the one for the number of chequers borne off being 1, and `crossovers`.
+	X Features `json:"x"`
+}
+
+// Class of the position, which is also the net evaluating it
+type NetFeaturesClass string
+
+// PipCounts defines model for PipCounts.
+type PipCounts struct {
+	O int `json:"o"`
+	X int `json:"x"`
+}
+
+// gnubg Position ID, seen from the player on roll
+type PositionId string
+
+// Longest run of made points, the one nearest home when there are several; 0 long without any made point
+type Prime struct {
+	From   int `json:"from"`
+	Length int `json:"length"`
+	To     int `json:"to"`
+}
+
+// Probabilty of win/lose after making this move. Values are percentage proportions of 1, i.e. 1 means 100% certainty, 0.5 means 50% etc.
+type Probability struct {
+	// Probabilty of losing the game. Always `1 - win`
+	Lose float32 `json:"lose"`
+
+	// Probabilty of losing a backgammon
+	LoseBG float32 `json:"loseBG"`
+
+	// Probabilty of losing a gammon
+	LoseG float32 `json:"loseG"`
+
+	// Probabilty of winning the game
+	Win float32 `json:"win"`
+
+	// Probability of winning a backgammon
+	WinBG float32 `json:"winBG"`
+
+	// Probability of winning a gammon
+	WinG float32 `json:"winG"`
+}
+
+// Cube action a racing formula recommends
+type RaceAction struct {
+	// Should the player on roll double with a centred cube?
+	Double bool `json:"double"`
+
+	// Should the player on roll redouble when owning the cube?
+	Redouble bool `json:"redouble"`
+
+	// Should their opponent take?
+	Take bool `json:"take"`
+}
+
+// The position is given as for `/getmoves`, without dice.
+type RaceArgs struct {
+	Board *Board `json:"board,omitempty"`
+
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
+
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
+
+	// Player on roll. With a Match ID, defaults to its player on roll.
+	Player *RaceArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// Player on roll. With a Match ID, defaults to its player on roll.
+type RaceArgsPlayer string
+
+// Counts of a racing formula. Keith's adds 1/7 to the count of the player on roll, Thorp's 10% when over 30.
+type RaceCount struct {
+	// Cube action a racing formula recommends
+	Action RaceAction `json:"action"`
+
+	// Count of the player on roll, adjusted
+	Adjusted float32 `json:"adjusted"`
+	O        int     `json:"o"`
+	X        int     `json:"x"`
+}
+
+// Racing counts of a position. The formulas are meant for races, and recommend the cube action of the player on roll in a money game.
+type RaceInfo struct {
+	// Effective pip count of each player whose chequers are all in the one-sided bearoff database, which covers up to 15 chequers in the home board.
+	Epc EffectivePipCounts `json:"epc"`
+
+	// Counts of a racing formula. Keith's adds 1/7 to the count of the player on roll, Thorp's 10% when over 30.
+	Keith    RaceCount     `json:"keith"`
+	Kleinman KleinmanCount `json:"kleinman"`
+	Pips     PipCounts     `json:"pips"`
+
+	// Is there no contact left?
+	Race bool `json:"race"`
+
+	// Counts of a racing formula. Keith's adds 1/7 to the count of the player on roll, Thorp's 10% when over 30.
+	Thorp RaceCount `json:"thorp"`
+}
+
+// Dice of a roll, the higher first
+type Roll []int
+
+// How the engine came by an evaluation
+type Route struct {
+	// Was the evaluation already in the cache? Cubeful evaluations are never cached.
+	Cached bool `json:"cached"`
+
+	// Class the engine put the position in. `bearoff2` and `bearoff-ts` are in the two-sided bearoff databases, `bearoff1` and `bearoff-os` in the one-sided ones.
+	Class RouteClass `json:"class"`
+
+	// Bearoff database file or neural net giving the static evaluation of the position, or `game over`.
+	Evaluator string `json:"evaluator"`
+
+	// How many turns ahead was considered, counted as in the evaluation info. 1 when the position was only evaluated statically, as the two-sided bearoff databases are exact.
+	Plies int `json:"plies"`
+
+	// Did the sanity check correct the static evaluation of the position, such as gammon chances that can't happen?
+	SanityAdjusted bool `json:"sanityAdjusted"`
+}
+
+// Class the engine put the position in. `bearoff2` and `bearoff-ts` are in the two-sided bearoff databases, `bearoff1` and `bearoff-os` in the one-sided ones.
+type RouteClass string
+
+// The position is given as for `/getmoves`, without dice.
+type RouteArgs struct {
+	Board *Board `json:"board,omitempty"`
+
+	// Is doubling cube in use?
+	Cubeful *bool `json:"cubeful,omitempty"`
+
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
+
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
+
+	// Player on roll. With a Match ID, defaults to its player on roll.
+	Player *RouteArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// Evaluate with the int8 quantized neural nets. Defaults to the server setting.
+	Quantized *bool `json:"quantized,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// Player on roll. With a Match ID, defaults to its player on roll.
+type RouteArgsPlayer string
+
+// The position is given as for `/getmoves`; the dice may be left out. The player's blots are counted, with the opponent rolling.
+type ShotsArgs struct {
+	Board *Board `json:"board,omitempty"`
+
+	// 2-slot array of the player's dice, to rank their moves. With a Match ID, defaults to its dice.
+	Dice *[]int `json:"dice,omitempty"`
+
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
+
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
+
+	// Max number of moves to return; all of them if not supplied
+	MaxMoves *int `json:"max-moves,omitempty"`
+
+	// Player whose blots are counted, and whose moves are ranked with dice. With a Match ID, defaults to its player on roll.
+	Player *ShotsArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// Player whose blots are counted, and whose moves are ranked with dice. With a Match ID, defaults to its player on roll.
+type ShotsArgsPlayer string
+
+// ShotsInfo defines model for ShotsInfo.
+type ShotsInfo struct {
+	// The player's legal moves with the shots each leaves, safest first; only with dice
+	Moves  *[]MoveShots    `json:"moves,omitempty"`
+	Player ShotsInfoPlayer `json:"player"`
+
+	// The opponent's rolls that hit a player's blots. Rolls are written with the higher die first; each roll that isn't a double comes up 2 times out of 36.
+	Shots HitChances `json:"shots"`
+}
+
+// ShotsInfoPlayer defines model for ShotsInfo.Player.
+type ShotsInfoPlayer string
+
+// Structure of one player's chequers. Points are counted from the player's side.
+type Structure struct {
+	// Points made in the opponent's home board, 19 to 24
+	Anchors []int `json:"anchors"`
+
+	// Chequers on the bar
+	Bar   int    `json:"bar"`
+	Blots []Blot `json:"blots"`
+
+	// Blots and spare chequers on the points from 4 to 11, from which new points are made
+	Builders int `json:"builders"`
+
+	// Rolls out of 36 on which the rearmost chequer gets past the opponent's points, 36 once it is past all of their chequers
+	Escapes int `json:"escapes"`
+
+	// Points with 2 chequers or more
+	Points []int `json:"points"`
+
+	// Longest run of made points, the one nearest home when there are several; 0 long without any made point
+	Prime Prime `json:"prime"`
+
+	// Rolls out of 36 on which the opponent hits at least one blot. Counted exactly, as `/getshots` counts them.
+	Shots int `json:"shots"`
+
+	// Made points with no spare chequers
+	Stripped []int `json:"stripped"`
+}
+
+// Structure of a position and of the positions after its moves
+type StructureInfo struct {
+	// Moves of the dice, if given, best first
+	Moves *[]MoveStructure `json:"moves,omitempty"`
+
+	// Structure of one player's chequers. Points are counted from the player's side.
+	O Structure `json:"o"`
+
+	// Structure of one player's chequers. Points are counted from the player's side.
+	X Structure `json:"x"`
+}
+
+// The position is given as for `/getmoves`; the dice may be left out. The cube, and the dice of the player on roll, are drawn from the Match ID, XGID or FIBS board.
+type SvgArgs struct {
+	Board *Board `json:"board,omitempty"`
+
+	// 2-slot array of dice values been thrown. With a Match ID, defaults to its dice.
+	Dice *[]int `json:"dice,omitempty"`
+
+	// FIBS `board:` string of the position, cube and score. FIBS's X is x and O is o.
+	FibsBoard *FibsBoard `json:"fibsBoard,omitempty"`
+
+	// Side of the bottom player's home board.
+	Home *SvgArgsHome `json:"home,omitempty"`
+
+	// gnubg Match ID, holding the player on roll, dice, cube and score. Player 0 is o, player 1 is x.
+	MatchId *MatchId `json:"matchId,omitempty"`
+
+	// The play in standard notation instead of `play`, such as `8/5* 6/5`, `24/18(2)` or `bar/22`. Hits need not be marked.
+	Notation *string `json:"notation,omitempty"`
+
+	// Player from whose side points are counted in notation. Defaults to the player on roll.
+	Numbering *SvgArgsNumbering `json:"numbering,omitempty"`
+
+	// Player whose point numbers are shown, or `none`. Defaults to the bottom player.
+	Numbers *SvgArgsNumbers `json:"numbers,omitempty"`
+
+	// Player drawn at the bottom. Defaults to the player on roll.
+	Perspective *SvgArgsPerspective `json:"perspective,omitempty"`
+
+	// Show the pip counts.
+	Pips *bool `json:"pips,omitempty"`
+
+	// Play of the player on roll, drawn as arrows.
+	Play *[]CheckerPlay `json:"play,omitempty"`
+
+	// Player on roll. With a Match ID, defaults to its player on roll.
+	Player *SvgArgsPlayer `json:"player,omitempty"`
+
+	// gnubg Position ID, seen from the player on roll
+	PositionId *PositionId `json:"positionId,omitempty"`
+
+	// Colour theme.
+	Theme *SvgArgsTheme `json:"theme,omitempty"`
+
+	// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+	Xgid *Xgid `json:"xgid,omitempty"`
+}
+
+// Side of the bottom player's home board.
+type SvgArgsHome string
+
+// Player from whose side points are counted in notation. Defaults to the player on roll.
+type SvgArgsNumbering string
+
+// Player whose point numbers are shown, or `none`. Defaults to the bottom player.
+type SvgArgsNumbers string
+
+// Player drawn at the bottom. Defaults to the player on roll.
+type SvgArgsPerspective string
+
+// Player on roll. With a Match ID, defaults to its player on roll.
+type SvgArgsPlayer string
+
+// Colour theme.
+type SvgArgsTheme string
+
+// eXtreme Gammon ID of the position, cube and score, with or without its `XGID=` prefix. The bottom player is x.
+type Xgid string
+
+// PostApplymoveJSONBody defines parameters for PostApplymove.
+type PostApplymoveJSONBody ApplyArgs
+
+// PostGetbearoffJSONBody defines parameters for PostGetbearoff.
+type PostGetbearoffJSONBody BearoffArgs
+
+// PostGetbearoffParams defines parameters for PostGetbearoff.
+type PostGetbearoffParams struct {
+	// Player drawn at the bottom of the `text/plain` board, with their home board on the right. Defaults to the player on roll.
+	Perspective *PostGetbearoffParamsPerspective `json:"perspective,omitempty"`
+}
+
+// PostGetbearoffParamsPerspective defines parameters for PostGetbearoff.
+type PostGetbearoffParamsPerspective string
+
+// PostGetbearoffcubeJSONBody defines parameters for PostGetbearoffcube.
+type PostGetbearoffcubeJSONBody BearoffArgs
+
+// PostGetfeaturesJSONBody defines parameters for PostGetfeatures.
+type PostGetfeaturesJSONBody FeaturesArgs
+
+// PostGetmovesJSONBody defines parameters for PostGetmoves.
+type PostGetmovesJSONBody MoveArgs
+
+// PostGetmovesParams defines parameters for PostGetmoves.
+type PostGetmovesParams struct {
+	// Player drawn at the bottom of the `text/plain` board, with their home board on the right. Defaults to the player on roll.
+	Perspective *PostGetmovesParamsPerspective `json:"perspective,omitempty"`
+}
+
+// PostGetmovesParamsPerspective defines parameters for PostGetmoves.
+type PostGetmovesParamsPerspective string
+
+// PostGetraceJSONBody defines parameters for PostGetrace.
+type PostGetraceJSONBody RaceArgs
+
+// PostGetraceParams defines parameters for PostGetrace.
+type PostGetraceParams struct {
+	// Player drawn at the bottom of the `text/plain` board, with their home board on the right. Defaults to the player on roll.
+	Perspective *PostGetraceParamsPerspective `json:"perspective,omitempty"`
+}
+
+// PostGetraceParamsPerspective defines parameters for PostGetrace.
+type PostGetraceParamsPerspective string
+
+// PostGetrouteJSONBody defines parameters for PostGetroute.
+type PostGetrouteJSONBody RouteArgs
+
+// PostGetshotsJSONBody defines parameters for PostGetshots.
+type PostGetshotsJSONBody ShotsArgs
+
+// PostGetshotsParams defines parameters for PostGetshots.
+type PostGetshotsParams struct {
+	// Player drawn at the bottom of the `text/plain` board, with their home board on the right. Defaults to the player on roll.
+	Perspective *PostGetshotsParamsPerspective `json:"perspective,omitempty"`
+}
+
+// PostGetshotsParamsPerspective defines parameters for PostGetshots.
+type PostGetshotsParamsPerspective string
+
+// PostGetstructureJSONBody defines parameters for PostGetstructure.
+type PostGetstructureJSONBody MoveArgs
+
+// PostGetstructureParams defines parameters for PostGetstructure.
+type PostGetstructureParams struct {
+	// Player drawn at the bottom of the `text/plain` board, with their home board on the right. Defaults to the player on roll.
+	Perspective *PostGetstructureParamsPerspective `json:"perspective,omitempty"`
+}
+
+// PostGetstructureParamsPerspective defines parameters for PostGetstructure.
+type PostGetstructureParamsPerspective string
+
+// PostGetsvgJSONBody defines parameters for PostGetsvg.
+type PostGetsvgJSONBody SvgArgs
+
+// PostGrademoveJSONBody defines parameters for PostGrademove.
+type PostGrademoveJSONBody GradeArgs
+
+// PostGrademoveParams defines parameters for PostGrademove.
+type PostGrademoveParams struct {
+	// Player drawn at the bottom of the `text/plain` board, with their home board on the right. Defaults to the player on roll.
+	Perspective *PostGrademoveParamsPerspective `json:"perspective,omitempty"`
+}
+
+// PostGrademoveParamsPerspective defines parameters for PostGrademove.
+type PostGrademoveParamsPerspective string
+
+// PostInfermoveJSONBody defines parameters for PostInfermove.
+type PostInfermoveJSONBody InferArgs
+
+// PostApplymoveJSONRequestBody defines body for PostApplymove for application/json ContentType.
+type PostApplymoveJSONRequestBody PostApplymoveJSONBody
+
+// PostGetbearoffJSONRequestBody defines body for PostGetbearoff for application/json ContentType.
+type PostGetbearoffJSONRequestBody PostGetbearoffJSONBody
+
+// PostGetbearoffcubeJSONRequestBody defines body for PostGetbearoffcube for application/json ContentType.
+type PostGetbearoffcubeJSONRequestBody PostGetbearoffcubeJSONBody
+
+// PostGetfeaturesJSONRequestBody defines body for PostGetfeatures for application/json ContentType.
+type PostGetfeaturesJSONRequestBody PostGetfeaturesJSONBody
+
+// PostGetmovesJSONRequestBody defines body for PostGetmoves for application/json ContentType.
+type PostGetmovesJSONRequestBody PostGetmovesJSONBody
+
+// PostGetraceJSONRequestBody defines body for PostGetrace for application/json ContentType.
+type PostGetraceJSONRequestBody PostGetraceJSONBody
+
+// PostGetrouteJSONRequestBody defines body for PostGetroute for application/json ContentType.
+type PostGetrouteJSONRequestBody PostGetrouteJSONBody
+
+// PostGetshotsJSONRequestBody defines body for PostGetshots for application/json ContentType.
+type PostGetshotsJSONRequestBody PostGetshotsJSONBody
+
+// PostGetstructureJSONRequestBody defines body for PostGetstructure for application/json ContentType.
+type PostGetstructureJSONRequestBody PostGetstructureJSONBody
+
+// PostGetsvgJSONRequestBody defines body for PostGetsvg for application/json ContentType.
+type PostGetsvgJSONRequestBody PostGetsvgJSONBody
+
+// PostGrademoveJSONRequestBody defines body for PostGrademove for application/json ContentType.
+type PostGrademoveJSONRequestBody PostGrademoveJSONBody
+
+// PostInfermoveJSONRequestBody defines body for PostInfermove for application/json ContentType.
+type PostInfermoveJSONRequestBody PostInfermoveJSONBody
+
+// Getter for additional properties for Features. Returns the specified
+// element and whether it was found
+func (a Features) Get(fieldName string) (value float32, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for Features
+func (a *Features) Set(fieldName string, value float32) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]float32)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for Features to handle AdditionalProperties
+func (a *Features) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]float32)
+		for fieldName, fieldBuf := range object {
+			var fieldVal float32
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for Features to handle AdditionalProperties
+func (a Features) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Apply a play to a board
+	// (POST /applymove)
+	PostApplymove(ctx echo.Context) error
+	// Get bearoff distributions
+	// (POST /getbearoff)
+	PostGetbearoff(ctx echo.Context, params PostGetbearoffParams) error
+	// Get exact bearoff cube decision
+	// (POST /getbearoffcube)
+	PostGetbearoffcube(ctx echo.Context) error
+	// Get neural net features
+	// (POST /getfeatures)
+	PostGetfeatures(ctx echo.Context) error
+	// Get moves
+	// (POST /getmoves)
+	PostGetmoves(ctx echo.Context, params PostGetmovesParams) error
+	// Get race analysis
+	// (POST /getrace)
+	PostGetrace(ctx echo.Context, params PostGetraceParams) error
+	// Get evaluation route
+	// (POST /getroute)
+	PostGetroute(ctx echo.Context) error
+	// Get shots
+	// (POST /getshots)
+	PostGetshots(ctx echo.Context, params PostGetshotsParams) error
+	// Get position structure
+	// (POST /getstructure)
+	PostGetstructure(ctx echo.Context, params PostGetstructureParams) error
+	// Get board diagram
+	// (POST /getsvg)
+	PostGetsvg(ctx echo.Context) error
+	// Grade a move
+	// (POST /grademove)
+	PostGrademove(ctx echo.Context, params PostGrademoveParams) error
+	// Infer a move from the boards before and after it
+	// (POST /infermove)
+	PostInfermove(ctx echo.Context) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// PostApplymove converts echo context to params.
+func (w *ServerInterfaceWrapper) PostApplymove(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostApplymove(ctx)
+	return err
+}
+
+// PostGetbearoff converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGetbearoff(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostGetbearoffParams
+	// ------------- Optional query parameter "perspective" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perspective", ctx.QueryParams(), &params.Perspective)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perspective: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGetbearoff(ctx, params)
+	return err
+}
+
+// PostGetbearoffcube converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGetbearoffcube(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGetbearoffcube(ctx)
+	return err
+}
+
+// PostGetfeatures converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGetfeatures(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGetfeatures(ctx)
+	return err
+}
+
+// PostGetmoves converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGetmoves(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostGetmovesParams
+	// ------------- Optional query parameter "perspective" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perspective", ctx.QueryParams(), &params.Perspective)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perspective: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGetmoves(ctx, params)
+	return err
+}
+
+// PostGetrace converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGetrace(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostGetraceParams
+	// ------------- Optional query parameter "perspective" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perspective", ctx.QueryParams(), &params.Perspective)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perspective: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGetrace(ctx, params)
+	return err
+}
+
+// PostGetroute converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGetroute(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGetroute(ctx)
+	return err
+}
+
+// PostGetshots converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGetshots(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostGetshotsParams
+	// ------------- Optional query parameter "perspective" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perspective", ctx.QueryParams(), &params.Perspective)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perspective: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGetshots(ctx, params)
+	return err
+}
+
+// PostGetstructure converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGetstructure(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostGetstructureParams
+	// ------------- Optional query parameter "perspective" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perspective", ctx.QueryParams(), &params.Perspective)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perspective: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGetstructure(ctx, params)
+	return err
+}
+
+// PostGetsvg converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGetsvg(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGetsvg(ctx)
+	return err
+}
+
+// PostGrademove converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGrademove(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostGrademoveParams
+	// ------------- Optional query parameter "perspective" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "perspective", ctx.QueryParams(), &params.Perspective)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter perspective: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostGrademove(ctx, params)
+	return err
+}
+
+// PostInfermove converts echo context to params.
+func (w *ServerInterfaceWrapper) PostInfermove(ctx echo.Context) error {
 	var err error
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.PostGetmoves(ctx)
+	err = w.Handler.PostInfermove(ctx)
 	return err
 }
 
@@ -312,37 +1619,158 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.POST(baseURL+"/applymove", wrapper.PostApplymove)
+	router.POST(baseURL+"/getbearoff", wrapper.PostGetbearoff)
+	router.POST(baseURL+"/getbearoffcube", wrapper.PostGetbearoffcube)
+	router.POST(baseURL+"/getfeatures", wrapper.PostGetfeatures)
 	router.POST(baseURL+"/getmoves", wrapper.PostGetmoves)
+	router.POST(baseURL+"/getrace", wrapper.PostGetrace)
+	router.POST(baseURL+"/getroute", wrapper.PostGetroute)
+	router.POST(baseURL+"/getshots", wrapper.PostGetshots)
+	router.POST(baseURL+"/getstructure", wrapper.PostGetstructure)
+	router.POST(baseURL+"/getsvg", wrapper.PostGetsvg)
+	router.POST(baseURL+"/grademove", wrapper.PostGrademove)
+	router.POST(baseURL+"/infermove", wrapper.PostInfermove)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8RX227bSBL9lULvBtgFGF3tXPSysLMLr7GbGQMTTB4MAymSRanjZjfd3ZTMCfTvg2pS",
-	"tCiStvM0L4LUl+qqU6dOlX6IxOSF0aS9E6sfwiUbyjF8vTRoU/5SWFOQ9ZLCsuGPv1vKxEr8bfp0e9pc",
-	"nX7aUHJP9v9YmdKLfSQef/LGPhKWHkppKRWrW2EEm7iLhK8KEith4u+UBMPde6sfIiWXWFl4abRYiV/K",
-	"PCYLJoOkPuhAaiBMNlAYqT0YDX5DEHOgExEJesS8UMSW5kuxOo/E4kysFpF4F358EKvlPjqBY84fjWdS",
-	"e1qTZdfms5H1sfOLkfXlyPrZyPr5yPq7kfX3I+sfRtY/Dq+PuL8YgWExAsNizM4IDIsRGEaOj5wewWwE",
-	"shHERgAbwStGO7SxH2f5jcKqz/HPZkuQGGNTqdGTE6f8zKzJ+9duAv93G7IUSqApEJAOktJa0l5VXBC6",
-	"zLkE54IzzLAyhgwYo8NQcNwcZGB8oHfgciBuYGmgZOBfIFtgVqBRIEdgQkh7yHFIaA3OU707b6VeMxLe",
-	"vD6SnVQKcrOlvy4Mk2UDYZyom2d5C0kaUrj/bFFd62wgbt4pkX9ASh6l6qc+KWPKStW/+xU5zTFBSol0",
-	"bCIx2smULKX/OtbBDJWj1q3YGEWo2a9CNW90Df/X7CBHXYEvrXaAG8IUdvxaa38CX6pCJqhUBcsI4tJD",
-	"jhV4snlgMBBaJZmKGRTWxBhL5StAZQnTCpQx9w4Ssh6l7mj2MhqqpmOoD3gc3B8DvIa1H91vibHE7YR5",
-	"1lCri3gqs2wgVQ+l9BXwJlnSCRdsXqClFLypOxA535L1ENCsdU+HRsbu0cOoeXa84x1YUujllviVP8ga",
-	"+Iem0ltU/+w8M5mffxx4Sja0e65xt/Ss22JIlvTVS9dujo6eJokeRFTjOJQelrs+ApeY3K8xz40eTgt1",
-	"kvpSQM3JwPJacqWn3L1yiAky/STjaC1Ww7rOsVzYteuPWPFh8nruvXo820fdMs+wVL4t3C5M1w5SU8ZK",
-	"6nVd/lJD6Ygrvl/hqUwGkF68dcp4CFEx1/hUzTwHMRHPU9bs9DG/bpfR/C56wjDHR5mzHr+LRC51/X0e",
-	"DfTIHB+v61uLcPTpRxfccPItZ35Akj7jI+h2EAyHuB4ssURNWGW08eDKgjUhhZxQu2YXUCnALUqFsapr",
-	"yp1KThvBbCgCJhDZgaYV1nn+tEapow71yG2Dmd++EZZ6ndCxFB2H3OTd27KX9q8b8ht+zYZQvYEEVVKq",
-	"ILasHZIcZMbWk3E33gl8wXtyoIxek50MMOWkfGvqNvRpARgq5ZuuXpwA9KT8JoOd1FNlHAFmnizkeM8U",
-	"9hvpGid/rxmIlqAgm5D2uCZuH4WxbNGxlXkEckITmDdJns9mbw6dxFcRzCbnzc757A2QTyY9IWEnXnJW",
-	"GVd7R7DGnCZwoXZYOfg2h7ccybeu+J7PlgPiyw9dXr3yKYS4FcCu8dns/Yjx19sesjs/GzC7k/oVidTH",
-	"4HStnn18P2z2OSRk1/JzWHwYtv4zxgfBWPbdPqkKhqZ56xBQnQZxyEab8n6p7I+acdfNT20dtwNEXckI",
-	"a7klDUeNsTBOhmuR8NIHZTna/UoxXNxci0hsybra+nwym8wYI1OQxkKKlViGpUgU6DehIKZr8q0KFcYN",
-	"/Au/oiHPgk6ACv/cAXVad5JGDbnkQhO+TsOE7/zV4ZkaV3L+0qRBNxKjPenwLrKGJ+Hi9Lurm33dLV/q",
-	"pW0r3u/r1LnCaFdHtZjNfuqdVw0LYY7pTwn7U+3+9X+BS67Mc7TVMZqcR+Th4VZcYU4XGlXlpBN3wYoj",
-	"y4kUq9vTdPy7bhVQnxCRKK0SKzHFQk63c7G/2/8ZAAD//4vUmD8TEgAA",
+	"H4sIAAAAAAAC/+x9jW4bt9bgqxCz30Xa745lSbZjx8WicJI2DdreBk1x290gC1EzlMR6RKokZVm38Evt",
+	"I+yTLc7hz3BGHEm2497cxC3gSCMOfw7POTz//DMr5HwhBRNGZ+d/ZjNGS6bw44/UFLPXJXwsmS4UXxgu",
+	"RXaeTcVyPCX4M3n9ksgJMTNGFlJzaOC/K/bHkmmT5ZkuZmxOoR+zXrDsPNNGcTHNbm7y7I17q3sc32L3",
+	"UDnRjAkyUXJum1V0zRSRgihZVTsm8tur1y83p8B+M4rNGXlF5/N9prB1kBv/I4L3YrGoOCt/lFdsc9xf",
+	"4iHoxDBFKK4nJytuZjikXNh9ixa4UHLBlOEMRxhLqhCs/6XYJDvP/sdhvdeHbiaHz7HRTZ6NpRLsp8lk",
+	"czIvZrA4pQk2IXIyyfKMXdP5omLZeT/3C+XCsClT0NmMG73Z0RvJhdFkNWOKEUoK2y/hmsy4yUkhl8Kw",
+	"st7AubxiKh7r3cn7POOGzXUE32hY94QqRdfwfdHArm1giPDwJs+up3znG79BG9hT2HquWJmdv3Mgbwzs",
+	"enMwieD8PsxXjn9nhYGBASnWF2qqd6AE12TKr5ggVJOJVGR0OGUGwKVHOaGiDPhPxmsygg8jAs2ENBQ6",
+	"GPXuiS0lLxJoOzzQlTQE4Q+UAa3IFa2WTJMx0KaZKbkSPfIrIDENPCQnJZvQZWU0MZJwo/FN1ww+5vWC",
+	"uAbEKS5ZCW3HjFRsSqteA02O8kGMKHN6zefLeXb+NM/mXNjPgxTezun1a/vWEJvWX9qoNeFj/XwfkH0b",
+	"GmL/gadue8ez3ps88zvWgRAIEUG0oaKkqiS+OeFCG0ZL2AW7/znRy2IGCDM6Ozz5b/L08GSUk9Hw+HBw",
+	"9sXwS4sfY6oOh8NRj3wHmyAYwx4BynOqLlnZAHN2dngC3WR5m9XlmVjOxwy/bHIBy5aRylczqRnRvATc",
+	"RuZAFQuMgIuwnh55GWHIJnfHiQnY13fZdZZnMnufmBW8kwZk4XkcEFGZw9AwiFQlU/BpjRPDH3vkwjcn",
+	"c6Aw99xy5rlUjJgZFUQKRkrOCAUOXbAa/sPjw2Hftj46GDQx988M4AKwBajK7Dw7yW7y8PRp9DTG8G3I",
+	"9AKpRQHYkzwS4di5TR6+u0l2jw2pUec6hTR/Cbve4LnPGVVyMrkv14X9lEtjOdc9uetfxVwe997u/Yvl",
+	"mL0WE7kJiR+lYGsypXNGiuWYkZIVXHMpdBAEGyvPyZhNpGL4BVbWxoNSLscV20myyzG7KHAKN3kG8oV/",
+	"f9dbk2X1jW9+g0AvzOaqfmXIpBjxXRMFZ0UQvMxKHgBTLsnYAoiU1NAx1exr8noCTDm3THEFHTE44Skw",
+	"7PEaXxdsqWhFBDO6wd2MWrKwCWMpK0ZxgdyBftvivrmiFW4RSly3B2NLULOAiWCb+62Jun/fjTEvOaDu",
+	"eOmP5uYuT1FdSNCVkmM65hU3KB4BcLmYgkSNcJtwpY0/W3Ki6RX8Cr/YDvFYwplXa2IRTudwvnBRsmsi",
+	"GifJICEq21M5dQrYAX6GHjen/c31ghWwv/Z9mDqObQUwqtILSCsJ9RSiId8mtL+3XqQp2RWnDV3r/mPL",
+	"yeRWu0OrqhYRbrEJ/bzfOz3J+73hya22Q91jI+K5xpMZ9IYnCVCoD7EBXWP2e8dHRxuDtmjRKpR2yfV8",
+	"PIJkTeRs480WGk1z9Oeeo0UUjNx8LM3MsXNdK1HFjIqCdbB7LyYqCvLdSoKITEAI8Jx0DqKCtWN4LqmJ",
+	"FKzJXfXGMVFJzV7txNDEhCqpAWcpCcDbTgc7RZIEu7vJsxUXd5ndigthT8UIQ05TWLni4tU9RtgbANd3",
+	"AkALga2MY6Hipp67LUyiZyUTZ/IFqaQIikjTwgNqxoybTWmCK5Y6318jknkQ+W6e6JqHgbTKBXlKFnyh",
+	"v97nkEb9DIYKLU9SGrSeyZTpB8kVSUNOyNFT0JAMLIngqmoeleizBWw7Dz9Q7mHQBei3fj4fAeQS1jA/",
+	"0riSKQuYxe4nGlXkeKwk7Bc1eTQ2qt87Hpzm2USqOTXZeTapJDVZ11nQePXdu6f58fv83Qn+PXZ/j/Dv",
+	"EP8O3ufW3vJuiE8G+eD9vqopYEXq+PvLkSiGXH0UbUMtr5010UruqYz/QNdyafZiQK032mdnBl2kZth8",
+	"bwOW/wgShLWmObmGFjNriQGOiogJC21I8X9mgyNEwOExWsae4pez7Pzops2fBmkz7aDf8byr/bDj+VHH",
+	"8+OO5ycdz592PD/teH7W8fxZ+nnH9IcdYBh2gGHY1U8HGIYdYOho3tG6A2YdIOuAWAfAOuA1pir1w003",
+	"lr9JGvbAt0IKKVXJBUheG+entaqlGbP1UlgREMdAy/NSKSZMtY4sHIMMdhjACjAEgOVopzvNcrTiPcsQ",
+	"4xG9EZcRcRFLESUR/xDZELMQjRA5EBNw23GPcUMtcFK2TSP3X8mKVxUaLf99y5CTSWIZLe5mZJbbTUpy",
+	"uFq93/RZxcYa5zQLxg4BB2HFtXUhIGCguTPdoPK/3XazITP4d4ji05n5OkuJAfb1N1Q3T9hB4hy2TX+h",
+	"l6x1jj87eZpoLuTLMLWo8dOTlGRt6GV6DYZebl1Ba2/CoI3pNpYZ2VRw1K5NjI1Wmzqv+6XL4JbezI3N",
+	"K5gwOPNk/+varQpvE9863w1PaF8xrffseSnoFeUVtWCJex+eJnqXi8VPK7HPvGt38Ep4s5GDRCwInp6l",
+	"Rtk6xNYOTwc79fsAIT9QntXwDQtMIcc3kwkrDL9ib/jiBcjGmxIXWxQJXeqKKTpNWor4nGlcCnVtQIwH",
+	"3KIOn46fHT6Nl3h22js53iYr7zl0Q0IFuD17IGNM0+Zz0k+Ns6La0GmCDwSIA1ysPkLAC29Jzz+Kh0iC",
+	"p21tXRRZPeimrWevvdd7znbipFjLJ6yHMWhvVDFCa8PNhikmGLpzsprxYkYKWL0mywWAeHDSsAJCDzM5",
+	"j4TkW2oDm/i9j0aQeCslGgWL+SbYrCkKkKhkhvJqUzIqLFtOuA6obnpCSCEFgFCxsqENT2il0+pwleTz",
+	"38kVmVOxJmaphCYUIpHIiuqo/x75Zb3gBa2qNTnKyXhpLSOGqTkKeIRRVXGmCJ8Qr9GZNaEVeDfWpJLy",
+	"UpOCKUO5aKg0RyltWsmlYbs1WGiU4ngAPb/Y9x3bs+xw7b8tpApGCCente0Xk0knw4YfmWKiYASmTFV9",
+	"No6ZNr7HrYYx9kdn9zDxxuyIYhVFMjSS/IspSb4QbGkUrb5sHhaDkxQzuq37p2Xm2OorjJpuMKU/UMXv",
+	"iMT5llGzVBbYtCzR40irN031YadBpSVkicXSaGIfjtG3ICIbj2ctOaHa+dEMSGX2wJr3CAYqSGFoYdA+",
+	"XSiqZ+ANYEY/QdY2kpPJYATbAJ+ORuimNnF8Q4jhyslorBi9fGE7hFiQMS0uXbCX/3ohipnEbxOpVlSV",
+	"9YMFX/wgtcaPgxFOaLQY1kPag2nGjfErhSZmJaOF4hjf6IIu0Ic+ojgbLuLPQ/hS1M+L6PFc2s21n+dM",
+	"GHzMhGEqfMBHhs+5mPpljaVg/vOr8GGA61QMGKtbEDz/mbkJ2h1QtMCteWJPk9GciZ8C1N2X41Huz5cA",
+	"DxFbXFqbQcYMgDSwfodRoaTWePCMWqaXeMtQd1gMkH7dZuBnu1Sgt5PhzRbUfox4+AwjHr6NAd2Ewbev",
+	"n78lI9y38xGxE23Hu+b28Acs1XBI9Qi89UST3wBXrvGHn+CjbCCuxYbz/yWX51rOmQXe+dF5H/8/GLoP",
+	"/fOT8379+OT8xH88st99M3hhcP703L44wP/h3wP4Z9hs1z/vp8D/StGS/SeEWjZEMUTCIFx12yFwl7gg",
+	"S806rBF/RQDnY1DmY1DmpxSUiZZLI+FT/zEAsz6S/lhSYfi/kjYkF/ZQG6q4MGckvNEIFtvAJ80UGB80",
+	"QyGyl+Rk9zsPv+PmhQ3xSONg5AJ2lhbvdKS15A7eW90j1j8JOLlS3Bgm6kXP+HTGFKIeBip9ZY0U0KXt",
+	"kWvxBPq0FlPQ2xhaHYbOZBW8nonjpHLe0r2wsXaKJ3DRjv4dN2+6XMn7uJFDLz9v+pPv6xoOfd/GPwxK",
+	"B0KJSIXMYGcCCyoOu0eQwpmJ6myWppka+9G1Q39MFaFTCgdBO8KgtiQ1TBPDzul17lHv7OzZPvs0ux0U",
+	"qSEVo9ogEwVw7nK6fzIRCS3Lgcvk6QgaqBG0g542CCR3JOzRLrG/KRPFazFhag8J1vm1AN3AtrZNot1k",
+	"Lph8doskso8zicirqTlhV0ytXfCeJkbxlvz0SYmpH6289zmp4DHnsNTUScyqKx/0wma54ZKJAtHBe+SQ",
+	"4lyKqKfwhML5AXM7PdXuw0YfKg/0dmmgd1TX4hGzwdHhaXYv7YV6xYOiSPcVEXCOrmZMBHMpKShIgWNH",
+	"WGX2ITSMFgri9rl5R6DZLz/1+4pxMaeiwxlLQzTGVuSgRZTdcrkJPT/KE02+z8kXL8nfyfGX/+//kkPy",
+	"xVtyQI6/zB3uvEQePmOkclr1Jk9CE81b774Mgd490g+g92qk1xGPbcsxm3HRPBwGvcHZIB2tnCItUCt2",
+	"xCnX0ePjNYnWDZLRsqItX/vps12e1ssQhuz2IrWLe2b252Qmq9LPsh13YY/TtjXQMfE+bIzM/UsD+Hrd",
+	"AGVWXFxe+P8AH6kxTMEk/s+7i4P/TQ/+1T949vfD938Ohjf/lSK8NKN8TotLG/md9psVcj5nwlCVINpf",
+	"Z+vapcW19ZVJcCFTQ+boh+EGovy1Q5SQdpJbXKIa4v8nUrU5xzOwyECX/V7/7MR1MWYFXWoGfZaSofqH",
+	"UrYoScWMJj8R63fopVbPGt7DXZ4z1/LurDAqO4CCog2Y8Iz7d8kFcDjgIM4mhcvgJRMG/LXuJcfY2+aq",
+	"/+6yV3ne+kGsLP8Wazsg6W1szGBCxhPdWv7qSY/yLt6G4rE3PTPl/FbwFPuAX5zkOPIxDbTSkmjAsRCW",
+	"FJHwhSAjWLWdQpBVRzhFjdETlqfNvRUN3w5HVyHnY8CHGmWkmVlvX2KKFOTOe5vIW1S9zUp+UZaEEs2E",
+	"QQe5kdYSg0TPrhcV5ciYV7N1Td+eG+QtdlAvztJ0XsssPtgwd4koIZEIIW3UsjDLpo9f98g/GCt1/cDu",
+	"SJk2ez26BfbWt9y27ocbVhZbmrA3NavdKP5ixe4af9wiozcgtiG9f3dTAisqpssQueXWkjGRtZfyg2vo",
+	"5xyRSK0n4XuTdDzx7dXNOb0+QLxNRGLT68j/jY0AWIqButIjHHOKiV7amjhkzqjQ7ldkOCFm0lFKK3gn",
+	"IEpSYXlUgz92s/+3VBvMeK4g+Lhak4ppTWhRLBU17G5uAeSdMT46YrFZWm3JjwEXJ1IhHhpJCloVy4qa",
+	"mpGjqQxJvYmMPfILngWVFFOmHsJFAUIEeqt32AXsadEwL8fhXq0Dlmmzk7bhReCfSknVGQ5mJZflIj1w",
+	"TvohAgbHbGg0/bO9rMBzJ+vvM9kODlTnO1mI2Waxbvc0GQRIRUJB/ZmKy1ixI3QunZoU9Z6TQefSB8c7",
+	"c9TctuEM/LL8XuR2/953YAsak0C8ScudNc7oOxiTOgB8YSERqKR2beEQTNcj5N4dZj1qKGKBAxe+PtGp",
+	"iWk6D7NTNqzKIpdFPhBCe/saSxqWtl3WErvYLjh3JJd+DHpLSKHc9krk/LyPVdOO1QkkL+ymotpbcvBG",
+	"zbsuJX5BFddSpMOIgxJvA2Ej95uV5qVwBcWQSwULnBVLAL8sJ62DAypmS3GgjQhXi6oVxOJxTDZHgeEr",
+	"wuYLsw40bwdrB9u+y6A3pskw6g4IGnqzRHJ2gP1lCStnfWhvCLl3tAikTbTBLhxg6cyDibyliF3ujOOt",
+	"keED6/fXtxi5nQxsTaFugT6ZP0KyFGb/g5k4RDgZ6OuQuhZ1gmbgkMbH1I3XRNB5AtErmkosegGPNyPz",
+	"rHLPtdXvfQBxNCQ3kXCoKNqBXQwxfHJRpQ150T9MSI079zrAZ5/tqRu3dsfCIN+QpOutaCSIJDIwwmqe",
+	"JdWD60abs/7Og3nLTG5TzXRnudJ6F46/e7O6+OXltDi8eP7jdmvpcdpa+kbxeYIB/wBCqzZELVG5ndOg",
+	"/NTxy4JRBW0wPsEzS8UsowR3Kq2+In2Uf0NIMORx1J115t1uL2dQMTE1s0a741Q709zls50b6Dp2qaXY",
+	"QXI3m8EL6VIkthLJiovDSmrmzq25PRfMjGunI/zTGksAZgumCtDEIfdMyYVUoQLNICe8x3pk4BTgQb//",
+	"N5+vYtY56fdO3C8n/b8RZopesnDMrsm6GjGurhRY/KoVSFyjATmAlYyaMvpJ/yghksNAz1/tORQl42CX",
+	"bykA/dOOzvfvO9Xv4HhfN83GRooYOK3syWen6W6f7y5WU1em6YbF2V0r4Wwte9MbHJ3u8hs1StfYBdlt",
+	"CJVswpanSCVy6aVTsa03ilCiaAETdf4topg1TJV673TrtzO5rMqUOdy+YU0O1Ofwos0zbexU7PZj+Hcs",
+	"J2ylxqaHSWdc10NwVSfvQts9kq83a9VtSbHGzXnM9fj8cj1g44OnvkWU8NjnPTdIske+Z9zMILupLDUZ",
+	"HJ6GvHqfX5tyB/8yk2rxBM6svznSAAvdUX8TWe4SJEDL35fasLJjIV2TCq9FG/Rs2Ds5HXTVQoua7SEr",
+	"DvaUFaMFbHXMw6LTKbs/2z0qom3zOGWz0tzuWREDhAQDj2BvmatmF1ht5PUrGm6Njdp2oH/7+qebG+lS",
+	"72+VqozKwCUg2D4IEHKiL11kxK6XmvEpN5gXt1OzbMwNAJYuj4FCr5AhA7NiE7NX/S8DpHGL5bZQyClq",
+	"uBIPO99pBJkc9yOJVbJKJHK/5AWL6x5EUepouGjYLI4f3kn3s0+3TttzmJhywUiBhsA1oSLysm3qzWBw",
+	"LNPJ6y2Xnk8Od7kl+ObXxFUliRpawhKg79hGzcigzmz3rSp8tLDF0jQtYFz0yMhVJRj6bFT79cDoEU6H",
+	"ix3lejHF1j4btPqQerRZBUEKpuPzzQXezdYLpqxkOWh+HTa/HqFl2s65/nhgdP1lED2XOsvvb4hwm5Ry",
+	"UTxvwYNMeMXQzVPbZKY8lNnVhhpebPH4YgDACNghnm+jXpaYI/SaDme5a+GDOiaShsITbSczGdSWzIBE",
+	"0I0U1Tqq0WzXCDUUQp75FvxBNMNqt7sLJmgquFlfdB7VL3npjPnQzhajIoVUihVmX/B7i2wrqAL9DDYC",
+	"ZUYXCyb2KUXRYWiq0clv2cbScs9h0ux2aT4qefthIkUepfjPIT0OPVz3Q+avcKqAyD4zEyQnIpfGSq7N",
+	"LLo46CJ12ZErrP/X3F/TKMHq7qGRBDyyTm93cVufdzb0XeN+vmrEErZigG4V4bODR9jIngR6gUBkf6x9",
+	"f7C7PngxuoXoU7QPIHF7fXMvJ3+DXqNQh5pQrXcUIwCsnzMnmk6YNj7vFcWRAN19nfa1t31rBvVt4X5r",
+	"L3nCdYgScrcLfF/3d7IUUI+82QxFS1eHThhasFRPd/4NOmi4aPDXRh5qTgbPAMcx7b1mXcPj26XfuHKu",
+	"HQ5mX2eYqp2ZR7dPdE5OZ8krf8dhS1uwHAICdBcI8NYcXVggwv8Y68ENch87CK5XwVZx6CDAN15T0nnl",
+	"agvdItXYXjdI1VzW912QKQM+RDezir03D3soMNuAu5Y19+UqeXvDMDlj22N3VheQ9jCC3WbG9bun+Vk+",
+	"OMpvi0gL78TcyiCx0f5FxBtwDWIGpjJsJDz3yAtHg+4CEFShUNbB0UbeQIf1uhrREsmzHBjRYpGSC3+s",
+	"PbEWokK2kPIeJNm+tNAlH4di6HaD88BAPOQtKdc4G9FStJatTDBt3WwwQhpFAYmyrQJq52KF/fExcXsd",
+	"XT/6vBUvjeYgcqDQmpNxOKBudSDFwSxtZL1dKMw9wle6wxHeXk0fVnQHJbEuglTyrnS3HPlhqegqineo",
+	"5Sm4e5VIhaWkumppfoSZ5f8Z8jwc581sAazwvJEw8JaXYffG0hg5r+WLZpFTL2QBLmS56+7DpBA8Flb6",
+	"gBkGdlZ6h1qEM3FKmp2QnsmVs3QKqJO4OZsGfmxOBvZRsOScFkzphXUJdc7L8glqoqE+DEC8M2hrLsDb",
+	"mfM4hJLCOm1YSWd+wxq6mKBbGEBZyZV+vPnz3vovyFkt7oZmXF5s8LcXspJLDMyds3iKdftxtcQC9lTZ",
+	"gHuRxqL76dy/ubdvec94O9PamcakCpZi2IgRnKT/c0QWik34tT2jG7SayMPGVw7GBwcHB98cvDg4OGDf",
+	"HBwcFAcMnjw/cAUWjwb4AcowDhLlFG+iMr4tsIdEmpASYsUM6qSOKGPbLxb65wZnF/36KxuTizevszyD",
+	"wqi290Gv3+vbOvlM0AXPzrMjfIQhkjOkkEMKl2r7FJKF1KbjppBGndyOYgI6PCWGYbUvUTpz1kYGBTdb",
+	"bmuPb5hzQ/rs71CKI30B9nypTbj3OhLaFC0ZrHL0lceJ3NphQl08WCSaX2bUVYwHkGs+rpAi5IIp6qkQ",
+	"YlnNRYCcFTmZNs9lubYx+MIwG1kC8OUFvnn4uwvKr2/B30Ym9XXnNzdWrNULKbSV+Ib9/gcdyF+2jyM1",
+	"t/+n77M8A7+bOy6R1R10MyPXNJZcPF/a460mCwP62/UGtrEg0sv5HPOc7V3xrgod7CYNxcMMBaH/XfaK",
+	"ztmFoNVac529h7dBtHc+vm5ieMVMV3H87Xcm1pfIYrDzE729vr4njdYVi1hfGctyQJJ1IBPWdfelK/jf",
+	"I8+jyTypyWq+dLaOcbB23aPIP7nQl5biDLs2h5hYjKWfp6zlwA+nvQfGxdsXr197y9pEVpVc1XfmNkCb",
+	"psZX9d4Be1N0zgwi7Lv9BSkP5Mbs3ZT8hnAVLdjbvlDK30cM4zABgB6Wl6FwPDekvjwi2V0i2837h+E7",
+	"8ZXfD8x54rtIYaAa7s0+EgfqJ8CjgJUkmce+XAqknt2cCq1xUawaKXz8TnR5EPUzSTGq7hCIyL6BkbZ5",
+	"HQKMP9DL+i6MLrEhcLMe+WlpYJwWG8KyEkxgeJmbej0j3wyEt0rSEjxXjcu76Y5LuIN6jAFACKwRdIZB",
+	"ALt4jbvy59Mgw3DR+6dEYBb7Pd42LmfZSWaTKIdtO41xm9PWRK2mvRYcVxjXBrNfGuZcJdzU1pjnTFVc",
+	"WHc/JDz6aMoc1e1KavAXgh06t79wMWfC5MRf9JATe7uBFZTdTQ71LRcdl2PkxDeoxfuJa+VvWgid+Psd",
+	"QlpendPsDB7klxoeTBQy1MiKHUbRVZpAn7Xh9E2wZDvpIhG0JxWZcMFhAWg7hcCmBoVTmGEn6YZdfRi6",
+	"bVwg8cCEG+dZfkpEG9FQtFvbiTV4NropdVO/tlJchdfA1gWjrGXngWRZOwkXSBG9NANiqLiOHGRJ9A1+",
+	"nUcZ9+6EE0qPfQAC3dsjlvQ1fn4ir0fg7eTsUw22n7u1Gbp52FoZEHN1XPYNUrfPgGhl9ei4gB+IyXH+",
+	"Bx5VdSZeLfGyxP168Ju7yS/ctYcS7V1v2nswNlQb75NcxudVPDKZuzOZkFr4wFJASIz6PPkJiqTUM5Cd",
+	"fMVn0mxnLEUiBUXH0Rc81JQAdaL0FXxa5NvKpsBIfC+q6toGvnIltjYSAHxmXONNH/wRvxX9hDUuNvN2",
+	"XDxxFNhQy8yB/bVUc1cc34UV1/E8NgAiWsm2wn/dPAa34oFoL+QZPDTxuTsuPyGNuUYlv0PbSSrEkqVJ",
+	"yiahupiw6PLDLbFlZiPy3XlrAO2juOg83JoYriwKETRxHS9/FFvfkqv/4qIM3MmzouvcXkiCgkF8JYkt",
+	"VQQjQW/wM1r2UVWAgxysXMAQjCS0wHMVqkZoiTpGcy2RNT89Fp8ABBq+LR9Rn44tdsc57oE1pWFkcTOw",
+	"+MGkCDssFf5cr71xoRRlkvRDNN2jfHF31lOnnzwwj6tD4T9PCcNj6w42GMeyp1nhS/w2tkxhHAKqI0oy",
+	"jq/5yGQXZZoTDDLVea1C1KHhOfGRpr5+MsaaBgbX9EQ2eAsWwyrjWXWd4nlksohlAHiq3aQjp6WbJL5u",
+	"w4VFSSB4iJvNwnMh+OHh+FQcQ4vQ0Ciz+VmFZfo7oSOVEGbXzcjCpj8ys4/GIrNXqPBnzM8CEcXYu4O5",
+	"XU23sDVFV43IdCAs8vafrwif06mPZrBxfc78amvyOr+HFM1a700Gg/FDW0SJq+kD6RA+QnwvjMSVHuqr",
+	"6d+v59Vn471GdlRyOlV0HuHQS/sk4I+PvtqiJKC6i1quQ44oGYFwL/OipMknNlEnt9F+8KWjujLVpGQM",
+	"I4F08zfCwPzfqG5KJrwy9rYCqD01o1AjVUk8SkEkvxCEV7VWAZNSbLLUsf6slhXOx9YrrT17FE774tIf",
+	"ysljHMOrUStgpRXb0dWFAEAH2VLYi48e7JSsodO4uvnWToqw3Y9n4r0YUH0L9wMfinUR9c/wQIR118WU",
+	"txyDXEyY2s7GvuWOcCI+gUwNz7AoCNZZ1ij+YG0HqQhZIHInt1v3JLvioaQHRqtq1ozNjXlOQedMUVts",
+	"jJLFbK3xviEXq9e8+hGsKsOBM82gVUNxd1MRCsdwRuOo1qLSmC/311Gw0loZ42VQUuJVX9xVLXcueuHq",
+	"QUN3aTbyOoD7YairviH0L6Cuuuj8p0I3uCJHN5EqBzuvA3qLGpm30Bb0i8U6UoeEY+WunEeWZ0tVZefZ",
+	"IV3ww6tBdvP+5v8PAMLcVDmnrQAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
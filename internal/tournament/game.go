@@ -0,0 +1,241 @@
+package tournament
+
+import (
+	"bgweb-api/internal/gnubg"
+	"math/rand"
+)
+
+// starting position, seen from the player on roll
+var startBoard = gnubg.TanBoard{
+	{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+	{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+}
+
+// a game or match between two seats; everything but the result is per
+// seat
+type game struct {
+	result      GameResult
+	wins        [2]int
+	gammons     [2]int
+	backgammons [2]int
+	errs        [2]ErrorRate
+}
+
+// Play a money game, or a match, and return it with the points counted
+// for seat 0.
+func playMatch(seats [2]*Player, cfg Config, rnd *rand.Rand) (game, error) {
+	var g game
+
+	if cfg.MatchTo == 0 {
+		winner, points, err := playGame(&g, seats, gnubg.Cube{Value: 1, Owner: -1, Jacoby: cfg.Jacoby}, cfg.Reference, rnd)
+		if err != nil {
+			return g, err
+		}
+
+		g.result.Games = 1
+		g.result.Points = points
+		if winner == 1 {
+			g.result.Points = -points
+		}
+
+		return g, nil
+	}
+
+	var score [2]int
+	var fCrawfordDone bool
+
+	for score[0] < cfg.MatchTo && score[1] < cfg.MatchTo {
+		/* the game after a player first gets to one away is played
+		 * without the cube */
+		fCrawford := !fCrawfordDone && (score[0] == cfg.MatchTo-1 || score[1] == cfg.MatchTo-1)
+		if fCrawford {
+			fCrawfordDone = true
+		}
+
+		cube := gnubg.Cube{Value: 1, Owner: -1, MatchTo: cfg.MatchTo, Score: score, Crawford: fCrawford}
+
+		winner, points, err := playGame(&g, seats, cube, cfg.Reference, rnd)
+		if err != nil {
+			return g, err
+		}
+
+		score[winner] += points
+		g.result.Games++
+	}
+
+	g.result.Points = 1
+	if score[1] >= cfg.MatchTo {
+		g.result.Points = -1
+	}
+
+	return g, nil
+}
+
+func roll(rnd *rand.Rand) [2]int {
+	return [2]int{rnd.Intn(6) + 1, rnd.Intn(6) + 1}
+}
+
+// Play a single game and return the seat that won it and the points won.
+func playGame(g *game, seats [2]*Player, cube gnubg.Cube, ref *gnubg.Settings, rnd *rand.Rand) (int, int, error) {
+	var board = startBoard
+
+	/* opening roll: each seat rolls one die, the higher plays both */
+	var dice = roll(rnd)
+	for dice[0] == dice[1] {
+		dice = roll(rnd)
+	}
+	var mover = 0
+	if dice[1] > dice[0] {
+		mover = 1
+	}
+
+	for turn := 0; ; turn++ {
+		g.result.Turns++
+
+		if turn > 0 {
+			if cube.CanDouble(mover) {
+				doubled, taken, err := cubeAction(g, seats, board, mover, cube, ref)
+				if err != nil {
+					return 0, 0, err
+				}
+
+				if doubled && !taken {
+					g.wins[mover]++
+					return mover, cube.Value, nil
+				}
+				if doubled {
+					cube.Value *= 2
+					cube.Owner = 1 - mover
+				}
+			}
+
+			dice = roll(rnd)
+		}
+
+		after, err := gnubg.BestPlay(board, dice, mover, cube, seats[mover].Settings)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if ref != nil {
+			loss, cPlays, err := gnubg.PlayLoss(board, dice, mover, cube, after, *ref)
+			if err != nil {
+				return 0, 0, err
+			}
+			if cPlays > 1 {
+				g.errs[mover].ChequerDecisions++
+				g.errs[mover].ChequerLoss += float64(loss)
+			}
+		}
+
+		if chequers(after[1]) == 0 {
+			return mover, g.score(mover, after, cube), nil
+		}
+
+		board = gnubg.SwapSides(after)
+		mover = 1 - mover
+	}
+}
+
+// Decide on doubling and taking, each seat with its own settings, and
+// charge both with the equity they gave up according to the reference.
+func cubeAction(g *game, seats [2]*Player, board gnubg.TanBoard, mover int, cube gnubg.Cube, ref *gnubg.Settings) (bool, bool, error) {
+	d, err := gnubg.EvaluateCube(board, mover, cube, seats[mover].Settings)
+	if err != nil {
+		return false, false, err
+	}
+
+	var doubled = d.Double()
+	var taken bool
+
+	if doubled {
+		dOpp, err := gnubg.EvaluateCube(board, mover, cube, seats[1-mover].Settings)
+		if err != nil {
+			return false, false, err
+		}
+		taken = dOpp.Take()
+	}
+
+	if ref != nil {
+		r, err := gnubg.EvaluateCube(board, mover, cube, *ref)
+		if err != nil {
+			return false, false, err
+		}
+
+		/* the doubler is judged against the best response */
+		var rDouble = min32(r.DoubleTake, r.DoublePass)
+		var rBest = r.NoDouble
+		if r.Double() {
+			rBest = rDouble
+		}
+
+		if doubled || r.Double() {
+			var rActual = r.NoDouble
+			if doubled {
+				rActual = rDouble
+			}
+			g.errs[mover].CubeDecisions++
+			g.errs[mover].CubeLoss += float64(rBest - rActual)
+		}
+
+		if doubled {
+			/* the taker's equities are the doubler's turned around */
+			var rActual = -r.DoublePass
+			if taken {
+				rActual = -r.DoubleTake
+			}
+			g.errs[1-mover].CubeDecisions++
+			g.errs[1-mover].CubeLoss += float64(-min32(r.DoubleTake, r.DoublePass) - rActual)
+		}
+	}
+
+	return doubled, taken, nil
+}
+
+// Count a game the mover won by bearing off their last chequer. Gammons
+// and backgammons are only counted when they score.
+func (g *game) score(mover int, after gnubg.TanBoard, cube gnubg.Cube) int {
+	g.wins[mover]++
+
+	var n = 1
+	if chequers(after[0]) == 15 {
+		n = 2
+
+		/* chequers left on the bar or in the winner's home board */
+		for i := 18; i < 25; i++ {
+			if after[0][i] > 0 {
+				n = 3
+				break
+			}
+		}
+	}
+
+	if cube.MatchTo == 0 && cube.Jacoby && cube.Owner == -1 {
+		/* gammons only count once the cube has been turned */
+		n = 1
+	}
+
+	if n >= 2 {
+		g.gammons[mover]++
+	}
+	if n == 3 {
+		g.backgammons[mover]++
+	}
+
+	return n * cube.Value
+}
+
+func chequers(side [25]int) int {
+	var c int
+	for _, n := range side {
+		c += n
+	}
+	return c
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,82 @@
+package tournament
+
+import (
+	"bgweb-api/internal/gnubg"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ParsePlayer reads an engine configuration from a comma separated list of
+// settings, e.g. "plies=2,filter=large,noise=0.02,weights=my.weights".
+// Flags (cubeful, prune, deterministic, quantized) can be given as
+// "prune" or "prune=false". Unless given, players are cubeful, prune, add
+// deterministic noise and evaluate at 0 plies with the normal filter; the
+// name defaults to the spec itself.
+func ParsePlayer(spec string) (Player, error) {
+	var p = Player{
+		Name: spec,
+		Settings: gnubg.Settings{
+			Cubeful:       true,
+			Prune:         true,
+			Deterministic: true,
+		},
+	}
+
+	if spec == "" {
+		p.Name = "default"
+		return p, nil
+	}
+
+	for _, kv := range strings.Split(spec, ",") {
+		k, v, hasValue := strings.Cut(kv, "=")
+
+		var err error
+		var flag = true
+		if hasValue {
+			flag, err = strconv.ParseBool(v)
+		}
+
+		switch k {
+		case "name":
+			p.Name, err = v, nil
+		case "plies":
+			p.Settings.Plies, err = strconv.Atoi(v)
+		case "filter":
+			p.Settings.Filter, err = v, nil
+		case "noise":
+			var r float64
+			r, err = strconv.ParseFloat(v, 32)
+			p.Settings.Noise = float32(r)
+		case "weights":
+			p.Settings.Weights, err = loadWeights(v)
+		case "cubeful":
+			p.Settings.Cubeful = flag
+		case "prune":
+			p.Settings.Prune = flag
+		case "deterministic":
+			p.Settings.Deterministic = flag
+		case "quantized":
+			p.Settings.Quantized = flag
+		default:
+			return p, fmt.Errorf("unknown setting: %v", k)
+		}
+
+		if err != nil {
+			return p, fmt.Errorf("invalid %v: %v", k, err)
+		}
+	}
+
+	return p, nil
+}
+
+func loadWeights(path string) (*gnubg.Weights, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return gnubg.LoadWeights(f, path)
+}
@@ -0,0 +1,237 @@
+// Package tournament plays engine configurations against each other to
+// measure which one plays better.
+package tournament
+
+import (
+	"bgweb-api/internal/gnubg"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// Player is an engine configuration taking part in a tournament.
+type Player struct {
+	Name     string
+	Settings gnubg.Settings
+}
+
+// Config holds the settings of a tournament.
+type Config struct {
+	// Games is the number of money games, or matches when MatchTo is set.
+	// With Duplicate it is rounded up to an even number.
+	Games   int
+	MatchTo int
+	Jacoby  bool // money play only
+
+	// Seed makes the dice, and so the whole tournament, reproducible.
+	Seed int64
+	// Duplicate plays every game twice with the same dice, the players
+	// swapping seats, which takes most of the luck out of the result.
+	Duplicate bool
+	// Parallel is the number of games played at the same time.
+	Parallel int
+
+	// Reference, if set, judges every decision of both players to work out
+	// their error rates.
+	Reference *gnubg.Settings
+
+	// Progress, if set, is called after each game (or pair of games) with
+	// the number of games done.
+	Progress func(done int)
+}
+
+// Result is the outcome of a tournament, from the first player's point of
+// view.
+type Result struct {
+	Players   [2]string `json:"players"`
+	Games     int       `json:"games"`
+	MatchTo   int       `json:"match-to"`
+	Seed      int64     `json:"seed"`
+	Duplicate bool      `json:"duplicate"`
+
+	// points per game won by the first player in money play; in match play
+	// +1 is a match won and -1 a match lost
+	PointsPerGame float64    `json:"points-per-game"`
+	StdErr        float64    `json:"std-err"`
+	CI95          [2]float64 `json:"ci95"`
+
+	// games won, and those of them scored as gammons or backgammons
+	Wins        [2]int `json:"wins"`
+	Gammons     [2]int `json:"gammons"`
+	Backgammons [2]int `json:"backgammons"`
+
+	// only when a reference is set
+	Errors *[2]ErrorRate `json:"errors,omitempty"`
+
+	GameResults []GameResult `json:"game-results"`
+}
+
+// ErrorRate sums up the equity a player lost, as judged by the reference.
+type ErrorRate struct {
+	ChequerDecisions int     `json:"chequer-decisions"`
+	ChequerLoss      float64 `json:"chequer-loss"`
+	CubeDecisions    int     `json:"cube-decisions"`
+	CubeLoss         float64 `json:"cube-loss"`
+	// mean equity lost per decision, in thousandths of a point
+	Rate float64 `json:"rate"`
+}
+
+// GameResult is a single game or match.
+type GameResult struct {
+	Seed  int64 `json:"seed"`
+	First int   `json:"first"` // seat of the first player, 0 or 1
+	// points won by the first player, or +1/-1 for a match
+	Points int `json:"points"`
+	Games  int `json:"games"` // games in the match, 1 in money play
+	Turns  int `json:"turns"`
+}
+
+// Run plays the tournament.
+func Run(players [2]Player, cfg Config) (Result, error) {
+	var ret = Result{
+		Players:   [2]string{players[0].Name, players[1].Name},
+		MatchTo:   cfg.MatchTo,
+		Seed:      cfg.Seed,
+		Duplicate: cfg.Duplicate,
+	}
+
+	var perUnit = 1
+	if cfg.Duplicate {
+		perUnit = 2
+	}
+	var cUnits = (cfg.Games + perUnit - 1) / perUnit
+
+	var parallel = cfg.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var units = make([][]game, cUnits)
+	var aErr = make([]error, cUnits)
+	var ch = make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var done int
+
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for iUnit := range ch {
+				units[iUnit], aErr[iUnit] = playUnit(players, cfg, cfg.Seed+int64(iUnit), iUnit)
+				if cfg.Progress != nil {
+					mu.Lock()
+					done += perUnit
+					cfg.Progress(done)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := 0; i < cUnits; i++ {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+
+	for _, err := range aErr {
+		if err != nil {
+			return ret, err
+		}
+	}
+
+	var errs [2]ErrorRate
+	var totals = make([]float64, cUnits)
+
+	for i, unit := range units {
+		for _, g := range unit {
+			ret.GameResults = append(ret.GameResults, g.result)
+			ret.Games++
+			totals[i] += float64(g.result.Points)
+
+			for seat := 0; seat < 2; seat++ {
+				p := seat
+				if g.result.First == 1 {
+					p = 1 - seat
+				}
+				ret.Wins[p] += g.wins[seat]
+				ret.Gammons[p] += g.gammons[seat]
+				ret.Backgammons[p] += g.backgammons[seat]
+				errs[p].add(g.errs[seat])
+			}
+		}
+	}
+
+	/* the duplicate pairs (or single games) are independent, so the
+	 * spread of their totals gives the error of the mean */
+	var mean, variance float64
+	for _, t := range totals {
+		mean += t
+	}
+	mean /= float64(cUnits)
+	for _, t := range totals {
+		variance += (t - mean) * (t - mean)
+	}
+	if cUnits > 1 {
+		variance /= float64(cUnits - 1)
+	}
+
+	ret.PointsPerGame = mean / float64(perUnit)
+	ret.StdErr = math.Sqrt(variance/float64(cUnits)) / float64(perUnit)
+	ret.CI95 = [2]float64{ret.PointsPerGame - 1.96*ret.StdErr, ret.PointsPerGame + 1.96*ret.StdErr}
+
+	if cfg.Reference != nil {
+		for i := range errs {
+			errs[i].finish()
+		}
+		ret.Errors = &errs
+	}
+
+	return ret, nil
+}
+
+func (e *ErrorRate) add(o ErrorRate) {
+	e.ChequerDecisions += o.ChequerDecisions
+	e.ChequerLoss += o.ChequerLoss
+	e.CubeDecisions += o.CubeDecisions
+	e.CubeLoss += o.CubeLoss
+}
+
+func (e *ErrorRate) finish() {
+	if n := e.ChequerDecisions + e.CubeDecisions; n > 0 {
+		e.Rate = 1000 * (e.ChequerLoss + e.CubeLoss) / float64(n)
+	}
+}
+
+// play one game or match, or two with duplicate dice
+func playUnit(players [2]Player, cfg Config, seed int64, iUnit int) ([]game, error) {
+	var ret []game
+
+	var firsts = []int{iUnit % 2}
+	if cfg.Duplicate {
+		firsts = []int{0, 1}
+	}
+
+	for _, first := range firsts {
+		var seats = [2]*Player{&players[0], &players[1]}
+		if first == 1 {
+			seats = [2]*Player{&players[1], &players[0]}
+		}
+
+		g, err := playMatch(seats, cfg, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			return nil, fmt.Errorf("game %v: %v", seed, err)
+		}
+
+		g.result.Seed = seed
+		g.result.First = first
+		if first == 1 {
+			g.result.Points = -g.result.Points
+		}
+
+		ret = append(ret, g)
+	}
+
+	return ret, nil
+}
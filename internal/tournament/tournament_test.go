@@ -0,0 +1,150 @@
+package tournament
+
+import (
+	"bgweb-api/internal/gnubg"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var once sync.Once
+
+func setup() {
+	if err := gnubg.Init(os.DirFS("../../cmd/bgweb-api/data")); err != nil {
+		panic(err)
+	}
+}
+
+func TestParsePlayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    Player
+		wantErr bool
+	}{
+		{
+			name: "should use defaults",
+			spec: "",
+			want: Player{Name: "default", Settings: gnubg.Settings{Cubeful: true, Prune: true, Deterministic: true}},
+		},
+		{
+			name: "should parse settings",
+			spec: "plies=2,filter=large,noise=0.5,prune=false,quantized,name=big",
+			want: Player{Name: "big", Settings: gnubg.Settings{Plies: 2, Filter: "large", Noise: 0.5, Cubeful: true, Deterministic: true, Quantized: true}},
+		},
+		{
+			name: "should name the player after the spec",
+			spec: "plies=1",
+			want: Player{Name: "plies=1", Settings: gnubg.Settings{Plies: 1, Cubeful: true, Prune: true, Deterministic: true}},
+		},
+		{
+			name:    "should fail on unknown settings",
+			spec:    "depth=2",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on bad values",
+			spec:    "plies=two",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on missing weights",
+			spec:    "weights=does-not-exist.weights",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlayer(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePlayer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePlayer() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	once.Do(setup)
+
+	var players = [2]Player{{Name: "a"}, {Name: "b"}}
+	for i := range players {
+		players[i].Settings = gnubg.Settings{Cubeful: true, Prune: true, Deterministic: true}
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"money", Config{Games: 4, Seed: 1, Duplicate: true, Parallel: 2, Reference: &players[0].Settings}},
+		{"match", Config{Games: 1, MatchTo: 3, Seed: 1, Parallel: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Run(players, tt.cfg)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if got.Games != tt.cfg.Games || len(got.GameResults) != tt.cfg.Games {
+				t.Errorf("Run() played %v games, want %v", got.Games, tt.cfg.Games)
+			}
+
+			if tt.cfg.Duplicate {
+				/* identical players with the same dice cancel out */
+				if got.PointsPerGame != 0 {
+					t.Errorf("Run() points per game = %v, want 0", got.PointsPerGame)
+				}
+				/* and make no errors against themselves */
+				for _, e := range got.Errors {
+					if e.Rate != 0 || e.ChequerDecisions == 0 {
+						t.Errorf("Run() errors = %+v, want none", e)
+					}
+				}
+			}
+
+			if tt.cfg.MatchTo > 0 {
+				if p := got.GameResults[0].Points; p != 1 && p != -1 {
+					t.Errorf("Run() match points = %v, want ±1", p)
+				}
+			}
+
+			again, err := Run(players, tt.cfg)
+			if err != nil || !reflect.DeepEqual(again, got) {
+				t.Errorf("Run() not reproducible: %+v, %v", again, err)
+			}
+		})
+	}
+}
+
+func TestGameScore(t *testing.T) {
+	/* the loser, seen from the winner's opponent, with none borne off and
+	 * one chequer in the winner's home board */
+	var backgammon = gnubg.TanBoard{{12: 14, 20: 1}}
+
+	tests := []struct {
+		name        string
+		cube        gnubg.Cube
+		want        int
+		gammons     int
+		backgammons int
+	}{
+		{"should score a backgammon", gnubg.Cube{Value: 2, Owner: 0}, 6, 1, 1},
+		{"should score a single game under Jacoby", gnubg.Cube{Value: 1, Owner: -1, Jacoby: true}, 1, 0, 0},
+		{"should score a backgammon once the cube is turned", gnubg.Cube{Value: 2, Owner: 1, Jacoby: true}, 6, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var g game
+			if got := g.score(1, backgammon, tt.cube); got != tt.want {
+				t.Errorf("game.score() = %v, want %v", got, tt.want)
+			}
+			if g.wins[1] != 1 || g.gammons[1] != tt.gammons || g.backgammons[1] != tt.backgammons {
+				t.Errorf("game.score() counted %v wins, %v gammons and %v backgammons, want 1, %v and %v", g.wins[1], g.gammons[1], g.backgammons[1], tt.gammons, tt.backgammons)
+			}
+		})
+	}
+}
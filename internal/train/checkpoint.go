@@ -0,0 +1,105 @@
+package train
+
+import (
+	"bgweb-api/internal/gnubg"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const checkpointFile = "checkpoint.json"
+
+// Checkpointer saves the nets in Dir every Every steps (epochs or games),
+// as numbered weights files plus a checkpoint.json pointing at the latest.
+type Checkpointer struct {
+	Dir   string
+	Every int
+}
+
+type checkpoint struct {
+	Step      int    `json:"step"`
+	Weights   string `json:"weights"`
+	Benchmark Stats  `json:"benchmark"`
+}
+
+// Save writes the nets for the given step and makes them the latest
+// checkpoint.
+func (c *Checkpointer) Save(w *gnubg.Weights, step int, s Stats) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("gnubg-%08d.weights", step)
+
+	if err := SaveWeights(w, filepath.Join(c.Dir, name)); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(checkpoint{step, name, s}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	/* replace the pointer atomically, so an interrupted run always leaves
+	 * a usable checkpoint */
+	tmp := filepath.Join(c.Dir, checkpointFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(c.Dir, checkpointFile))
+}
+
+func (c *Checkpointer) maybeSave(w *gnubg.Weights, step int, s Stats) error {
+	if c == nil || c.Every <= 0 || step%c.Every != 0 {
+		return nil
+	}
+	return c.Save(w, step, s)
+}
+
+// LoadCheckpoint returns the latest nets saved in dir and their step.
+func LoadCheckpoint(dir string) (*gnubg.Weights, int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, checkpointFile))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var c checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, 0, fmt.Errorf("invalid checkpoint: %v", err)
+	}
+
+	w, err := LoadWeights(filepath.Join(dir, c.Weights))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return w, c.Step, nil
+}
+
+// LoadWeights reads a gnubg.weights file.
+func LoadWeights(path string) (*gnubg.Weights, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return gnubg.LoadWeights(f, path)
+}
+
+// SaveWeights writes the nets to a gnubg.weights file.
+func SaveWeights(w *gnubg.Weights, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := w.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,147 @@
+package train
+
+import (
+	"bgweb-api/internal/gnubg"
+	"math/rand"
+)
+
+// Starting positions, seen from the player on roll.
+var (
+	StartStandard = gnubg.TanBoard{
+		{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+		{0, 0, 0, 0, 0, 5, 0, 3, 0, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+	}
+	StartNackgammon = gnubg.TanBoard{
+		{0, 0, 0, 0, 0, 4, 0, 3, 0, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 2, 0},
+		{0, 0, 0, 0, 0, 4, 0, 3, 0, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 2, 0},
+	}
+)
+
+// a game longer than this is abandoned (the nets are still untrained
+// enough to shuffle chequers back and forth)
+const maxGameMoves = 2000
+
+// TDConfig holds the settings of TD.
+type TDConfig struct {
+	Alpha   float32 // learning rate
+	Lambda  float32 // trace decay; 0 learns from the next position only, 1 from the game result only
+	Epsilon float64 // chance of playing a random move instead of the best one
+	Games   int
+	Start   gnubg.TanBoard
+	Rand    *rand.Rand // dice and exploration
+
+	// Benchmark examples, checked every BenchmarkEvery games (and after
+	// the last one) and passed to Progress.
+	Benchmark      []Example
+	BenchmarkEvery int
+	Progress       func(game int, s Stats)
+	// Checkpoint, if set, saves the nets every Checkpoint.Every games.
+	Checkpoint *Checkpointer
+	// Done is the number of games already played, when resuming.
+	Done int
+}
+
+// TD trains the nets by self-play with TD(λ). Each game is played with the
+// current nets, choosing the play with the best 0-ply cubeless equity,
+// after which every position is trained towards its λ-return.
+func TD(w *gnubg.Weights, cfg TDConfig) error {
+	for game := cfg.Done + 1; game <= cfg.Games; game++ {
+		positions := SelfPlay(w, cfg.Start, cfg.Epsilon, cfg.Rand)
+
+		trainLambda(w, positions, cfg.Alpha, cfg.Lambda)
+
+		if cfg.Progress != nil && (game == cfg.Games || (cfg.BenchmarkEvery > 0 && game%cfg.BenchmarkEvery == 0)) {
+			cfg.Progress(game, Benchmark(w, cfg.Benchmark))
+		}
+
+		if cfg.Checkpoint != nil && cfg.Checkpoint.Every > 0 && game%cfg.Checkpoint.Every == 0 {
+			if err := cfg.Checkpoint.Save(w, game, Benchmark(w, cfg.Benchmark)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SelfPlay plays a game with the nets and returns every position in it,
+// each seen from the player on roll, ending with the final one.
+func SelfPlay(w *gnubg.Weights, start gnubg.TanBoard, epsilon float64, rnd *rand.Rand) []gnubg.TanBoard {
+	var board = start
+	var ret = []gnubg.TanBoard{board}
+
+	for n := 0; n < maxGameMoves && !gameOver(board); n++ {
+		dice := [2]int{rnd.Intn(6) + 1, rnd.Intn(6) + 1}
+
+		if plays := w.Plays(board, dice); len(plays) > 0 {
+			board = choosePlay(w, plays, epsilon, rnd)
+		}
+
+		board = gnubg.SwapSides(board)
+		ret = append(ret, board)
+	}
+
+	return ret
+}
+
+func choosePlay(w *gnubg.Weights, plays []gnubg.TanBoard, epsilon float64, rnd *rand.Rand) gnubg.TanBoard {
+	if rnd.Float64() < epsilon {
+		return plays[rnd.Intn(len(plays))]
+	}
+
+	var iBest int
+	var rBest float32
+
+	for i, p := range plays {
+		/* the opponent is on roll after the play */
+		r := -Equity(w.Evaluate(gnubg.SwapSides(p)))
+
+		if i == 0 || r > rBest {
+			iBest, rBest = i, r
+		}
+	}
+
+	return plays[iBest]
+}
+
+func gameOver(board gnubg.TanBoard) bool {
+	for side := 0; side < 2; side++ {
+		var c int
+		for _, n := range board[side] {
+			c += n
+		}
+		if c == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Train every position but the last towards its λ-return,
+//
+//	G(t) = (1-λ) V(t+1) + λ G(t+1),  G(T) = V(T),
+//
+// where the values alternate between the players' points of view. The
+// values are all taken before any training, so the game is learned from
+// as it was played.
+func trainLambda(w *gnubg.Weights, positions []gnubg.TanBoard, rAlpha float32, rLambda float32) {
+	var T = len(positions) - 1
+
+	var values = make([][5]float32, len(positions))
+	for t, p := range positions {
+		values[t] = w.Evaluate(p)
+	}
+
+	var g = values[T]
+
+	for t := T - 1; t >= 0; t-- {
+		next := Invert(values[t+1])
+		g = Invert(g)
+
+		for i := range g {
+			g[i] = (1-rLambda)*next[i] + rLambda*g[i]
+		}
+
+		w.Train(positions[t], g, rAlpha)
+	}
+}
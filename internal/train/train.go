@@ -0,0 +1,163 @@
+// Package train trains the gnubg neural nets, either supervised from
+// positions with known chances (e.g. rollout results) or by TD(λ)
+// self-play.
+package train
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/layout"
+	"bgweb-api/internal/openapi"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// Example is a position with the chances the nets should learn for it.
+// The board is seen from the player on roll, see gnubg.Weights.
+type Example struct {
+	Board  gnubg.TanBoard
+	Target [5]float32
+}
+
+type exampleLine struct {
+	Board  openapi.Board       `json:"board"`
+	Player string              `json:"player"`
+	Target openapi.Probability `json:"target"`
+}
+
+// ReadExamples reads one example per line, as JSON with the "board" and
+// "player" of a /getmoves request and the "target" chances of the player
+// on roll in the form of a move evaluation's "probability" ("lose" is
+// ignored).
+func ReadExamples(r io.Reader) ([]Example, error) {
+	var ret []Example
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var l exampleLine
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return nil, fmt.Errorf("line %v: %v", line, err)
+		}
+
+		board := layout.TanBoard(l.Board)
+		if l.Player == "x" {
+			board = gnubg.SwapSides(board)
+		}
+
+		ret = append(ret, Example{
+			Board:  board,
+			Target: [5]float32{l.Target.Win, l.Target.WinG, l.Target.WinBG, l.Target.LoseG, l.Target.LoseBG},
+		})
+	}
+
+	return ret, scanner.Err()
+}
+
+// Stats measures how far the nets are from the targets of a set of
+// examples.
+type Stats struct {
+	N           int     `json:"n"`
+	MSE         float64 `json:"mse"`          // mean squared error per output
+	EquityError float64 `json:"equity-error"` // mean absolute error of the cubeless equity
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("n=%v mse=%.6f equity-error=%.4f", s.N, s.MSE, s.EquityError)
+}
+
+// Benchmark evaluates the examples with the nets and compares them to the
+// targets. The examples are not trained on.
+func Benchmark(w *gnubg.Weights, examples []Example) Stats {
+	var s Stats
+
+	for _, e := range examples {
+		ar := w.Evaluate(e.Board)
+
+		for i := range ar {
+			d := float64(ar[i] - e.Target[i])
+			s.MSE += d * d
+		}
+		s.EquityError += math.Abs(float64(Equity(ar) - Equity(e.Target)))
+		s.N++
+	}
+
+	if s.N > 0 {
+		s.MSE /= float64(s.N * 5)
+		s.EquityError /= float64(s.N)
+	}
+
+	return s
+}
+
+// Equity is the cubeless money equity of a set of chances.
+func Equity(ar [5]float32) float32 {
+	return 2*ar[0] - 1 + ar[1] - ar[3] + ar[2] - ar[4]
+}
+
+// Invert turns chances around to the other player's point of view.
+func Invert(ar [5]float32) [5]float32 {
+	return [5]float32{1 - ar[0], ar[3], ar[4], ar[1], ar[2]}
+}
+
+// SupervisedConfig holds the settings of Supervised.
+type SupervisedConfig struct {
+	Alpha  float32 // learning rate
+	Epochs int
+	Rand   *rand.Rand // shuffles the examples every epoch
+
+	// Progress, if set, is called after every epoch with the benchmark
+	// result on the held-out examples.
+	Progress func(epoch int, s Stats)
+	// Checkpoint, if set, saves the nets every Checkpoint.Every epochs.
+	Checkpoint *Checkpointer
+	// Start is the number of epochs already done, when resuming.
+	Start int
+}
+
+// Supervised trains the nets on the examples for a number of epochs,
+// benchmarking against the held-out ones after each.
+func Supervised(w *gnubg.Weights, examples []Example, heldOut []Example, cfg SupervisedConfig) error {
+	var order = make([]int, len(examples))
+	for i := range order {
+		order[i] = i
+	}
+
+	for epoch := cfg.Start + 1; epoch <= cfg.Epochs; epoch++ {
+		cfg.Rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		for _, i := range order {
+			w.Train(examples[i].Board, examples[i].Target, cfg.Alpha)
+		}
+
+		s := Benchmark(w, heldOut)
+
+		if cfg.Progress != nil {
+			cfg.Progress(epoch, s)
+		}
+
+		if err := cfg.Checkpoint.maybeSave(w, epoch, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Split shuffles the examples and holds back a fraction of them for
+// benchmarking.
+func Split(examples []Example, heldOut float64, rnd *rand.Rand) ([]Example, []Example) {
+	rnd.Shuffle(len(examples), func(i, j int) { examples[i], examples[j] = examples[j], examples[i] })
+
+	n := int(float64(len(examples)) * heldOut)
+
+	return examples[n:], examples[:n]
+}
@@ -0,0 +1,144 @@
+package train
+
+import (
+	"bgweb-api/internal/gnubg"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var once sync.Once
+
+func setup() {
+	if err := gnubg.Init(os.DirFS("../../cmd/bgweb-api/data")); err != nil {
+		panic(err)
+	}
+}
+
+const examplesFile = `{"board":{"x":{"6":5,"8":3,"13":5,"24":2},"o":{"6":5,"8":3,"13":5,"24":2}},"player":"x","target":{"win":0.5,"winG":0.14,"winBG":0.01,"loseG":0.14,"loseBG":0.01}}
+
+{"board":{"x":{"7":5,"8":5,"9":5},"o":{"7":5,"8":5,"9":5}},"player":"o","target":{"win":0.3,"winG":0,"winBG":0,"loseG":0,"loseBG":0}}
+`
+
+func TestReadExamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Example
+		wantErr bool
+	}{
+		{
+			name: "should read examples",
+			data: examplesFile,
+			want: []Example{
+				{Board: StartStandard, Target: [5]float32{0.5, 0.14, 0.01, 0.14, 0.01}},
+				{
+					Board: gnubg.TanBoard{
+						{0, 0, 0, 0, 0, 0, 5, 5, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+						{0, 0, 0, 0, 0, 0, 5, 5, 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+					},
+					Target: [5]float32{0.3, 0, 0, 0, 0},
+				},
+			},
+		},
+		{
+			name:    "should fail on bad json",
+			data:    "{\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadExamples(strings.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadExamples() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ReadExamples() = %v examples, want %v", len(got), len(tt.want))
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("ReadExamples()[%v] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSupervised(t *testing.T) {
+	once.Do(setup)
+
+	examples, err := ReadExamples(strings.NewReader(examplesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	w := gnubg.NewWeights(8, rnd)
+	before := Benchmark(w, examples)
+
+	var epochs int
+	err = Supervised(w, examples, examples, SupervisedConfig{
+		Alpha:    0.1,
+		Epochs:   200,
+		Rand:     rnd,
+		Progress: func(int, Stats) { epochs++ },
+	})
+	if err != nil {
+		t.Fatalf("Supervised() error = %v", err)
+	}
+
+	after := Benchmark(w, examples)
+
+	if epochs != 200 {
+		t.Errorf("Supervised() reported %v epochs, want 200", epochs)
+	}
+	if after.MSE >= before.MSE/10 {
+		t.Errorf("Supervised() mse = %v, started at %v", after.MSE, before.MSE)
+	}
+}
+
+func TestTD(t *testing.T) {
+	once.Do(setup)
+
+	rnd := rand.New(rand.NewSource(1))
+	w := gnubg.NewWeights(8, rnd)
+
+	game := SelfPlay(w, StartStandard, 0, rnd)
+	if !gameOver(game[len(game)-1]) {
+		t.Fatalf("SelfPlay() didn't finish in %v positions", len(game))
+	}
+
+	dir := t.TempDir()
+
+	var games int
+	err := TD(w, TDConfig{
+		Alpha:          0.1,
+		Lambda:         0.7,
+		Games:          4,
+		Start:          StartStandard,
+		Rand:           rnd,
+		BenchmarkEvery: 2,
+		Progress:       func(int, Stats) { games++ },
+		Checkpoint:     &Checkpointer{Dir: dir, Every: 2},
+	})
+	if err != nil {
+		t.Fatalf("TD() error = %v", err)
+	}
+	if games != 2 {
+		t.Errorf("TD() reported progress %v times, want 2", games)
+	}
+
+	w2, step, err := LoadCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if step != 4 {
+		t.Errorf("LoadCheckpoint() step = %v, want 4", step)
+	}
+	if got, want := w2.Evaluate(StartStandard), w.Evaluate(StartStandard); got != want {
+		t.Errorf("LoadCheckpoint() evaluates %v, want %v", got, want)
+	}
+}
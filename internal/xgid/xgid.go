@@ -0,0 +1,218 @@
+// Package xgid reads and writes eXtreme Gammon position IDs:
+//
+//	XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:0:0:10
+//
+// The first field is the board: the bar of the top player, points 1 to 24
+// of the bottom player, then their bar, with 'A' to 'O' for 1 to 15
+// chequers of the bottom player and 'a' to 'o' for those of the top one.
+// Then follow the cube as a power of 2, its owner (1 bottom, -1 top, 0
+// centred), the player to act, the dice ("00" before rolling, "D" while a
+// double is pending), the scores of the bottom and top players, the
+// Crawford game in a match or the Jacoby and beaver rules in money play,
+// the match length (0 for money) and the highest cube as a power of 2.
+//
+// The bottom player is x and the top player o.
+package xgid
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/layout"
+	"bgweb-api/internal/openapi"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// XGID is a position with its match context.
+type XGID struct {
+	Board openapi.Board
+	// player 1 is x and player 0 is o, as in a gnubg match ID
+	Match gnubg.MatchState
+	// highest cube value, as a power of 2
+	MaxCube int
+}
+
+const prefix = "XGID="
+
+// DefaultMaxCube is the cube limit of XGIDs leaving it out.
+const DefaultMaxCube = 10
+
+// Parse reads an XGID, with or without its "XGID=" prefix.
+func Parse(s string) (XGID, error) {
+	var ret = XGID{MaxCube: DefaultMaxCube}
+
+	var fields = strings.Split(strings.TrimPrefix(strings.TrimSpace(s), prefix), ":")
+	if len(fields) != 9 && len(fields) != 10 {
+		return ret, fmt.Errorf("an XGID has 10 fields, not %v", len(fields))
+	}
+
+	board, err := parseBoard(fields[0])
+	if err != nil {
+		return ret, err
+	}
+	ret.Board = board
+
+	var an [9]int
+	for i, f := range fields[1:] {
+		if i == 3 {
+			/* the dice */
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return ret, fmt.Errorf("invalid XGID field %v: %q", i+2, f)
+		}
+		an[i] = n
+	}
+	var logCube, cubePos, turn, crawJacoby, matchTo = an[0], an[1], an[2], an[6], an[7]
+	if len(fields) == 10 {
+		ret.MaxCube = an[8]
+	}
+
+	var ms = &ret.Match
+	ms.GameState = 1
+
+	switch {
+	case logCube < 0 || logCube > 15:
+		return ret, fmt.Errorf("invalid XGID cube: %v", logCube)
+	case cubePos < -1 || cubePos > 1:
+		return ret, fmt.Errorf("invalid XGID cube owner: %v", cubePos)
+	case turn != 1 && turn != -1:
+		return ret, fmt.Errorf("invalid XGID turn: %v", turn)
+	case matchTo < 0:
+		return ret, fmt.Errorf("invalid XGID match length: %v", matchTo)
+	case an[4] < 0 || an[5] < 0 || (matchTo > 0 && (an[4] >= matchTo || an[5] >= matchTo)):
+		return ret, fmt.Errorf("invalid XGID score: %v-%v", an[4], an[5])
+	case ret.MaxCube < logCube || ret.MaxCube > 15:
+		return ret, fmt.Errorf("invalid XGID cube limit: %v", ret.MaxCube)
+	}
+
+	ms.Cube.Value = 1 << logCube
+	ms.Cube.Owner = fromXGPlayer(cubePos)
+	ms.Turn = fromXGPlayer(turn)
+	ms.Move = ms.Turn
+	ms.Cube.MatchTo = matchTo
+	/* S1 is the bottom player's */
+	ms.Cube.Score = [2]int{an[5], an[4]}
+	if matchTo > 0 {
+		ms.Cube.Crawford = crawJacoby&1 != 0
+	} else {
+		ms.Cube.Jacoby = crawJacoby&1 != 0
+		ms.Cube.Beavers = crawJacoby&2 != 0
+	}
+
+	switch dice := fields[4]; {
+	case dice == "D":
+		/* the player to act answers the double */
+		ms.Doubled = true
+		ms.Move = 1 - ms.Turn
+	case dice == "B" || dice == "R":
+		return ret, fmt.Errorf("beavered cubes are not supported")
+	case len(dice) != 2 || dice[0] < '0' || dice[0] > '6' || dice[1] < '0' || dice[1] > '6' || (dice[0] == '0') != (dice[1] == '0'):
+		return ret, fmt.Errorf("invalid XGID dice: %q", dice)
+	default:
+		ms.Dice = [2]int{int(dice[0] - '0'), int(dice[1] - '0')}
+	}
+
+	return ret, nil
+}
+
+func parseBoard(s string) (openapi.Board, error) {
+	if len(s) != 26 {
+		return openapi.Board{}, fmt.Errorf("an XGID board has 26 characters")
+	}
+
+	var anX, anO [25]int
+	var cX, cO int
+	for i := 0; i < 26; i++ {
+		switch c := s[i]; {
+		case c == '-':
+		case c >= 'A' && c <= 'O' && i > 0:
+			anX[i-1] = int(c-'A') + 1
+			cX += anX[i-1]
+		case c >= 'a' && c <= 'o' && i < 25:
+			/* the top player counts from the other side */
+			anO[24-i] = int(c-'a') + 1
+			cO += anO[24-i]
+		default:
+			return openapi.Board{}, fmt.Errorf("invalid XGID board: %q at %v", c, i)
+		}
+	}
+	if cX > 15 || cO > 15 {
+		return openapi.Board{}, fmt.Errorf("invalid XGID board: more than 15 chequers")
+	}
+
+	return openapi.Board{X: layout.CheckerLayout(anX), O: layout.CheckerLayout(anO)}, nil
+}
+
+// String formats the XGID with its "XGID=" prefix.
+func (x XGID) String() string {
+	var ms = x.Match
+
+	var dice = fmt.Sprintf("%d%d", ms.Dice[0], ms.Dice[1])
+	if ms.Doubled {
+		dice = "D"
+	}
+
+	var crawJacoby int
+	if ms.Cube.MatchTo > 0 {
+		crawJacoby = toInt(ms.Cube.Crawford)
+	} else {
+		crawJacoby = toInt(ms.Cube.Jacoby) | toInt(ms.Cube.Beavers)<<1
+	}
+
+	var logCube int
+	for n := ms.Cube.Value >> 1; n > 0; n >>= 1 {
+		logCube++
+	}
+
+	return fmt.Sprintf("%v%v:%v:%v:%v:%v:%v:%v:%v:%v:%v", prefix, formatBoard(x.Board),
+		logCube, toXGPlayer(ms.Cube.Owner), toXGPlayer(ms.Turn), dice,
+		ms.Cube.Score[1], ms.Cube.Score[0], crawJacoby, ms.Cube.MatchTo, x.MaxCube)
+}
+
+func formatBoard(board openapi.Board) string {
+	var anX, anO = layout.Points(board.X), layout.Points(board.O)
+	var ret [26]byte
+
+	for i := range ret {
+		ret[i] = '-'
+		switch {
+		case i > 0 && anX[i-1] > 0:
+			ret[i] = 'A' + byte(anX[i-1]-1)
+		case i < 25 && anO[24-i] > 0:
+			ret[i] = 'a' + byte(anO[24-i]-1)
+		}
+	}
+
+	return string(ret[:])
+}
+
+/* x, the bottom player, is 1 in both; centred is 0 in an XGID and -1 in
+ * gnubg */
+func fromXGPlayer(n int) int {
+	switch n {
+	case 1:
+		return 1
+	case -1:
+		return 0
+	}
+	return -1
+}
+
+func toXGPlayer(n int) int {
+	switch n {
+	case 1:
+		return 1
+	case 0:
+		return -1
+	}
+	return 0
+}
+
+func toInt(f bool) int {
+	if f {
+		return 1
+	}
+	return 0
+}
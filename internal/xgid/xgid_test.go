@@ -0,0 +1,143 @@
+package xgid
+
+import (
+	"bgweb-api/internal/gnubg"
+	"bgweb-api/internal/layout"
+	"bgweb-api/internal/openapi"
+	"reflect"
+	"testing"
+)
+
+func chequers(points map[int]int) openapi.CheckerLayout {
+	var an [25]int
+	for i, n := range points {
+		an[i-1] = n
+	}
+	return layout.CheckerLayout(an)
+}
+
+var start = openapi.Board{
+	X: chequers(map[int]int{6: 5, 8: 3, 13: 5, 24: 2}),
+	O: chequers(map[int]int{6: 5, 8: 3, 13: 5, 24: 2}),
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    XGID
+		wantErr bool
+	}{
+		{
+			name: "should parse the starting position",
+			s:    "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:0:0:10",
+			want: XGID{
+				Board:   start,
+				Match:   gnubg.MatchState{Cube: gnubg.Cube{Value: 1, Owner: -1}, Move: 1, Turn: 1, GameState: 1},
+				MaxCube: 10,
+			},
+		},
+		{
+			name: "should parse a match with dice and an owned cube",
+			s:    "XGID=-b----E-C---eE---c-e----B-:1:-1:-1:31:2:4:0:7:10",
+			want: XGID{
+				Board:   start,
+				Match:   gnubg.MatchState{Cube: gnubg.Cube{Value: 2, Owner: 0, MatchTo: 7, Score: [2]int{4, 2}}, Dice: [2]int{3, 1}, GameState: 1},
+				MaxCube: 10,
+			},
+		},
+		{
+			name: "should parse a pending double",
+			s:    "-b----E-C---eE---c-e----B-:0:0:-1:D:0:0:3:0:8",
+			want: XGID{
+				Board:   start,
+				Match:   gnubg.MatchState{Cube: gnubg.Cube{Value: 1, Owner: -1, Jacoby: true, Beavers: true}, Move: 1, Turn: 0, Doubled: true, GameState: 1},
+				MaxCube: 8,
+			},
+		},
+		{
+			name: "should parse chequers on the bar",
+			s:    "XGID=aa---BDBB---a-----bAbbbb-A:1:1:1:42:0:0:1:3:10",
+			want: XGID{
+				Board: openapi.Board{
+					X: chequers(map[int]int{5: 2, 6: 4, 7: 2, 8: 2, 19: 1, 25: 1}),
+					O: chequers(map[int]int{2: 2, 3: 2, 4: 2, 5: 2, 7: 2, 13: 1, 24: 1, 25: 1}),
+				},
+				Match:   gnubg.MatchState{Cube: gnubg.Cube{Value: 2, Owner: 1, MatchTo: 3, Crawford: true}, Move: 1, Turn: 1, Dice: [2]int{4, 2}, GameState: 1},
+				MaxCube: 10,
+			},
+		},
+		{
+			name:    "should fail on a short board",
+			s:       "XGID=-b----E-C---eE---c-e----B:0:0:1:00:0:0:0:0:10",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on too many chequers",
+			s:       "XGID=-b----O-C---eE---c-e----B-:0:0:1:00:0:0:0:0:10",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on x chequers on o's bar",
+			s:       "XGID=Ab----E-C---eE---c-e----A-:0:0:1:00:0:0:0:0:10",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on invalid dice",
+			s:       "XGID=-b----E-C---eE---c-e----B-:0:0:1:70:0:0:0:0:10",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on a beaver",
+			s:       "XGID=-b----E-C---eE---c-e----B-:1:-1:1:B:0:0:3:0:10",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on a score past the match length",
+			s:       "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:5:0:0:5:10",
+			wantErr: true,
+		},
+		{
+			name:    "should fail on missing fields",
+			s:       "XGID=-b----E-C---eE---c-e----B-:0:0:1:00",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXGID_String(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{"should round-trip the starting position", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:0:0:10"},
+		{"should round-trip a money game with the opponent to roll", "XGID=-b----E-C---eE---c-e----B-:0:0:-1:00:0:0:3:0:10"},
+		{"should round-trip a match position", "XGID=-a-B--C-dE---eD---c-b---A-:1:-1:1:63:2:4:0:7:10"},
+		{"should round-trip a pending double", "XGID=-b----E-C---eE---c-e----B-:2:1:-1:D:0:0:1:0:10"},
+		{"should round-trip a Crawford game", "XGID=aa---BDBB---a-----bAbbbb-A:0:0:1:42:4:2:1:5:10"},
+		{"should round-trip a bearoff", "XGID=-AABBB----------------bbb-:3:1:-1:11:0:0:0:0:6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := Parse(tt.s)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := x.String(); got != tt.s {
+				t.Errorf("XGID.String() = %v, want %v", got, tt.s)
+			}
+		})
+	}
+}